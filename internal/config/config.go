@@ -12,22 +12,34 @@ const (
 )
 
 type Config struct {
-	Gateway  Gateway   `yaml:"gateway"`  // gateway config
-	HTTP     HTTP      `yaml:"http"`     // http server config
-	Database Database  `yaml:"database"` // database config
-	FCM      FCMConfig `yaml:"fcm"`      // firebase cloud messaging config
-	SSE      SSE       `yaml:"sse"`      // server-sent events config
-	Messages Messages  `yaml:"messages"` // messages config
-	Cache    Cache     `yaml:"cache"`    // cache (memory or redis) config
-	PubSub   PubSub    `yaml:"pubsub"`   // pubsub (memory or redis) config
-	JWT      JWT       `yaml:"jwt"`      // jwt config
-	OTP      OTP       `yaml:"otp"`      // one-time password config
+	Gateway   Gateway   `yaml:"gateway"`   // gateway config
+	HTTP      HTTP      `yaml:"http"`      // http server config
+	Database  Database  `yaml:"database"`  // database config
+	FCM       FCMConfig `yaml:"fcm"`       // firebase cloud messaging config
+	SSE       SSE       `yaml:"sse"`       // server-sent events config
+	Messages  Messages  `yaml:"messages"`  // messages config
+	Devices   Devices   `yaml:"devices"`   // devices config
+	Campaigns Campaigns `yaml:"campaigns"` // campaigns config
+	Cache     Cache     `yaml:"cache"`     // cache (memory or redis) config
+	PubSub    PubSub    `yaml:"pubsub"`    // pubsub (memory or redis) config
+	Blobs     Blobs     `yaml:"blobs"`     // blob storage config
+	Telemetry Telemetry `yaml:"telemetry"` // device telemetry config
+	JWT       JWT       `yaml:"jwt"`       // jwt config
+	OTP       OTP       `yaml:"otp"`       // one-time password config
 }
 
 type Gateway struct {
 	Mode         GatewayMode `yaml:"mode"          envconfig:"GATEWAY__MODE"`          // gateway mode: public or private
 	PrivateToken string      `yaml:"private_token" envconfig:"GATEWAY__PRIVATE_TOKEN"` // device registration token in private mode
 	UpstreamURL  string      `yaml:"upstream_url"  envconfig:"GATEWAY__UPSTREAM_URL"`  // upstream server URL for private-mode push notifications
+
+	Registration gatewayRegistrationConfig `yaml:"registration"` // device registration policy
+}
+
+type gatewayRegistrationConfig struct {
+	Approval   bool     `yaml:"approval"    envconfig:"GATEWAY__REGISTRATION__APPROVAL"`    // new devices wait for an admin approval in private mode
+	MaxDevices int      `yaml:"max_devices" envconfig:"GATEWAY__REGISTRATION__MAX_DEVICES"` // max devices per user, 0 for unlimited
+	Admins     []string `yaml:"admins"      envconfig:"GATEWAY__REGISTRATION__ADMINS"`      // users allowed to approve devices and manage registration tokens
 }
 
 type HTTP struct {
@@ -75,9 +87,12 @@ type SSE struct {
 }
 
 type Messages struct {
-	CacheTTLSeconds        uint16              `yaml:"cache_ttl_seconds"        envconfig:"MESSAGES__CACHE_TTL_SECONDS"`
-	HashingIntervalSeconds uint16              `yaml:"hashing_interval_seconds" envconfig:"MESSAGES__HASHING_INTERVAL_SECONDS"`
-	Queue                  messagesQueueConfig `yaml:"queue"`
+	CacheTTLSeconds        uint16                `yaml:"cache_ttl_seconds"        envconfig:"MESSAGES__CACHE_TTL_SECONDS"`
+	HashingIntervalSeconds uint16                `yaml:"hashing_interval_seconds" envconfig:"MESSAGES__HASHING_INTERVAL_SECONDS"`
+	Queue                  messagesQueueConfig   `yaml:"queue"`
+	Phone                  messagesPhoneConfig   `yaml:"phone"`
+	ContentPolicy          messagesContentConfig `yaml:"content_policy"`
+	Attachments            messagesAttachments   `yaml:"attachments"`
 }
 
 type messagesQueueConfig struct {
@@ -90,6 +105,36 @@ type messagesQueueConfig struct {
 	StatsCacheTTL        Duration `yaml:"stats_cache_ttl"        envconfig:"MESSAGES__QUEUE__STATS_CACHE_TTL"`
 }
 
+type messagesPhoneConfig struct {
+	DefaultRegion string   `yaml:"default_region" envconfig:"MESSAGES__PHONE__DEFAULT_REGION"` // region for numbers without a country code
+	AllowedTypes  []string `yaml:"allowed_types"  envconfig:"MESSAGES__PHONE__ALLOWED_TYPES"`  // accepted number types
+}
+
+type messagesContentConfig struct {
+	Keywords       []string `yaml:"keywords"        envconfig:"MESSAGES__CONTENT_POLICY__KEYWORDS"`        // blocked keywords
+	Patterns       []string `yaml:"patterns"        envconfig:"MESSAGES__CONTENT_POLICY__PATTERNS"`        // blocked regular expressions
+	AllowedDomains []string `yaml:"allowed_domains" envconfig:"MESSAGES__CONTENT_POLICY__ALLOWED_DOMAINS"` // the only domains links may point to
+	DeniedDomains  []string `yaml:"denied_domains"  envconfig:"MESSAGES__CONTENT_POLICY__DENIED_DOMAINS"`  // domains links must not point to
+	MaxLinks       int      `yaml:"max_links"       envconfig:"MESSAGES__CONTENT_POLICY__MAX_LINKS"`       // max links per message
+	MaxRecipients  int      `yaml:"max_recipients"  envconfig:"MESSAGES__CONTENT_POLICY__MAX_RECIPIENTS"`  // max recipients per message
+	Action         string   `yaml:"action"          envconfig:"MESSAGES__CONTENT_POLICY__ACTION"`          // flag, quarantine or reject
+	Reviewers      []string `yaml:"reviewers"       envconfig:"MESSAGES__CONTENT_POLICY__REVIEWERS"`       // users allowed to review quarantined messages
+}
+
+type messagesAttachments struct {
+	MaxSize      int64    `yaml:"max_size"      envconfig:"MESSAGES__ATTACHMENTS__MAX_SIZE"`      // max size of an MMS attachment in bytes
+	AllowedTypes []string `yaml:"allowed_types" envconfig:"MESSAGES__ATTACHMENTS__ALLOWED_TYPES"` // accepted MIME types
+}
+
+type Devices struct {
+	TokenGracePeriod Duration `yaml:"token_grace_period" envconfig:"DEVICES__TOKEN_GRACE_PERIOD"` // how long a rotated device token stays valid
+}
+
+type Campaigns struct {
+	ExpansionInterval  Duration `yaml:"expansion_interval"   envconfig:"CAMPAIGNS__EXPANSION_INTERVAL"`   // how often messages are generated, one interval ahead
+	ExpansionBatchSize int      `yaml:"expansion_batch_size" envconfig:"CAMPAIGNS__EXPANSION_BATCH_SIZE"` // max messages generated per transaction
+}
+
 type Cache struct {
 	URL string `yaml:"url" envconfig:"CACHE__URL"`
 }
@@ -99,6 +144,16 @@ type PubSub struct {
 	BufferSize uint   `yaml:"buffer_size" envconfig:"PUBSUB__BUFFER_SIZE"`
 }
 
+type Blobs struct {
+	URL string `yaml:"url" envconfig:"BLOBS__URL"`
+}
+
+type Telemetry struct {
+	MaxSamples   int      `yaml:"max_samples"   envconfig:"TELEMETRY__MAX_SAMPLES"`   // samples kept per device
+	MinInterval  Duration `yaml:"min_interval"  envconfig:"TELEMETRY__MIN_INTERVAL"`  // min time between two samples of a device
+	DeviceGauges bool     `yaml:"device_gauges" envconfig:"TELEMETRY__DEVICE_GAUGES"` // export the latest values of every device as gauges
+}
+
 type JWT struct {
 	Secret     string   `yaml:"secret"      envconfig:"JWT__SECRET"`
 	AccessTTL  Duration `yaml:"access_ttl"  envconfig:"JWT__ACCESS_TTL"`
@@ -121,6 +176,11 @@ func Default() Config {
 		Gateway: Gateway{
 			Mode:        GatewayModePublic,
 			UpstreamURL: DefaultUpstreamURL,
+			Registration: gatewayRegistrationConfig{
+				Approval:   false,
+				MaxDevices: 0,
+				Admins:     nil,
+			},
 		},
 		HTTP: HTTP{
 			Listen: ":3000",
@@ -151,6 +211,31 @@ func Default() Config {
 				StatsRefreshInterval: Duration(time.Second * 5),
 				StatsCacheTTL:        Duration(time.Second * 60),
 			},
+			Phone: messagesPhoneConfig{
+				DefaultRegion: "RU",
+				AllowedTypes:  []string{"mobile"},
+			},
+			ContentPolicy: messagesContentConfig{
+				Keywords:       nil,
+				Patterns:       nil,
+				AllowedDomains: nil,
+				DeniedDomains:  nil,
+				MaxLinks:       0,
+				MaxRecipients:  0,
+				Action:         "reject",
+				Reviewers:      nil,
+			},
+			Attachments: messagesAttachments{
+				MaxSize:      1024 * 1024,
+				AllowedTypes: []string{"image/jpeg", "image/png", "image/gif", "application/pdf"},
+			},
+		},
+		Devices: Devices{
+			TokenGracePeriod: Duration(time.Hour),
+		},
+		Campaigns: Campaigns{
+			ExpansionInterval:  Duration(time.Minute),
+			ExpansionBatchSize: 500,
 		},
 		Cache: Cache{
 			URL: "memory://",
@@ -159,6 +244,14 @@ func Default() Config {
 			URL:        "memory://",
 			BufferSize: 128,
 		},
+		Blobs: Blobs{
+			URL: "file://./data/blobs",
+		},
+		Telemetry: Telemetry{
+			MaxSamples:   1440,
+			MinInterval:  Duration(time.Minute),
+			DeviceGauges: false,
+		},
 		JWT: JWT{
 			AccessTTL:  Duration(time.Minute * 15),
 			RefreshTTL: Duration(time.Hour * 24 * 30),
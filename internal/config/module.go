@@ -1,22 +1,30 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/blobs"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/jwt"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/sse"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/transfers"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/otp"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/pubsub"
 	"github.com/capcom6/go-infra-fx/config"
 	"github.com/capcom6/go-infra-fx/db"
 	"github.com/capcom6/go-infra-fx/http"
 	"github.com/go-core-fx/cachefx"
+	"github.com/samber/lo"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -86,6 +94,15 @@ func Module() fx.Option {
 			return auth.Config{
 				Mode:         auth.Mode(cfg.Gateway.Mode),
 				PrivateToken: cfg.Gateway.PrivateToken,
+
+				RegistrationApproval: cfg.Gateway.Registration.Approval,
+				MaxDevices:           cfg.Gateway.Registration.MaxDevices,
+				Admins:               cfg.Gateway.Registration.Admins,
+			}
+		}),
+		fx.Provide(func(cfg Config) transfers.Config {
+			return transfers.Config{
+				MaxDevices: cfg.Gateway.Registration.MaxDevices,
 			}
 		}),
 		fx.Provide(func(cfg Config) handlers.Config {
@@ -110,8 +127,24 @@ func Module() fx.Option {
 				OpenAPIEnabled:  cfg.HTTP.OpenAPI.Enabled,
 			}
 		}),
-		fx.Provide(func(cfg Config) messages.Config {
-			return messages.Config{
+		fx.Provide(func(cfg Config) (messages.Config, error) {
+			content, err := messages.NewContentPolicy(
+				messages.ContentPolicySourceServer,
+				messages.ContentRules{
+					Keywords:       cfg.Messages.ContentPolicy.Keywords,
+					Patterns:       cfg.Messages.ContentPolicy.Patterns,
+					AllowedDomains: cfg.Messages.ContentPolicy.AllowedDomains,
+					DeniedDomains:  cfg.Messages.ContentPolicy.DeniedDomains,
+					MaxLinks:       cfg.Messages.ContentPolicy.MaxLinks,
+					MaxRecipients:  cfg.Messages.ContentPolicy.MaxRecipients,
+					Action:         messages.ContentAction(cfg.Messages.ContentPolicy.Action),
+				},
+			)
+			if err != nil {
+				return messages.Config{}, fmt.Errorf("invalid messages content policy config: %w", err)
+			}
+
+			msgsCfg := messages.Config{
 				CacheTTL:        time.Duration(cfg.Messages.CacheTTLSeconds) * time.Second,
 				HashingInterval: time.Duration(cfg.Messages.HashingIntervalSeconds) * time.Second,
 				Queue: messages.QueueConfig{
@@ -123,10 +156,40 @@ func Module() fx.Option {
 					StatsRefreshInterval: cfg.Messages.Queue.StatsRefreshInterval.Duration(),
 					StatsCacheTTL:        cfg.Messages.Queue.StatsCacheTTL.Duration(),
 				},
+				Phone: messages.PhonePolicy{
+					DefaultRegion: strings.ToUpper(cfg.Messages.Phone.DefaultRegion),
+					AllowedTypes: lo.Map(
+						cfg.Messages.Phone.AllowedTypes,
+						func(t string, _ int) messages.PhoneNumberType { return messages.PhoneNumberType(t) },
+					),
+					AllowedCountries: nil,
+					DeniedCountries:  nil,
+				},
+				Content:          content,
+				LinksBaseURL:     linksBaseURL(cfg.HTTP.API),
+				ContentReviewers: cfg.Messages.ContentPolicy.Reviewers,
+			}
+
+			if err = msgsCfg.Phone.Validate(); err != nil {
+				return msgsCfg, fmt.Errorf("invalid messages phone config: %w", err)
+			}
+
+			return msgsCfg, nil
+		}),
+		fx.Provide(func(cfg Config) devices.Config {
+			return devices.Config{
+				TokenGracePeriod: time.Duration(cfg.Devices.TokenGracePeriod),
 			}
 		}),
-		fx.Provide(func(_ Config) devices.Config {
-			return devices.Config{}
+		fx.Provide(func(cfg Config) (campaigns.Config, error) {
+			if cfg.Campaigns.ExpansionInterval <= 0 || cfg.Campaigns.ExpansionBatchSize <= 0 {
+				return campaigns.Config{}, errors.New("invalid campaigns config: expansion interval and batch size must be positive")
+			}
+
+			return campaigns.Config{
+				ExpansionInterval:  time.Duration(cfg.Campaigns.ExpansionInterval),
+				ExpansionBatchSize: cfg.Campaigns.ExpansionBatchSize,
+			}, nil
 		}),
 		fx.Provide(func(cfg Config) sse.Config {
 			return sse.NewConfig(
@@ -144,6 +207,30 @@ func Module() fx.Option {
 				BufferSize: cfg.PubSub.BufferSize,
 			}
 		}),
+		fx.Provide(func(cfg Config) blobs.Config {
+			return blobs.Config{
+				URL: cfg.Blobs.URL,
+			}
+		}),
+		fx.Provide(func(cfg Config) attachments.Config {
+			return attachments.Config{
+				MaxSize:      cfg.Messages.Attachments.MaxSize,
+				AllowedTypes: cfg.Messages.Attachments.AllowedTypes,
+			}
+		}),
+		fx.Provide(func(cfg Config) (telemetry.Config, error) {
+			if cfg.Telemetry.MaxSamples <= 0 || cfg.Telemetry.MinInterval < 0 {
+				return telemetry.Config{}, errors.New(
+					"invalid telemetry config: max samples must be positive and min interval must not be negative",
+				)
+			}
+
+			return telemetry.Config{
+				MaxSamples:   cfg.Telemetry.MaxSamples,
+				MinInterval:  time.Duration(cfg.Telemetry.MinInterval),
+				DeviceGauges: cfg.Telemetry.DeviceGauges,
+			}, nil
+		}),
 		fx.Provide(func(cfg Config) jwt.Config {
 			accessTTL := cfg.JWT.AccessTTL
 			if cfg.JWT.TTL != 0 {
@@ -167,3 +254,19 @@ func Module() fx.Option {
 		}),
 	)
 }
+
+// linksBaseURL returns the public URL of the API the short links are served from.
+// Short links need an absolute URL, so they are unavailable without the public host.
+func linksBaseURL(api API) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(api.Host, "https://"), "http://")
+	if host == "" {
+		return ""
+	}
+
+	base := "https://" + host
+	if path := strings.Trim(api.Path, "/"); path != "" {
+		base += "/" + path
+	}
+
+	return base
+}
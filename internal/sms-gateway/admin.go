@@ -0,0 +1,173 @@
+package smsgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/transfers"
+	"go.uber.org/fx"
+)
+
+const (
+	cmdDevicesPending    = "devices:pending"
+	cmdDevicesApprove    = "devices:approve"
+	cmdDevicesReject     = "devices:reject"
+	cmdDevicesTransfer   = "devices:transfer"
+	cmdRegistrationToken = "registration:token"
+
+	adminCommandTimeout = 30 * time.Second
+)
+
+var errUsage = errors.New("invalid arguments")
+
+type AdminParams struct {
+	fx.In
+
+	Shut fx.Shutdowner
+
+	AuthSvc      *auth.Service
+	DevicesSvc   *devices.Service
+	TransfersSvc *transfers.Service
+}
+
+// commandArgs returns the arguments following the command name, the cli
+// package doesn't pass them to the commands.
+func commandArgs() []string {
+	const skip = 2
+	if len(os.Args) <= skip {
+		return nil
+	}
+
+	return os.Args[skip:]
+}
+
+// DevicesPending prints the devices waiting for approval.
+func DevicesPending(p AdminParams) error {
+	ctx, cancel := context.WithTimeout(context.Background(), adminCommandTimeout)
+	defer cancel()
+
+	items, err := p.DevicesSvc.SelectPending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to select devices: %w", err)
+	}
+
+	for _, device := range items {
+		name := ""
+		if device.Name != nil {
+			name = *device.Name
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "%s\t%s\t%s\t%q\n",
+			device.ID, device.UserID, device.CreatedAt.Format(time.RFC3339), name,
+		)
+	}
+
+	return p.Shut.Shutdown()
+}
+
+// DevicesApprove approves the devices passed as arguments.
+func DevicesApprove(p AdminParams) error {
+	ids := commandArgs()
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: usage: %s <device-id>...", errUsage, cmdDevicesApprove)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminCommandTimeout)
+	defer cancel()
+
+	for _, id := range ids {
+		if _, err := p.DevicesSvc.Approve(ctx, id); err != nil {
+			return fmt.Errorf("failed to approve device %s: %w", id, err)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "%s approved\n", id)
+	}
+
+	return p.Shut.Shutdown()
+}
+
+// DevicesReject removes the devices passed as arguments.
+func DevicesReject(p AdminParams) error {
+	ids := commandArgs()
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: usage: %s <device-id>...", errUsage, cmdDevicesReject)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminCommandTimeout)
+	defer cancel()
+
+	for _, id := range ids {
+		if err := p.DevicesSvc.Reject(ctx, id); err != nil {
+			return fmt.Errorf("failed to reject device %s: %w", id, err)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "%s rejected\n", id)
+	}
+
+	return p.Shut.Shutdown()
+}
+
+// DevicesTransfer moves the device to another user, the webhooks scoped to the
+// device are moved unless "drop" is passed.
+func DevicesTransfer(p AdminParams) error {
+	const (
+		minArgs = 2
+		maxArgs = 3
+	)
+
+	args := commandArgs()
+	if len(args) < minArgs || len(args) > maxArgs {
+		return fmt.Errorf("%w: usage: %s <device-id> <user-id> [move|drop]", errUsage, cmdDevicesTransfer)
+	}
+
+	mode := transfers.WebhooksMove
+	if len(args) == maxArgs {
+		mode = transfers.WebhooksMode(args[2])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminCommandTimeout)
+	defer cancel()
+
+	transfer, err := p.TransfersSvc.Transfer(ctx, args[0], args[1], mode)
+	if err != nil {
+		return fmt.Errorf("failed to transfer device %s: %w", args[0], err)
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "%s transferred from %s to %s, webhooks: %d (%s)\n",
+		transfer.Device.ID, transfer.FromUserID, transfer.Device.UserID, transfer.Webhooks, transfer.WebhooksMode,
+	)
+
+	return p.Shut.Shutdown()
+}
+
+// RegistrationToken creates a registration token and prints it.
+func RegistrationToken(p AdminParams) error {
+	const argsCount = 2
+
+	args := commandArgs()
+	if len(args) != argsCount {
+		return fmt.Errorf("%w: usage: %s <name> <ttl, e.g. 72h>", errUsage, cmdRegistrationToken)
+	}
+
+	ttl, err := time.ParseDuration(args[1])
+	if err != nil {
+		return fmt.Errorf("%w: invalid ttl: %w", errUsage, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminCommandTimeout)
+	defer cancel()
+
+	token, err := p.AuthSvc.CreateRegistrationToken(ctx, auth.RegistrationTokenInput{
+		Name:      args[0],
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create registration token: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "%s\t%s\texpires %s\n", token.ID, token.Token, token.ExpiresAt.Format(time.RFC3339))
+
+	return p.Shut.Shutdown()
+}
@@ -5,11 +5,16 @@ import (
 	"sync"
 
 	appconfig "github.com/android-sms-gateway/server/internal/config"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/blobs"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/cache"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/inbox"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/jwt"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
 	appdb "github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
@@ -18,6 +23,8 @@ import (
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/sse"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/transfers"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/webhooks"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/online"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/openapi"
@@ -53,12 +60,19 @@ func Module() fx.Option {
 		db.Module,
 		cache.Module(),
 		pubsub.Module(),
+		blobs.Module(),
 		events.Module(),
 		messages.Module(),
+		campaigns.Module(),
+		contacts.Module(),
+		attachments.Module(),
 		health.Module(),
 		webhooks.Module(),
 		settings.Module(),
 		devices.Module(),
+		telemetry.Module(),
+		alerts.Module(),
+		transfers.Module(),
 		metrics.Module(),
 		sse.Module(),
 		online.Module(),
@@ -128,4 +142,9 @@ func Start(p StartParams) error {
 //nolint:gochecknoinits //backward compatibility
 func init() {
 	cli.Register("start", Start)
+	cli.Register(cmdDevicesPending, DevicesPending)
+	cli.Register(cmdDevicesApprove, DevicesApprove)
+	cli.Register(cmdDevicesReject, DevicesReject)
+	cli.Register(cmdDevicesTransfer, DevicesTransfer)
+	cli.Register(cmdRegistrationToken, RegistrationToken)
 }
@@ -0,0 +1,39 @@
+package blobs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/android-sms-gateway/server/pkg/blobs"
+)
+
+type Store = blobs.Store
+
+var ErrInvalidScheme = errors.New("invalid scheme")
+
+func New(config Config) (Store, error) {
+	if config.URL == "" {
+		config.URL = "file://./data/blobs"
+	}
+
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	var store Store
+	switch u.Scheme {
+	case "file":
+		// relative paths are given as file://./path, so the host is a part of the path
+		store, err = blobs.NewLocal(u.Host + u.Path)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidScheme, u.Scheme)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob store: %w", err)
+	}
+
+	return store, nil
+}
@@ -0,0 +1,6 @@
+package blobs
+
+// Config controls the blob storage backend via a URL (e.g., "file:///var/lib/sms-gateway/blobs").
+type Config struct {
+	URL string
+}
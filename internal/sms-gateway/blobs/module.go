@@ -0,0 +1,12 @@
+package blobs
+
+import (
+	"go.uber.org/fx"
+)
+
+func Module() fx.Option {
+	return fx.Module(
+		"blobs",
+		fx.Provide(New),
+	)
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/admin"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/attachments"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/campaigns"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/contacts"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/inbox"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/logs"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/messages"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/jwtauth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/numbers"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/settings"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/thirdparty"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/webhooks"
@@ -24,14 +29,19 @@ type thirdPartyHandler struct {
 	usersSvc *users.Service
 	jwtSvc   jwt.Service
 
-	healthHandler   *HealthHandler
-	messagesHandler *messages.ThirdPartyController
-	webhooksHandler *webhooks.ThirdPartyController
-	devicesHandler  *devices.ThirdPartyController
-	settingsHandler *settings.ThirdPartyController
-	inboxHandler    *inbox.ThirdPartyController
-	logsHandler     *logs.ThirdPartyController
-	authHandler     *thirdparty.AuthHandler
+	healthHandler      *HealthHandler
+	messagesHandler    *messages.ThirdPartyController
+	campaignsHandler   *campaigns.ThirdPartyController
+	contactsHandler    *contacts.ThirdPartyController
+	attachmentsHandler *attachments.ThirdPartyController
+	numbersHandler     *numbers.ThirdPartyController
+	webhooksHandler    *webhooks.ThirdPartyController
+	devicesHandler     *devices.ThirdPartyController
+	settingsHandler    *settings.ThirdPartyController
+	inboxHandler       *inbox.ThirdPartyController
+	logsHandler        *logs.ThirdPartyController
+	adminHandler       *admin.ThirdPartyController
+	authHandler        *thirdparty.AuthHandler
 }
 
 func newThirdPartyHandler(
@@ -40,11 +50,16 @@ func newThirdPartyHandler(
 
 	healthHandler *HealthHandler,
 	messagesHandler *messages.ThirdPartyController,
+	campaignsHandler *campaigns.ThirdPartyController,
+	contactsHandler *contacts.ThirdPartyController,
+	attachmentsHandler *attachments.ThirdPartyController,
+	numbersHandler *numbers.ThirdPartyController,
 	webhooksHandler *webhooks.ThirdPartyController,
 	devicesHandler *devices.ThirdPartyController,
 	settingsHandler *settings.ThirdPartyController,
 	inboxHandler *inbox.ThirdPartyController,
 	logsHandler *logs.ThirdPartyController,
+	adminHandler *admin.ThirdPartyController,
 	authHandler *thirdparty.AuthHandler,
 
 	logger *zap.Logger,
@@ -59,14 +74,19 @@ func newThirdPartyHandler(
 		usersSvc: usersSvc,
 		jwtSvc:   jwtService,
 
-		healthHandler:   healthHandler,
-		messagesHandler: messagesHandler,
-		webhooksHandler: webhooksHandler,
-		devicesHandler:  devicesHandler,
-		settingsHandler: settingsHandler,
-		inboxHandler:    inboxHandler,
-		logsHandler:     logsHandler,
-		authHandler:     authHandler,
+		healthHandler:      healthHandler,
+		messagesHandler:    messagesHandler,
+		campaignsHandler:   campaignsHandler,
+		contactsHandler:    contactsHandler,
+		attachmentsHandler: attachmentsHandler,
+		numbersHandler:     numbersHandler,
+		webhooksHandler:    webhooksHandler,
+		devicesHandler:     devicesHandler,
+		settingsHandler:    settingsHandler,
+		inboxHandler:       inboxHandler,
+		logsHandler:        logsHandler,
+		adminHandler:       adminHandler,
+		authHandler:        authHandler,
 	}
 }
 
@@ -91,6 +111,11 @@ func (h *thirdPartyHandler) Register(router fiber.Router) {
 
 	h.messagesHandler.Register(router.Group("/message")) // TODO: remove after 2025-12-31
 	h.messagesHandler.Register(router.Group("/messages"))
+	h.attachmentsHandler.Register(router.Group("/attachments"))
+
+	h.campaignsHandler.Register(router.Group("/campaigns"))
+	h.contactsHandler.Register(router.Group("/contacts"))
+	h.numbersHandler.Register(router.Group("/numbers"))
 
 	h.devicesHandler.Register(router.Group("/device")) // TODO: remove after 2025-07-11
 	h.devicesHandler.Register(router.Group("/devices"))
@@ -101,4 +126,6 @@ func (h *thirdPartyHandler) Register(router fiber.Router) {
 	h.webhooksHandler.Register(router.Group("/webhooks"))
 
 	h.logsHandler.Register(router.Group("/logs"))
+
+	h.adminHandler.Register(router.Group("/admin"))
 }
@@ -0,0 +1,306 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/transfers"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	AuthSvc      *auth.Service
+	DevicesSvc   *devices.Service
+	TransfersSvc *transfers.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+// ThirdPartyController serves the endpoints of the users listed as admins in the server config.
+type ThirdPartyController struct {
+	base.Handler
+
+	authSvc      *auth.Service
+	devicesSvc   *devices.Service
+	transfersSvc *transfers.Service
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger,
+			Validator: params.Validator,
+		},
+		authSvc:      params.AuthSvc,
+		devicesSvc:   params.DevicesSvc,
+		transfersSvc: params.TransfersSvc,
+	}
+}
+
+//	@Summary		List devices pending approval
+//	@Description	Returns the devices of all users waiting for approval, oldest first. Available to the admins listed in the server config only.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			Admin
+//	@Produce		json
+//	@Success		200	{object}	[]Device					"Device list"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/admin/devices [get]
+//
+// List devices pending approval.
+func (h *ThirdPartyController) listDevices(c *fiber.Ctx) error {
+	items, err := h.devicesSvc.SelectPending(c.Context())
+	if err != nil {
+		return fmt.Errorf("failed to select devices: %w", err)
+	}
+
+	return c.JSON(lo.Map(items, func(item devices.Device, _ int) Device { return newDevice(item) }))
+}
+
+//	@Summary		Approve device
+//	@Description	Approves the device pending approval, messages are routed to it from now on.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			id	path		string						true	"Device ID"
+//	@Success		200	{object}	Device						"Approved device"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Device not found or not pending approval"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/admin/devices/{id}/approve [post]
+//
+// Approve device.
+func (h *ThirdPartyController) approveDevice(c *fiber.Ctx) error {
+	device, err := h.devicesSvc.Approve(c.Context(), c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to approve device: %w", err)
+	}
+
+	h.Logger.Info("device approved",
+		zap.String("device_id", device.ID),
+		zap.String("admin_id", userauth.GetUserID(c)),
+	)
+
+	return c.JSON(newDevice(*device))
+}
+
+//	@Summary		Reject device
+//	@Description	Removes the device pending approval, its token stops working immediately.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			Admin
+//	@Param			id	path	string	true	"Device ID"
+//	@Success		204	"Device rejected"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Device not found or not pending approval"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/admin/devices/{id}/reject [post]
+//
+// Reject device.
+func (h *ThirdPartyController) rejectDevice(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.devicesSvc.Reject(c.Context(), id); err != nil {
+		return fmt.Errorf("failed to reject device: %w", err)
+	}
+
+	h.Logger.Info("device rejected",
+		zap.String("device_id", id),
+		zap.String("admin_id", userauth.GetUserID(c)),
+	)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Transfer device
+//	@Description	Moves the device to another user together with its messages. The webhooks scoped to the device are moved or dropped. The user may not exceed the devices limit.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			request	body		TransferRequest				true	"Transfer"
+//	@Success		200		{object}	Transfer					"Transferred device"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request or devices limit reached"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device or user not found"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"The user already has a webhook with the same ID"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/admin/devices/{id}/transfer [post]
+//
+// Transfer device.
+func (h *ThirdPartyController) transferDevice(c *fiber.Ctx) error {
+	req := new(TransferRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	transfer, err := h.transfersSvc.Transfer(c.Context(), c.Params("id"), req.UserID, req.webhooksMode())
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "device not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to transfer device: %w", err)
+	}
+
+	h.Logger.Info("device transferred",
+		zap.String("device_id", transfer.Device.ID),
+		zap.String("from_user_id", transfer.FromUserID),
+		zap.String("to_user_id", transfer.Device.UserID),
+		zap.String("admin_id", userauth.GetUserID(c)),
+	)
+
+	return c.JSON(newTransfer(*transfer))
+}
+
+//	@Summary		List registration tokens
+//	@Description	Returns the registration tokens including the expired ones. The tokens themselves are not returned.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			Admin
+//	@Produce		json
+//	@Success		200	{object}	[]RegistrationToken			"Registration tokens"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/admin/registration-tokens [get]
+//
+// List registration tokens.
+func (h *ThirdPartyController) listTokens(c *fiber.Ctx) error {
+	items, err := h.authSvc.SelectRegistrationTokens(c.Context())
+	if err != nil {
+		return fmt.Errorf("failed to select registration tokens: %w", err)
+	}
+
+	now := time.Now()
+	return c.JSON(lo.Map(items, func(item auth.RegistrationToken, _ int) RegistrationToken {
+		return newRegistrationToken(item, now)
+	}))
+}
+
+//	@Summary		Create registration token
+//	@Description	Creates a token authorizing device registration in private mode until it expires. The token is returned only once.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RegistrationTokenRequest	true	"Registration token"
+//	@Success		201		{object}	RegistrationToken			"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/admin/registration-tokens [post]
+//
+// Create registration token.
+func (h *ThirdPartyController) postToken(c *fiber.Ctx) error {
+	req := new(RegistrationTokenRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	token, err := h.authSvc.CreateRegistrationToken(c.Context(), req.toDomain())
+	if err != nil {
+		return fmt.Errorf("failed to create registration token: %w", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newRegistrationToken(*token, time.Now()))
+}
+
+//	@Summary		Delete registration token
+//	@Description	Revokes the registration token. Devices registered with it are not affected.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			Admin
+//	@Param			id	path	string	true	"Token ID"
+//	@Success		204	"Token deleted"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Token not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/admin/registration-tokens/{id} [delete]
+//
+// Delete registration token.
+func (h *ThirdPartyController) deleteToken(c *fiber.Ctx) error {
+	if err := h.authSvc.DeleteRegistrationToken(c.Context(), c.Params("id")); err != nil {
+		return fmt.Errorf("failed to delete registration token: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// adminRequired refuses the users not listed as admins.
+func (h *ThirdPartyController) adminRequired(c *fiber.Ctx) error {
+	if !h.authSvc.IsAdmin(userauth.GetUserID(c)) {
+		return fiber.NewError(fiber.StatusForbidden, "admin required")
+	}
+
+	return c.Next()
+}
+
+func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError
+	}
+
+	var (
+		validationError         auth.ValidationError
+		transferValidationError transfers.ValidationError
+	)
+	switch {
+	case errors.As(err, &validationError):
+		return fiber.NewError(fiber.StatusBadRequest, validationError.Error())
+	case errors.As(err, &transferValidationError):
+		return fiber.NewError(fiber.StatusBadRequest, transferValidationError.Error())
+	case errors.Is(err, transfers.ErrUserNotFound):
+		return fiber.NewError(fiber.StatusNotFound, transfers.ErrUserNotFound.Error())
+	case errors.Is(err, transfers.ErrWebhookConflict):
+		return fiber.NewError(fiber.StatusConflict, transfers.ErrWebhookConflict.Error())
+	case errors.Is(err, devices.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "device not found or not pending approval")
+	case errors.Is(err, auth.ErrTokenNotFound):
+		return fiber.NewError(fiber.StatusNotFound, auth.ErrTokenNotFound.Error())
+	}
+
+	h.Logger.Error("failed to handle request", zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "failed to handle request")
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Use(h.errorHandler, h.adminRequired)
+
+	router.Get("devices", permissions.RequireScope(ScopeDevices), h.listDevices)
+	router.Post("devices/:id/approve", permissions.RequireScope(ScopeDevices), h.approveDevice)
+	router.Post("devices/:id/reject", permissions.RequireScope(ScopeDevices), h.rejectDevice)
+	router.Post("devices/:id/transfer", permissions.RequireScope(ScopeDevices), h.transferDevice)
+
+	router.Get("registration-tokens", permissions.RequireScope(ScopeRegistration), h.listTokens)
+	router.Post("registration-tokens", permissions.RequireScope(ScopeRegistration), h.postToken)
+	router.Delete("registration-tokens/:id", permissions.RequireScope(ScopeRegistration), h.deleteToken)
+}
@@ -0,0 +1,6 @@
+package admin
+
+const (
+	ScopeDevices      = "admin:devices"
+	ScopeRegistration = "admin:registration"
+)
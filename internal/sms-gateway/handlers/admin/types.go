@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/transfers"
+)
+
+// Device extends smsgateway.Device with its owner and approval state.
+type Device struct {
+	smsgateway.Device
+
+	// Owner ID
+	UserID string `json:"userId"`
+	// Approval state
+	Status string `json:"status" enums:"active,pending_approval"`
+}
+
+func newDevice(device devices.Device) Device {
+	return Device{
+		Device: converters.DeviceToDTO(device),
+		UserID: device.UserID,
+		Status: string(device.Status),
+	}
+}
+
+// TransferRequest describes a device transfer.
+type TransferRequest struct {
+	// ID of the user receiving the device
+	UserID string `json:"userId" validate:"required,max=32"`
+	// What happens to the webhooks scoped to the device, moved by default
+	Webhooks string `json:"webhooks,omitempty" validate:"omitempty,oneof=move drop" enums:"move,drop"`
+}
+
+func (r TransferRequest) webhooksMode() transfers.WebhooksMode {
+	if r.Webhooks == "" {
+		return transfers.WebhooksMove
+	}
+
+	return transfers.WebhooksMode(r.Webhooks)
+}
+
+// Transfer is a completed device transfer.
+type Transfer struct {
+	// Device with the new owner
+	Device Device `json:"device"`
+	// Previous owner ID
+	FromUserID string `json:"fromUserId"`
+	// What happened to the webhooks scoped to the device
+	WebhooksMode string `json:"webhooksMode" enums:"move,drop"`
+	// Number of the webhooks moved or dropped
+	Webhooks int64 `json:"webhooks"`
+}
+
+func newTransfer(transfer transfers.Transfer) Transfer {
+	return Transfer{
+		Device:       newDevice(transfer.Device),
+		FromUserID:   transfer.FromUserID,
+		WebhooksMode: string(transfer.WebhooksMode),
+		Webhooks:     transfer.Webhooks,
+	}
+}
+
+// RegistrationTokenRequest describes a new registration token.
+type RegistrationTokenRequest struct {
+	// Token name, e.g. who it is given to
+	Name string `json:"name" validate:"required,max=128"`
+	// The token stops authorizing registration at this time
+	ExpiresAt time.Time `json:"expiresAt" validate:"required"`
+}
+
+func (r RegistrationTokenRequest) toDomain() auth.RegistrationTokenInput {
+	return auth.RegistrationTokenInput{
+		Name:      r.Name,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+// RegistrationToken authorizes device registration in private mode until it expires.
+type RegistrationToken struct {
+	// Token ID
+	ID string `json:"id"`
+	// Token name
+	Name string `json:"name"`
+	// The token itself, returned only on creation
+	Token string `json:"token,omitempty"`
+	// Expiration time
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Whether the token no longer authorizes registration
+	Expired bool `json:"expired"`
+	// Creation time
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newRegistrationToken(token auth.RegistrationToken, now time.Time) RegistrationToken {
+	return RegistrationToken{
+		ID:        token.ID,
+		Name:      token.Name,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+		Expired:   token.IsExpired(now),
+		CreatedAt: token.CreatedAt,
+	}
+}
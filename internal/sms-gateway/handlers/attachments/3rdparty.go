@@ -0,0 +1,145 @@
+package attachments
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	route3rdPartyGetAttachment = "3rdparty.get.attachment"
+
+	formFieldFile = "file"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	AttachmentsSvc *attachments.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	attachmentsSvc *attachments.Service
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger,
+			Validator: params.Validator,
+		},
+		attachmentsSvc: params.AttachmentsSvc,
+	}
+}
+
+//	@Summary		Upload attachment
+//	@Description	Uploads a file to be sent with MMS messages. The content type is detected from the content and must be one of the allowed types (JPEG, PNG, GIF and PDF by default).
+//	@Description	Attachments not used by a message are removed after a while; the attachments of a message are removed when the message is hashed or deleted.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file	formData	file						true	"File"
+//	@Success		201		{object}	AttachmentResponse			"Uploaded"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		413		{object}	smsgateway.ErrorResponse	"File is too large"
+//	@Failure		415		{object}	smsgateway.ErrorResponse	"File type is not supported"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			201		{string}	Location					"Get attachment URL"
+//	@Router			/3rdparty/v1/attachments [post]
+//
+// Upload attachment.
+func (h *ThirdPartyController) post(userID string, c *fiber.Ctx) error {
+	header, err := c.FormFile(formFieldFile)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to read `%s` field: %s", formFieldFile, err))
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentsSvc.Upload(c.Context(), userID, header.Filename, file, header.Size)
+	if err != nil {
+		return fmt.Errorf("failed to upload attachment: %w", err)
+	}
+
+	location, err := c.GetRouteURL(route3rdPartyGetAttachment, fiber.Map{
+		"id": attachment.ID,
+	})
+	if err != nil {
+		h.Logger.Warn(
+			"failed to get route URL",
+			zap.String("route", route3rdPartyGetAttachment),
+			zap.String("id", attachment.ID),
+			zap.Error(err),
+		)
+	} else {
+		c.Location(location)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newAttachmentResponse(*attachment))
+}
+
+//	@Summary		Get attachment
+//	@Description	Returns the description of an uploaded file
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Produce		json
+//	@Param			id	path		string						true	"Attachment ID"
+//	@Success		200	{object}	AttachmentResponse			"Attachment"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Attachment not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/attachments/{id} [get]
+//
+// Get attachment.
+func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
+	attachment, err := h.attachmentsSvc.Get(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return c.JSON(newAttachmentResponse(*attachment))
+}
+
+func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	if fiberError := toFiberError(err); fiberError != nil {
+		return fiberError
+	}
+
+	h.Logger.Error("failed to handle request", zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "failed to handle request")
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Use(h.errorHandler)
+
+	router.Post("", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.post))
+	router.Get(":id", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.get)).
+		Name(route3rdPartyGetAttachment)
+}
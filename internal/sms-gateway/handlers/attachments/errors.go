@@ -0,0 +1,30 @@
+package attachments
+
+import (
+	"errors"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
+	"github.com/gofiber/fiber/v2"
+)
+
+// toFiberError maps the module errors to HTTP errors, nil if the error is unknown.
+func toFiberError(err error) *fiber.Error {
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError
+	}
+
+	var validationError attachments.ValidationError
+	switch {
+	case errors.As(err, &validationError):
+		return fiber.NewError(fiber.StatusBadRequest, validationError.Error())
+	case errors.Is(err, attachments.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, attachments.ErrNotFound.Error())
+	case errors.Is(err, attachments.ErrTooLarge):
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, attachments.ErrUnsupportedType):
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, err.Error())
+	}
+
+	return nil
+}
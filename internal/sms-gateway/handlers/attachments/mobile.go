@@ -0,0 +1,86 @@
+package attachments
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/deviceauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type mobileControllerParams struct {
+	fx.In
+
+	AttachmentsSvc *attachments.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type MobileController struct {
+	base.Handler
+
+	attachmentsSvc *attachments.Service
+}
+
+func NewMobileController(params mobileControllerParams) *MobileController {
+	return &MobileController{
+		Handler: base.Handler{
+			Logger:    params.Logger,
+			Validator: params.Validator,
+		},
+		attachmentsSvc: params.AttachmentsSvc,
+	}
+}
+
+//	@Summary		Download attachment
+//	@Description	Returns the content of an MMS attachment. Only the attachments of the device's messages are available until the messages are hashed.
+//	@Security		MobileToken
+//	@Tags			Device, Messages
+//	@Produce		octet-stream
+//	@Param			id	path		string						true	"Attachment ID"
+//	@Success		200	{file}		file						"Attachment content"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Attachment not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/attachments/{id} [get]
+//
+// Download attachment.
+func (h *MobileController) get(device devices.Device, c *fiber.Ctx) error {
+	attachment, content, err := h.attachmentsSvc.Open(c.Context(), device.ID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+attachment.Name+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	// the stream is closed by fasthttp after the response is sent
+	return c.SendStream(content, int(attachment.Size))
+}
+
+func (h *MobileController) errorHandler(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	if fiberError := toFiberError(err); fiberError != nil {
+		return fiberError
+	}
+
+	h.Logger.Error("failed to handle request", zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "failed to handle request")
+}
+
+func (h *MobileController) Register(router fiber.Router) {
+	router.Use(h.errorHandler)
+
+	router.Get(":id", deviceauth.WithDevice(h.get))
+}
@@ -0,0 +1,6 @@
+package attachments
+
+const (
+	ScopeRead  = "attachments:read"
+	ScopeWrite = "attachments:write"
+)
@@ -0,0 +1,31 @@
+package attachments
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
+)
+
+// AttachmentResponse describes an uploaded file.
+type AttachmentResponse struct {
+	// Attachment ID, referenced in `mmsMessage.attachmentIds` on enqueue
+	ID string `json:"id"`
+	// File name
+	Name string `json:"name"`
+	// MIME type detected from the content
+	ContentType string `json:"contentType"`
+	// Size in bytes
+	Size int64 `json:"size"`
+	// Upload time
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newAttachmentResponse(attachment attachments.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          attachment.ID,
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
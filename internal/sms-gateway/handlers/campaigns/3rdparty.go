@@ -0,0 +1,329 @@
+package campaigns
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const route3rdPartyGetCampaign = "3rdparty.get.campaign"
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	CampaignsSvc *campaigns.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	campaignsSvc *campaigns.Service
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger,
+			Validator: params.Validator,
+		},
+		campaignsSvc: params.CampaignsSvc,
+	}
+}
+
+//	@Summary		Create campaign
+//	@Description	Creates a campaign. Messages are generated in the background and spread over the throttle window.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateCampaignRequest		true	"Campaign"
+//	@Success		201		{object}	CampaignResponse			"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			201		{string}	Location					"Get campaign URL"
+//	@Router			/3rdparty/v1/campaigns [post]
+//
+// Create campaign.
+func (h *ThirdPartyController) post(userID string, c *fiber.Ctx) error {
+	req := new(CreateCampaignRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	campaign, err := h.campaignsSvc.Create(c.Context(), userID, req.ToDomain())
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	location, err := c.GetRouteURL(route3rdPartyGetCampaign, fiber.Map{
+		"id": campaign.ID,
+	})
+	if err != nil {
+		h.Logger.Warn("failed to get route URL", zap.String("id", campaign.ID), zap.Error(err))
+	} else {
+		c.Location(location)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newCampaignResponse(*campaign))
+}
+
+//	@Summary		List campaigns
+//	@Description	Returns campaigns ordered by creation time, newest first
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			limit	query		int							false	"Pagination limit"	default(50)	minimum(1)	maximum(100)
+//	@Param			offset	query		int							false	"Pagination offset"	default(0)
+//	@Success		200		{object}	[]CampaignResponse			"Campaign list"
+//	@Header			200		{integer}	X-Total-Count				"Total number of items available"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns [get]
+//
+// List campaigns.
+func (h *ThirdPartyController) list(userID string, c *fiber.Ctx) error {
+	const defaultLimit = 50
+
+	params := new(listQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	items, total, err := h.campaignsSvc.List(
+		c.Context(),
+		userID,
+		lo.FromPtrOr(params.Limit, defaultLimit),
+		lo.FromPtr(params.Offset),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	c.Set("X-Total-Count", strconv.Itoa(int(total)))
+	return c.JSON(lo.Map(items, func(item campaigns.Campaign, _ int) CampaignResponse {
+		return newCampaignResponse(item)
+	}))
+}
+
+//	@Summary		Get campaign
+//	@Description	Returns the campaign
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			id	path		string						true	"Campaign ID"
+//	@Success		200	{object}	CampaignResponse			"Campaign"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns/{id} [get]
+//
+// Get campaign.
+func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
+	campaign, err := h.campaignsSvc.Get(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return c.JSON(newCampaignResponse(*campaign))
+}
+
+//	@Summary		Get campaign stats
+//	@Description	Returns the progress of the campaign and the number of its messages by state
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			id	path		string						true	"Campaign ID"
+//	@Success		200	{object}	CampaignStatsResponse		"Campaign stats"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns/{id}/stats [get]
+//
+// Get campaign stats.
+func (h *ThirdPartyController) getStats(userID string, c *fiber.Ctx) error {
+	stats, err := h.campaignsSvc.Stats(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to get campaign stats: %w", err)
+	}
+
+	return c.JSON(newCampaignStatsResponse(*stats))
+}
+
+//	@Summary		Pause campaign
+//	@Description	Stops generating new messages. Already generated messages are sent as scheduled.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			id	path		string						true	"Campaign ID"
+//	@Success		200	{object}	CampaignResponse			"Campaign"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		409	{object}	smsgateway.ErrorResponse	"Campaign is not active"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns/{id}/pause [post]
+//
+// Pause campaign.
+func (h *ThirdPartyController) postPause(userID string, c *fiber.Ctx) error {
+	campaign, err := h.campaignsSvc.Pause(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to pause campaign: %w", err)
+	}
+
+	return c.JSON(newCampaignResponse(*campaign))
+}
+
+//	@Summary		Resume campaign
+//	@Description	Resumes a paused campaign. The remaining schedule is shifted by the pause duration.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			id	path		string						true	"Campaign ID"
+//	@Success		200	{object}	CampaignResponse			"Campaign"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		409	{object}	smsgateway.ErrorResponse	"Campaign is not paused"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns/{id}/resume [post]
+//
+// Resume campaign.
+func (h *ThirdPartyController) postResume(userID string, c *fiber.Ctx) error {
+	campaign, err := h.campaignsSvc.Resume(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to resume campaign: %w", err)
+	}
+
+	return c.JSON(newCampaignResponse(*campaign))
+}
+
+//	@Summary		Abort campaign
+//	@Description	Stops the campaign and cancels its pending messages in the background
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			id	path		string						true	"Campaign ID"
+//	@Success		200	{object}	CampaignResponse			"Campaign"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		409	{object}	smsgateway.ErrorResponse	"Campaign is already finished"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Failure		503	{object}	smsgateway.ErrorResponse	"Too many bulk jobs in progress"
+//	@Router			/3rdparty/v1/campaigns/{id}/abort [post]
+//
+// Abort campaign.
+func (h *ThirdPartyController) postAbort(userID string, c *fiber.Ctx) error {
+	campaign, err := h.campaignsSvc.Abort(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to abort campaign: %w", err)
+	}
+
+	return c.JSON(newCampaignResponse(*campaign))
+}
+
+//	@Summary		Export campaign
+//	@Description	Returns a CSV report with a row per recipient: position, phone number, message ID, state and error
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Campaigns
+//	@Produce		text/csv
+//	@Param			id	path		string						true	"Campaign ID"
+//	@Success		200	{string}	string						"CSV report"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns/{id}/export [get]
+//
+// Export campaign.
+func (h *ThirdPartyController) getExport(userID string, c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.campaignsSvc.Get(c.Context(), userID, id); err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment("campaign-" + id + ".csv")
+
+	ctx := c.Context()
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.campaignsSvc.Export(ctx, userID, id, w); err != nil {
+			h.Logger.Error("failed to export campaign", zap.String("campaign_id", id), zap.Error(err))
+		}
+	})
+
+	return nil
+}
+
+func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError
+	}
+
+	var validationError campaigns.ValidationError
+	var msgValidationError messages.ValidationError
+	switch {
+	case errors.As(err, &validationError):
+		return fiber.NewError(fiber.StatusBadRequest, validationError.Error())
+	case errors.As(err, &msgValidationError):
+		return fiber.NewError(fiber.StatusBadRequest, msgValidationError.Error())
+	case errors.Is(err, campaigns.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, campaigns.ErrNotFound.Error())
+	case errors.Is(err, campaigns.ErrInvalidStatus):
+		return fiber.NewError(fiber.StatusConflict, campaigns.ErrInvalidStatus.Error())
+	case errors.Is(err, messages.ErrBulkQueueFull):
+		return fiber.NewError(fiber.StatusServiceUnavailable, messages.ErrBulkQueueFull.Error())
+	}
+
+	h.Logger.Error("failed to handle request", zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "failed to handle request")
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Use(h.errorHandler)
+
+	router.Get("", permissions.RequireScope(ScopeList), userauth.WithUserID(h.list))
+	router.Post("", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.post))
+	router.Get(":id", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.get)).Name(route3rdPartyGetCampaign)
+	router.Get(":id/stats", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.getStats))
+	router.Get(":id/export", permissions.RequireScope(ScopeExport), userauth.WithUserID(h.getExport))
+	router.Post(":id/pause", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.postPause))
+	router.Post(":id/resume", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.postResume))
+	router.Post(":id/abort", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.postAbort))
+}
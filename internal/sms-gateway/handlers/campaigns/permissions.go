@@ -0,0 +1,8 @@
+package campaigns
+
+const (
+	ScopeList   = "campaigns:list"
+	ScopeRead   = "campaigns:read"
+	ScopeWrite  = "campaigns:write"
+	ScopeExport = "campaigns:export"
+)
@@ -0,0 +1,118 @@
+package campaigns
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/samber/lo"
+)
+
+// CreateCampaignRequest describes a new campaign.
+type CreateCampaignRequest struct {
+	// Campaign name
+	Name string `json:"name" validate:"required,max=128"`
+	// Message text sent to every recipient
+	Text string `json:"text" validate:"required,max=65535"`
+	// Recipients
+	PhoneNumbers []string `json:"phoneNumbers" validate:"required,min=1,max=100000,dive,required,min=10,max=128"`
+	// Devices used for sending; all user devices are used when empty
+	DevicePool []string `json:"devicePool,omitempty" validate:"omitempty,max=100,dive,len=21"`
+	// Period in seconds over which the messages are spread evenly; 0 sends them as soon as possible
+	ThrottleWindow uint32 `json:"throttleWindow,omitempty" validate:"omitempty,max=2592000"`
+}
+
+func (r *CreateCampaignRequest) ToDomain() campaigns.CampaignInput {
+	return campaigns.CampaignInput{
+		Name:           r.Name,
+		Text:           r.Text,
+		PhoneNumbers:   r.PhoneNumbers,
+		DevicePool:     r.DevicePool,
+		ThrottleWindow: time.Duration(r.ThrottleWindow) * time.Second,
+	}
+}
+
+// CampaignResponse describes a campaign.
+type CampaignResponse struct {
+	// Campaign ID
+	ID string `json:"id"`
+	// Campaign name
+	Name string `json:"name"`
+	// Message text
+	Text string `json:"text"`
+	// Devices used for sending
+	DevicePool []string `json:"devicePool"`
+	// Period in seconds over which the messages are spread
+	ThrottleWindow uint32 `json:"throttleWindow"`
+	// Campaign status
+	Status string `json:"status" enums:"Active,Paused,Completed,Aborted"`
+	// Number of recipients
+	Total int `json:"total"`
+	// Number of generated messages
+	Generated int `json:"generated"`
+	// Start of the sending schedule
+	StartedAt time.Time `json:"startedAt"`
+	// Time the campaign was paused
+	PausedAt *time.Time `json:"pausedAt,omitempty"`
+	// Creation time
+	CreatedAt time.Time `json:"createdAt"`
+	// Last update time
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func newCampaignResponse(campaign campaigns.Campaign) CampaignResponse {
+	return CampaignResponse{
+		ID:             campaign.ID,
+		Name:           campaign.Name,
+		Text:           campaign.Text,
+		DevicePool:     lo.Ternary(campaign.DevicePool == nil, []string{}, campaign.DevicePool),
+		ThrottleWindow: uint32(campaign.ThrottleWindow / time.Second), //nolint:gosec // limited on creation
+		Status:         string(campaign.Status),
+		Total:          campaign.Total,
+		Generated:      campaign.Generated,
+		StartedAt:      campaign.StartedAt,
+		PausedAt:       campaign.PausedAt,
+		CreatedAt:      campaign.CreatedAt,
+		UpdatedAt:      campaign.UpdatedAt,
+	}
+}
+
+// CampaignStatsResponse describes the progress of a campaign.
+type CampaignStatsResponse struct {
+	// Number of recipients
+	Total int `json:"total"`
+	// Number of generated messages
+	Generated int `json:"generated"`
+	// Number of messages waiting to be sent
+	Pending int64 `json:"pending"`
+	// Number of messages held for content review
+	Quarantined int64 `json:"quarantined"`
+	// Number of messages sent
+	Sent int64 `json:"sent"`
+	// Number of messages delivered
+	Delivered int64 `json:"delivered"`
+	// Number of failed messages
+	Failed int64 `json:"failed"`
+	// Number of cancelled messages
+	Cancelled int64 `json:"cancelled"`
+}
+
+func newCampaignStatsResponse(stats campaigns.Stats) CampaignStatsResponse {
+	return CampaignStatsResponse{
+		Total:     stats.Total,
+		Generated: stats.Generated,
+		Pending: stats.States[messages.ProcessingStatePending] +
+			stats.States[messages.ProcessingStateCancelling] +
+			stats.States[messages.ProcessingStateProcessed],
+		Quarantined: stats.States[messages.ProcessingStateQuarantined],
+		Sent:        stats.States[messages.ProcessingStateSent],
+		Delivered:   stats.States[messages.ProcessingStateDelivered],
+		Failed:      stats.States[messages.ProcessingStateFailed],
+		Cancelled:   stats.States[messages.ProcessingStateCancelled],
+	}
+}
+
+type listQueryParams struct {
+	Limit  *int `query:"limit"  validate:"omitempty,min=1,max=100"`
+	Offset *int `query:"offset" validate:"omitempty,min=0"`
+}
@@ -0,0 +1,550 @@
+package contacts
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	route3rdPartyGetContact = "3rdparty.get.contact"
+	route3rdPartyGetGroup   = "3rdparty.get.contact.group"
+
+	defaultLimit = 50
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	ContactsSvc *contacts.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	contactsSvc *contacts.Service
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger,
+			Validator: params.Validator,
+		},
+		contactsSvc: params.ContactsSvc,
+	}
+}
+
+//	@Summary		Create contact
+//	@Description	Creates a contact. The phone number is normalized the same way as on enqueue.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ContactRequest				true	"Contact"
+//	@Success		201		{object}	ContactResponse				"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Contact with the same phone number already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			201		{string}	Location					"Get contact URL"
+//	@Router			/3rdparty/v1/contacts [post]
+//
+// Create contact.
+func (h *ThirdPartyController) post(userID string, c *fiber.Ctx) error {
+	req := new(ContactRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	contact, err := h.contactsSvc.Create(c.Context(), userID, req.ToDomain())
+	if err != nil {
+		return fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	h.setLocation(c, route3rdPartyGetContact, contact.ID)
+
+	return c.Status(fiber.StatusCreated).JSON(newContactResponse(*contact))
+}
+
+//	@Summary		List contacts
+//	@Description	Returns contacts ordered by name
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			tag		query		string						false	"Filter by tag"
+//	@Param			groupId	query		string						false	"Filter by group ID"	minLength(21)	maxLength(21)
+//	@Param			limit	query		int							false	"Pagination limit"		default(50)		minimum(1)	maximum(100)
+//	@Param			offset	query		int							false	"Pagination offset"		default(0)
+//	@Success		200		{object}	[]ContactResponse			"Contact list"
+//	@Header			200		{integer}	X-Total-Count				"Total number of items available"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts [get]
+//
+// List contacts.
+func (h *ThirdPartyController) list(userID string, c *fiber.Ctx) error {
+	params := new(contactsQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	items, total, err := h.contactsSvc.List(
+		c.Context(),
+		userID,
+		params.ToFilter(),
+		lo.FromPtrOr(params.Limit, defaultLimit),
+		lo.FromPtr(params.Offset),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list contacts: %w", err)
+	}
+
+	c.Set("X-Total-Count", strconv.Itoa(int(total)))
+	return c.JSON(lo.Map(items, func(item contacts.Contact, _ int) ContactResponse {
+		return newContactResponse(item)
+	}))
+}
+
+//	@Summary		Get contact
+//	@Description	Returns the contact
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path		string						true	"Contact ID"
+//	@Success		200	{object}	ContactResponse				"Contact"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Contact not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/{id} [get]
+//
+// Get contact.
+func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
+	contact, err := h.contactsSvc.Get(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to get contact: %w", err)
+	}
+
+	return c.JSON(newContactResponse(*contact))
+}
+
+//	@Summary		Replace contact
+//	@Description	Replaces the contact with the request
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Contact ID"
+//	@Param			request	body		ContactRequest				true	"Contact"
+//	@Success		200		{object}	ContactResponse				"Contact"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Contact not found"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Contact with the same phone number already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/{id} [put]
+//
+// Replace contact.
+func (h *ThirdPartyController) put(userID string, c *fiber.Ctx) error {
+	req := new(ContactRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	contact, err := h.contactsSvc.Update(c.Context(), userID, c.Params("id"), req.ToDomain())
+	if err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	return c.JSON(newContactResponse(*contact))
+}
+
+//	@Summary		Delete contact
+//	@Description	Deletes the contact and removes it from its groups. Messages sent to the contact are not affected.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path	string	true	"Contact ID"
+//	@Success		204	"Successfully removed"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Contact not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/{id} [delete]
+//
+// Delete contact.
+func (h *ThirdPartyController) delete(userID string, c *fiber.Ctx) error {
+	if err := h.contactsSvc.Delete(c.Context(), userID, c.Params("id")); err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Import contacts
+//	@Description	Imports contacts from a CSV file with a header row. The `phone_number` column is required, `name` and `tags` (separated by `;`) are optional, other columns are custom fields.
+//	@Description	Existing contacts with the same phone number are replaced. Either all rows are imported or none.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Accept			text/csv
+//	@Produce		json
+//	@Param			groupId	query		string						false	"Add the imported contacts to the group"	minLength(21)	maxLength(21)
+//	@Param			request	body		string						true	"CSV file"
+//	@Success		200		{object}	ImportResponse				"Import result"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/import [post]
+//
+// Import contacts.
+func (h *ThirdPartyController) postImport(userID string, c *fiber.Ctx) error {
+	params := new(importQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.contactsSvc.Import(c.Context(), userID, bytes.NewReader(c.Body()), params.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to import contacts: %w", err)
+	}
+
+	return c.JSON(ImportResponse{
+		Created: result.Created,
+		Updated: result.Updated,
+	})
+}
+
+//	@Summary		Export contacts
+//	@Description	Returns all contacts as a CSV file in the import format
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Produce		text/csv
+//	@Success		200	{string}	string						"CSV file"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/export [get]
+//
+// Export contacts.
+func (h *ThirdPartyController) getExport(userID string, c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment("contacts.csv")
+
+	ctx := c.Context()
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.contactsSvc.Export(ctx, userID, w); err != nil {
+			h.Logger.Error("failed to export contacts", zap.String("user_id", userID), zap.Error(err))
+		}
+	})
+
+	return nil
+}
+
+//	@Summary		Create contact group
+//	@Description	Creates an empty contact group
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		GroupRequest				true	"Group"
+//	@Success		201		{object}	GroupResponse				"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Group with the same name already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			201		{string}	Location					"Get group URL"
+//	@Router			/3rdparty/v1/contacts/groups [post]
+//
+// Create contact group.
+func (h *ThirdPartyController) postGroup(userID string, c *fiber.Ctx) error {
+	req := new(GroupRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	group, err := h.contactsSvc.CreateGroup(c.Context(), userID, req.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create contact group: %w", err)
+	}
+
+	h.setLocation(c, route3rdPartyGetGroup, group.ID)
+
+	return c.Status(fiber.StatusCreated).JSON(newGroupResponse(*group))
+}
+
+//	@Summary		List contact groups
+//	@Description	Returns contact groups ordered by name
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			limit	query		int							false	"Pagination limit"	default(50)	minimum(1)	maximum(100)
+//	@Param			offset	query		int							false	"Pagination offset"	default(0)
+//	@Success		200		{object}	[]GroupResponse				"Group list"
+//	@Header			200		{integer}	X-Total-Count				"Total number of items available"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups [get]
+//
+// List contact groups.
+func (h *ThirdPartyController) listGroups(userID string, c *fiber.Ctx) error {
+	params := new(listQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	items, total, err := h.contactsSvc.ListGroups(
+		c.Context(),
+		userID,
+		lo.FromPtrOr(params.Limit, defaultLimit),
+		lo.FromPtr(params.Offset),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list contact groups: %w", err)
+	}
+
+	c.Set("X-Total-Count", strconv.Itoa(int(total)))
+	return c.JSON(lo.Map(items, func(item contacts.Group, _ int) GroupResponse {
+		return newGroupResponse(item)
+	}))
+}
+
+//	@Summary		Get contact group
+//	@Description	Returns the contact group. Use the contacts list with `groupId` to get its members.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path		string						true	"Group ID"
+//	@Success		200	{object}	GroupResponse				"Group"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id} [get]
+//
+// Get contact group.
+func (h *ThirdPartyController) getGroup(userID string, c *fiber.Ctx) error {
+	group, err := h.contactsSvc.GetGroup(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to get contact group: %w", err)
+	}
+
+	return c.JSON(newGroupResponse(*group))
+}
+
+//	@Summary		Rename contact group
+//	@Description	Renames the contact group
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Group ID"
+//	@Param			request	body		GroupRequest				true	"Group"
+//	@Success		200		{object}	GroupResponse				"Group"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Group with the same name already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id} [put]
+//
+// Rename contact group.
+func (h *ThirdPartyController) putGroup(userID string, c *fiber.Ctx) error {
+	req := new(GroupRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	group, err := h.contactsSvc.RenameGroup(c.Context(), userID, c.Params("id"), req.Name)
+	if err != nil {
+		return fmt.Errorf("failed to rename contact group: %w", err)
+	}
+
+	return c.JSON(newGroupResponse(*group))
+}
+
+//	@Summary		Delete contact group
+//	@Description	Deletes the contact group. Its contacts are kept.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path	string	true	"Group ID"
+//	@Success		204	"Successfully removed"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id} [delete]
+//
+// Delete contact group.
+func (h *ThirdPartyController) deleteGroup(userID string, c *fiber.Ctx) error {
+	if err := h.contactsSvc.DeleteGroup(c.Context(), userID, c.Params("id")); err != nil {
+		return fmt.Errorf("failed to delete contact group: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Add group members
+//	@Description	Adds the contacts to the group. Contacts that are already members are skipped.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Group ID"
+//	@Param			request	body		GroupMembersRequest			true	"Contacts"
+//	@Success		200		{object}	GroupResponse				"Group"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id}/members [post]
+//
+// Add group members.
+func (h *ThirdPartyController) postMembers(userID string, c *fiber.Ctx) error {
+	req := new(GroupMembersRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	group, err := h.contactsSvc.AddMembers(c.Context(), userID, c.Params("id"), req.ContactIDs)
+	if err != nil {
+		return fmt.Errorf("failed to add group members: %w", err)
+	}
+
+	return c.JSON(newGroupResponse(*group))
+}
+
+//	@Summary		Remove group members
+//	@Description	Removes the contacts from the group. The contacts themselves are kept.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Group ID"
+//	@Param			request	body		GroupMembersRequest			true	"Contacts"
+//	@Success		200		{object}	GroupResponse				"Group"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id}/members [delete]
+//
+// Remove group members.
+func (h *ThirdPartyController) deleteMembers(userID string, c *fiber.Ctx) error {
+	req := new(GroupMembersRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	group, err := h.contactsSvc.RemoveMembers(c.Context(), userID, c.Params("id"), req.ContactIDs)
+	if err != nil {
+		return fmt.Errorf("failed to remove group members: %w", err)
+	}
+
+	return c.JSON(newGroupResponse(*group))
+}
+
+func (h *ThirdPartyController) setLocation(c *fiber.Ctx, route, id string) {
+	location, err := c.GetRouteURL(route, fiber.Map{
+		"id": id,
+	})
+	if err != nil {
+		h.Logger.Warn("failed to get route URL", zap.String("route", route), zap.String("id", id), zap.Error(err))
+		return
+	}
+
+	c.Location(location)
+}
+
+func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError
+	}
+
+	var validationError contacts.ValidationError
+	switch {
+	case errors.As(err, &validationError):
+		return fiber.NewError(fiber.StatusBadRequest, validationError.Error())
+	case errors.Is(err, contacts.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, contacts.ErrNotFound.Error())
+	case errors.Is(err, contacts.ErrGroupNotFound):
+		return fiber.NewError(fiber.StatusNotFound, contacts.ErrGroupNotFound.Error())
+	case errors.Is(err, contacts.ErrContactExists):
+		return fiber.NewError(fiber.StatusConflict, contacts.ErrContactExists.Error())
+	case errors.Is(err, contacts.ErrGroupExists):
+		return fiber.NewError(fiber.StatusConflict, contacts.ErrGroupExists.Error())
+	}
+
+	h.Logger.Error("failed to handle request", zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "failed to handle request")
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Use(h.errorHandler)
+
+	router.Get("", permissions.RequireScope(ScopeList), userauth.WithUserID(h.list))
+	router.Post("", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.post))
+	router.Post("import", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.postImport))
+	router.Get("export", permissions.RequireScope(ScopeExport), userauth.WithUserID(h.getExport))
+
+	router.Get("groups", permissions.RequireScope(ScopeList), userauth.WithUserID(h.listGroups))
+	router.Post("groups", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.postGroup))
+	router.Get("groups/:id", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.getGroup)).
+		Name(route3rdPartyGetGroup)
+	router.Put("groups/:id", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.putGroup))
+	router.Delete("groups/:id", permissions.RequireScope(ScopeDelete), userauth.WithUserID(h.deleteGroup))
+	router.Post("groups/:id/members", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.postMembers))
+	router.Delete("groups/:id/members", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.deleteMembers))
+
+	router.Get(":id", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.get)).Name(route3rdPartyGetContact)
+	router.Put(":id", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.put))
+	router.Delete(":id", permissions.RequireScope(ScopeDelete), userauth.WithUserID(h.delete))
+}
@@ -0,0 +1,9 @@
+package contacts
+
+const (
+	ScopeList   = "contacts:list"
+	ScopeRead   = "contacts:read"
+	ScopeWrite  = "contacts:write"
+	ScopeDelete = "contacts:delete"
+	ScopeExport = "contacts:export"
+)
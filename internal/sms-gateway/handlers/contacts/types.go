@@ -0,0 +1,125 @@
+package contacts
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+)
+
+// ContactRequest describes a contact.
+type ContactRequest struct {
+	// Contact name, available as the `{{name}}` placeholder
+	Name string `json:"name,omitempty" validate:"omitempty,max=128"`
+	// Phone number, unique among the contacts
+	PhoneNumber string `json:"phoneNumber" validate:"required,min=3,max=128"`
+	// Custom fields available as `{{field}}` placeholders in personalized messages
+	Fields map[string]string `json:"fields,omitempty" validate:"omitempty,max=32"`
+	// Tags
+	Tags []string `json:"tags,omitempty" validate:"omitempty,max=32,dive,max=64"`
+}
+
+func (r *ContactRequest) ToDomain() contacts.ContactInput {
+	return contacts.ContactInput{
+		Name:        r.Name,
+		PhoneNumber: r.PhoneNumber,
+		Fields:      r.Fields,
+		Tags:        r.Tags,
+	}
+}
+
+// ContactResponse describes a contact.
+type ContactResponse struct {
+	// Contact ID
+	ID string `json:"id"`
+	// Contact name
+	Name string `json:"name"`
+	// Normalized phone number
+	PhoneNumber string `json:"phoneNumber"`
+	// Custom fields
+	Fields map[string]string `json:"fields"`
+	// Tags
+	Tags []string `json:"tags"`
+	// Creation time
+	CreatedAt time.Time `json:"createdAt"`
+	// Last update time
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func newContactResponse(contact contacts.Contact) ContactResponse {
+	return ContactResponse{
+		ID:          contact.ID,
+		Name:        contact.Name,
+		PhoneNumber: contact.PhoneNumber,
+		Fields:      contact.Fields,
+		Tags:        contact.Tags,
+		CreatedAt:   contact.CreatedAt,
+		UpdatedAt:   contact.UpdatedAt,
+	}
+}
+
+// GroupRequest describes a contact group.
+type GroupRequest struct {
+	// Group name, unique among the groups
+	Name string `json:"name" validate:"required,max=128"`
+}
+
+// GroupMembersRequest lists the contacts to add to or remove from a group.
+type GroupMembersRequest struct {
+	// Contact IDs
+	ContactIDs []string `json:"contactIds" validate:"required,min=1,max=1000,dive,len=21"`
+}
+
+// GroupResponse describes a contact group.
+type GroupResponse struct {
+	// Group ID
+	ID string `json:"id"`
+	// Group name
+	Name string `json:"name"`
+	// Number of contacts in the group
+	Members int64 `json:"members"`
+	// Creation time
+	CreatedAt time.Time `json:"createdAt"`
+	// Last update time
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func newGroupResponse(group contacts.Group) GroupResponse {
+	return GroupResponse{
+		ID:        group.ID,
+		Name:      group.Name,
+		Members:   group.Members,
+		CreatedAt: group.CreatedAt,
+		UpdatedAt: group.UpdatedAt,
+	}
+}
+
+// ImportResponse is the outcome of a CSV import.
+type ImportResponse struct {
+	// Number of new contacts
+	Created int `json:"created"`
+	// Number of existing contacts replaced by the rows with the same phone number
+	Updated int `json:"updated"`
+}
+
+type listQueryParams struct {
+	Limit  *int `query:"limit"  validate:"omitempty,min=1,max=100"`
+	Offset *int `query:"offset" validate:"omitempty,min=0"`
+}
+
+type contactsQueryParams struct {
+	Limit   *int   `query:"limit"   validate:"omitempty,min=1,max=100"`
+	Offset  *int   `query:"offset"  validate:"omitempty,min=0"`
+	Tag     string `query:"tag"     validate:"omitempty,max=64"`
+	GroupID string `query:"groupId" validate:"omitempty,len=21"`
+}
+
+func (p *contactsQueryParams) ToFilter() contacts.ListFilter {
+	return contacts.ListFilter{
+		Tag:     p.Tag,
+		GroupID: p.GroupID,
+	}
+}
+
+type importQueryParams struct {
+	GroupID string `query:"groupId" validate:"omitempty,len=21"`
+}
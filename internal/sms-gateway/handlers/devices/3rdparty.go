@@ -1,15 +1,23 @@
 package devices
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
-	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
+	messagesHandler "github.com/android-sms-gateway/server/internal/sms-gateway/handlers/messages"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/sse"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/online"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/samber/lo"
@@ -19,11 +27,25 @@ import (
 type ThirdPartyController struct {
 	base.Handler
 
-	devicesSvc *devices.Service
+	devicesSvc   *devices.Service
+	eventsSvc    *events.Service
+	telemetrySvc *telemetry.Service
+	alertsSvc    *alerts.Service
+	messagesSvc  *messages.Service
+	sseSvc       *sse.Service
+	pushSvc      *push.Service
+	onlineSvc    online.Service
 }
 
 func NewThirdPartyController(
 	devicesSvc *devices.Service,
+	eventsSvc *events.Service,
+	telemetrySvc *telemetry.Service,
+	alertsSvc *alerts.Service,
+	messagesSvc *messages.Service,
+	sseSvc *sse.Service,
+	pushSvc *push.Service,
+	onlineSvc online.Service,
 	logger *zap.Logger,
 	validator *validator.Validate,
 ) *ThirdPartyController {
@@ -32,17 +54,25 @@ func NewThirdPartyController(
 			Logger:    logger,
 			Validator: validator,
 		},
-		devicesSvc: devicesSvc,
+		devicesSvc:   devicesSvc,
+		eventsSvc:    eventsSvc,
+		telemetrySvc: telemetrySvc,
+		alertsSvc:    alertsSvc,
+		messagesSvc:  messagesSvc,
+		sseSvc:       sseSvc,
+		pushSvc:      pushSvc,
+		onlineSvc:    onlineSvc,
 	}
 }
 
 //	@Summary		List devices
-//	@Description	Returns list of registered devices
+//	@Description	Returns list of registered devices with their latest telemetry and real-time connectivity
 //	@Security		ApiAuth
 //	@Security		JWTAuth
 //	@Tags			User, Devices
 //	@Produce		json
-//	@Success		200	{object}	[]smsgateway.Device			"Device list"
+//	@Param			tag	query		string						false	"Filter by group"	maxLength(32)
+//	@Success		200	{object}	[]Device					"Device list"
 //	@Failure		400	{object}	smsgateway.ErrorResponse	"Invalid request"
 //	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
 //	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
@@ -51,17 +81,300 @@ func NewThirdPartyController(
 //
 // List devices.
 func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
-	items, err := h.devicesSvc.Select(c.Context(), userID)
+	params := new(listQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var filter []devices.SelectFilter
+	if params.Tag != "" {
+		filter = append(filter, devices.WithTag(params.Tag))
+	}
+
+	items, err := h.devicesSvc.Select(c.Context(), userID, filter...)
 	if err != nil {
 		return fmt.Errorf("failed to select devices: %w", err)
 	}
 
-	return c.JSON(lo.Map(
-		items,
-		func(device devices.Device, _ int) smsgateway.Device {
-			return converters.DeviceToDTO(device)
-		},
-	))
+	result, err := h.newDevices(c.Context(), items)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
+
+//	@Summary		Update device
+//	@Description	Renames, pauses or resumes the device. Messages are not routed to a paused device: it is skipped when a device is chosen randomly and refused when requested by `deviceId`.
+//	@Description	Messages already queued for a paused device are held back and delivered after it is resumed.
+//	@Description	The device is notified about the change.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			request	body		PatchDeviceRequest			true	"Device changes"
+//	@Success		200		{object}	Device						"Device"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id} [patch]
+//
+// Update device.
+func (h *ThirdPartyController) patch(userID string, c *fiber.Ctx) error {
+	req := new(PatchDeviceRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	device, err := h.devicesSvc.Patch(c.Context(), userID, c.Params("id"), req.toDomain())
+	var validationErr devices.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return fiber.NewError(fiber.StatusBadRequest, validationErr.Error())
+	case errors.Is(err, devices.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case err != nil:
+		return fmt.Errorf("failed to update device: %w", err)
+	}
+
+	event := events.NewDeviceUpdatedEvent(
+		device.IsPaused(),
+		lo.FromPtr(device.PauseReason),
+		lo.FromPtr(device.Name),
+	)
+	if notifyErr := h.eventsSvc.Notify(userID, &device.ID, event); notifyErr != nil {
+		h.Logger.Error("failed to notify device", zap.String("device_id", device.ID), zap.Error(notifyErr))
+	}
+
+	// the held back messages are fetched on resume
+	if req.Paused != nil && !*req.Paused {
+		if notifyErr := h.eventsSvc.Notify(userID, &device.ID, events.NewMessageEnqueuedEvent()); notifyErr != nil {
+			h.Logger.Error("failed to notify device", zap.String("device_id", device.ID), zap.Error(notifyErr))
+		}
+	}
+
+	result, err := h.newDevices(c.Context(), []devices.Device{*device})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result[0])
+}
+
+//	@Summary		Set device groups
+//	@Description	Replaces the groups of the device. Groups are addressed by name when enqueueing messages and scoping webhooks and settings.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			request	body		SetTagsRequest				true	"Device groups"
+//	@Success		200		{object}	Device						"Device"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/tags [put]
+//
+// Set device groups.
+func (h *ThirdPartyController) putTags(userID string, c *fiber.Ctx) error {
+	req := new(SetTagsRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	device, err := h.devicesSvc.SetTags(c.Context(), userID, c.Params("id"), req.Tags)
+	var validationErr devices.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return fiber.NewError(fiber.StatusBadRequest, validationErr.Error())
+	case errors.Is(err, devices.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case err != nil:
+		return fmt.Errorf("failed to set device tags: %w", err)
+	}
+
+	// group-scoped webhooks and settings that apply to the device have changed
+	for _, event := range []events.Event{events.NewWebhooksUpdatedEvent(), events.NewSettingsUpdatedEvent()} {
+		if notifyErr := h.eventsSvc.Notify(userID, &device.ID, event); notifyErr != nil {
+			h.Logger.Error("failed to notify device", zap.String("device_id", device.ID), zap.Error(notifyErr))
+		}
+	}
+
+	result, err := h.newDevices(c.Context(), []devices.Device{*device})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result[0])
+}
+
+//	@Summary		List device groups
+//	@Description	Returns the groups of the user's devices with the number of devices in each
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Success		200	{object}	[]Group						"Group list"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/groups [get]
+//
+// List device groups.
+func (h *ThirdPartyController) getGroups(userID string, c *fiber.Ctx) error {
+	groups, err := h.devicesSvc.Groups(c.Context(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to select device groups: %w", err)
+	}
+
+	return c.JSON(lo.Map(groups, func(group devices.Group, _ int) Group { return newGroup(group) }))
+}
+
+//	@Summary		Get device telemetry
+//	@Description	Returns the telemetry reported by the device, newest first. Only a limited number of the latest reports is kept.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			from	query		string						false	"Start of the period (RFC 3339)"	format(date-time)
+//	@Param			to		query		string						false	"End of the period, exclusive (RFC 3339)"	format(date-time)
+//	@Param			limit	query		int							false	"Max number of reports"	minimum(1)	maximum(1000)	default(100)
+//	@Success		200		{object}	[]TelemetrySample			"Telemetry history"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/telemetry [get]
+//
+// Get device telemetry.
+func (h *ThirdPartyController) getTelemetry(userID string, c *fiber.Ctx) error {
+	params := new(telemetryQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	samples, err := h.telemetrySvc.History(c.Context(), userID, c.Params("id"), params.toFilter())
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get device telemetry: %w", err)
+	}
+
+	return c.JSON(lo.Map(samples, func(sample telemetry.Sample, _ int) TelemetrySample { return newTelemetrySample(sample) }))
+}
+
+//	@Summary		Get device queue
+//	@Description	Returns the pending and cancelling messages of the device in the order the device receives them: by schedule time, then by priority, then newest (lifo) or oldest (fifo) first.
+//	@Description	Paced messages are listed at their delivery slots and held until the slot arrives. The limiter verdict is the one computed on the latest enqueue.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			order	query		string						false	"Processing order of the device"	Enums(lifo,fifo)	default(lifo)
+//	@Param			limit	query		int							false	"Max number of messages"	minimum(1)	maximum(500)	default(100)
+//	@Param			offset	query		int							false	"Number of messages to skip"	minimum(0)	default(0)
+//	@Success		200		{object}	DeviceQueue					"Device queue"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/queue [get]
+//
+// Get device queue.
+func (h *ThirdPartyController) getQueue(userID string, c *fiber.Ctx) error {
+	const defaultLimit = 100
+
+	params := new(queueQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	device, err := h.devicesSvc.Get(c.Context(), userID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get device: %w", err)
+	}
+
+	queue, err := h.messagesSvc.Queue(
+		c.Context(),
+		device.ID,
+		messages.Order(params.Order),
+		lo.FromPtrOr(params.Limit, defaultLimit),
+		lo.FromPtr(params.Offset),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get device queue: %w", err)
+	}
+
+	return c.JSON(newDeviceQueue(*queue, time.Now()))
+}
+
+//	@Summary		Get device connectivity
+//	@Description	Returns the real-time connectivity of the device: whether it holds an SSE connection to any server replica, the state of its push token and the latest time it was seen, including the activity not persisted to `lastSeen` of the device yet.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id	path		string						true	"Device ID"
+//	@Success		200	{object}	Connectivity				"Device connectivity"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/status [get]
+//
+// Get device connectivity.
+func (h *ThirdPartyController) getStatus(userID string, c *fiber.Ctx) error {
+	device, err := h.devicesSvc.Get(c.Context(), userID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get device: %w", err)
+	}
+
+	return c.JSON(h.connectivity(c.Context(), *device))
+}
+
+//	@Summary		Revoke device credentials
+//	@Description	Invalidates the auth tokens of the device immediately, including a token replaced by rotation. The device keeps its history but has to be registered again, e.g. with a one-time code.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id	path	string	true	"Device ID"
+//	@Success		204	"Successfully revoked"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/token [delete]
+//
+// Revoke device credentials.
+func (h *ThirdPartyController) revokeToken(userID string, c *fiber.Ctx) error {
+	err := h.devicesSvc.RevokeTokens(c.Context(), userID, c.Params("id"))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke device credentials: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
 //	@Summary		Remove device
@@ -94,7 +407,69 @@ func (h *ThirdPartyController) remove(userID string, c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// newDevices converts the devices adding their latest telemetry and connectivity.
+func (h *ThirdPartyController) newDevices(ctx context.Context, items []devices.Device) ([]Device, error) {
+	latest, err := h.telemetrySvc.Latest(ctx, lo.Map(items, func(device devices.Device, _ int) string { return device.ID }))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest telemetry: %w", err)
+	}
+
+	result := make([]Device, 0, len(items))
+	for _, device := range items {
+		sample, ok := latest[device.ID]
+		result = append(result, newDevice(device, lo.Ternary(ok, &sample, nil), h.connectivity(ctx, device)))
+	}
+
+	return result, nil
+}
+
+// connectivity collects the live state of the device shared by the replicas.
+// The state that can't be read is reported as unknown, so a failing presence,
+// push or online store doesn't break the device endpoints.
+func (h *ThirdPartyController) connectivity(ctx context.Context, device devices.Device) Connectivity {
+	var connected *bool
+	if isConnected, err := h.sseSvc.IsConnected(ctx, device.ID); err != nil {
+		h.Logger.Error("failed to get connection state", zap.String("device_id", device.ID), zap.Error(err))
+	} else {
+		connected = &isConnected
+	}
+
+	pushToken := pushTokenNone
+	if device.PushToken != nil {
+		blacklisted, err := h.pushSvc.IsBlacklisted(ctx, *device.PushToken)
+		if err != nil {
+			h.Logger.Error("failed to get push token state", zap.String("device_id", device.ID), zap.Error(err))
+			pushToken = pushTokenUnknown
+		} else {
+			pushToken = lo.Ternary(blacklisted, pushTokenBlacklisted, pushTokenActive)
+		}
+	}
+
+	lastSeen, err := h.onlineSvc.LastSeen(ctx, device.ID)
+	if err != nil {
+		h.Logger.Error("failed to get last seen", zap.String("device_id", device.ID), zap.Error(err))
+	}
+
+	return newConnectivity(device, connected, pushToken, lastSeen)
+}
+
 func (h *ThirdPartyController) Register(router fiber.Router) {
 	router.Get("", permissions.RequireScope(ScopeList), userauth.WithUserID(h.get))
+	router.Get("groups", permissions.RequireScope(ScopeList), userauth.WithUserID(h.getGroups))
+	router.Get("alerts", permissions.RequireScope(ScopeList), userauth.WithUserID(h.getAlerts))
+	router.Put("alerts", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.putAlerts))
+	router.Delete("alerts", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.deleteAlerts))
+	router.Get("incidents", permissions.RequireScope(ScopeList), userauth.WithUserID(h.getIncidents))
+	router.Patch(":id", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.patch))
+	router.Put(":id/tags", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.putTags))
+	router.Get(":id/telemetry", permissions.RequireScope(ScopeList), userauth.WithUserID(h.getTelemetry))
+	router.Get(":id/status", permissions.RequireScope(ScopeList), userauth.WithUserID(h.getStatus))
+	router.Get(
+		":id/queue",
+		permissions.RequireScope(ScopeList),
+		permissions.RequireScope(messagesHandler.ScopeList),
+		userauth.WithUserID(h.getQueue),
+	)
+	router.Delete(":id/token", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.revokeToken))
 	router.Delete(":id", permissions.RequireScope(ScopeDelete), userauth.WithUserID(h.remove))
 }
@@ -0,0 +1,127 @@
+package devices
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+//	@Summary		Get offline alerts
+//	@Description	Returns the settings of the notifications about devices that stop checking in
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Success		200	{object}	OfflineAlerts				"Offline alerts"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Offline alerts are not configured"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/alerts [get]
+//
+// Get offline alerts.
+func (h *ThirdPartyController) getAlerts(userID string, c *fiber.Ctx) error {
+	settings, err := h.alertsSvc.GetSettings(c.Context(), userID)
+	if errors.Is(err, alerts.ErrNotConfigured) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get offline alerts: %w", err)
+	}
+
+	return c.JSON(newOfflineAlerts(*settings))
+}
+
+//	@Summary		Set offline alerts
+//	@Description	Enables the notifications about devices that stop checking in. A device not seen for `offlineAfterSeconds` is offline: the callback URL receives a `device:offline` POST request and/or a message is sent to the email.
+//	@Description	Once the device checks in again and stays online for a while, a `device:recovered` notification is sent. A device going offline again in the meantime doesn't cause another alert.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		OfflineAlerts				true	"Offline alerts"
+//	@Success		200		{object}	OfflineAlerts				"Offline alerts"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/alerts [put]
+//
+// Set offline alerts.
+func (h *ThirdPartyController) putAlerts(userID string, c *fiber.Ctx) error {
+	req := new(OfflineAlerts)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	settings, err := h.alertsSvc.ReplaceSettings(c.Context(), userID, req.toDomain())
+	var validationErr alerts.ValidationError
+	if errors.As(err, &validationErr) {
+		return fiber.NewError(fiber.StatusBadRequest, validationErr.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set offline alerts: %w", err)
+	}
+
+	return c.JSON(newOfflineAlerts(*settings))
+}
+
+//	@Summary		Disable offline alerts
+//	@Description	Disables the notifications about devices that stop checking in. Open incidents are closed without notification.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Success		204	"Successfully disabled"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Offline alerts are not configured"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/alerts [delete]
+//
+// Disable offline alerts.
+func (h *ThirdPartyController) deleteAlerts(userID string, c *fiber.Ctx) error {
+	err := h.alertsSvc.DeleteSettings(c.Context(), userID)
+	if errors.Is(err, alerts.ErrNotConfigured) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to disable offline alerts: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		List offline incidents
+//	@Description	Returns the periods the user's devices were offline, newest first
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			deviceId	query		string						false	"Filter by device"	maxLength(21)
+//	@Param			open		query		bool						false	"Only open (`true`) or resolved (`false`) incidents"
+//	@Param			limit		query		int							false	"Max number of incidents"	minimum(1)	maximum(500)	default(50)
+//	@Success		200			{object}	[]Incident					"Incident list"
+//	@Failure		400			{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401			{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500			{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/incidents [get]
+//
+// List offline incidents.
+func (h *ThirdPartyController) getIncidents(userID string, c *fiber.Ctx) error {
+	params := new(incidentsQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	incidents, err := h.alertsSvc.SelectIncidents(c.Context(), userID, params.toFilter())
+	if err != nil {
+		return fmt.Errorf("failed to select incidents: %w", err)
+	}
+
+	return c.JSON(lo.Map(incidents, func(incident alerts.Incident, _ int) Incident { return newIncident(incident) }))
+}
@@ -0,0 +1,70 @@
+package devices
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/deviceauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type MobileController struct {
+	base.Handler
+
+	telemetrySvc *telemetry.Service
+}
+
+func NewMobileController(
+	telemetrySvc *telemetry.Service,
+	logger *zap.Logger,
+	validator *validator.Validate,
+) *MobileController {
+	return &MobileController{
+		Handler: base.Handler{
+			Logger:    logger,
+			Validator: validator,
+		},
+		telemetrySvc: telemetrySvc,
+	}
+}
+
+//	@Summary		Report telemetry
+//	@Description	Stores the current state of the device. The latest state is shown in the device list, the history is kept for a limited number of reports.
+//	@Security		MobileToken
+//	@Tags			Device
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	Telemetry	true	"Device state"
+//	@Success		204		"Successfully stored"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		429		{object}	smsgateway.ErrorResponse	"Reported too frequently"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/telemetry [post]
+//
+// Report telemetry.
+func (h *MobileController) postTelemetry(device devices.Device, c *fiber.Ctx) error {
+	req := new(Telemetry)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	err := h.telemetrySvc.Report(c.Context(), device, req.toDomain())
+	if errors.Is(err, telemetry.ErrTooFrequent) {
+		return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to report telemetry: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *MobileController) Register(router fiber.Router) {
+	router.Post("", deviceauth.WithDevice(h.postTelemetry))
+}
@@ -4,5 +4,6 @@ import "github.com/android-sms-gateway/client-go/smsgateway"
 
 const (
 	ScopeList   = smsgateway.ScopeDevicesList
+	ScopeWrite  = "devices:write"
 	ScopeDelete = smsgateway.ScopeDevicesDelete
 )
@@ -0,0 +1,354 @@
+package devices
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/samber/lo"
+)
+
+// Device extends smsgateway.Device with the device groups, the pause state, the latest telemetry and the connectivity.
+type Device struct {
+	smsgateway.Device
+
+	// Approval state, messages are not routed to a device pending approval
+	Status string `json:"status" enums:"active,pending_approval"`
+	// Groups the device belongs to
+	Tags []string `json:"tags"`
+	// Messages are not routed to a paused device
+	Paused bool `json:"paused"`
+	// Time the device was paused
+	PausedAt *time.Time `json:"pausedAt,omitempty"`
+	// Why the device is paused
+	PauseReason *string `json:"pauseReason,omitempty"`
+	// Latest telemetry reported by the device
+	Telemetry *TelemetrySample `json:"telemetry,omitempty"`
+	// Real-time connectivity
+	Connectivity Connectivity `json:"connectivity"`
+}
+
+func newDevice(device devices.Device, sample *telemetry.Sample, connectivity Connectivity) Device {
+	var latest *TelemetrySample
+	if sample != nil {
+		latest = lo.ToPtr(newTelemetrySample(*sample))
+	}
+
+	return Device{
+		Device:       converters.DeviceToDTO(device),
+		Status:       string(device.Status),
+		Tags:         device.Tags,
+		Paused:       device.IsPaused(),
+		PausedAt:     device.PausedAt,
+		PauseReason:  device.PauseReason,
+		Telemetry:    latest,
+		Connectivity: connectivity,
+	}
+}
+
+const (
+	pushTokenNone        = "none"
+	pushTokenActive      = "active"
+	pushTokenBlacklisted = "blacklisted"
+	pushTokenUnknown     = "unknown"
+)
+
+// Connectivity is the real-time state of the device connection.
+type Connectivity struct {
+	// Whether the device holds an SSE connection to any server replica, `null` if the state is unavailable
+	Connected *bool `json:"connected"`
+	// Push token state: `none` - events are delivered over SSE, `active`, `blacklisted` - delivery failed repeatedly, events are dropped for a while, `unknown` - the state is unavailable
+	PushToken string `json:"pushToken" enums:"none,active,blacklisted,unknown"`
+	// Latest time the device was seen, including the activity not persisted yet when available
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+func newConnectivity(device devices.Device, connected *bool, pushToken string, lastSeen *time.Time) Connectivity {
+	seen := device.LastSeen
+	if lastSeen != nil && lastSeen.After(seen) {
+		seen = *lastSeen
+	}
+
+	return Connectivity{
+		Connected: connected,
+		PushToken: pushToken,
+		LastSeen:  seen,
+	}
+}
+
+// PatchDeviceRequest changes the device. Omitted fields are left unchanged.
+type PatchDeviceRequest struct {
+	// Display name, empty to clear
+	Name *string `json:"name,omitempty" validate:"omitempty,max=128"`
+	// Pause or resume the device
+	Paused *bool `json:"paused,omitempty"`
+	// Why the device is paused, only with `paused: true`
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=256"`
+}
+
+func (r PatchDeviceRequest) toDomain() devices.DevicePatch {
+	return devices.DevicePatch{
+		Name:   r.Name,
+		Paused: r.Paused,
+		Reason: r.Reason,
+	}
+}
+
+// SetTagsRequest replaces the groups of a device.
+type SetTagsRequest struct {
+	// Groups the device belongs to, letters, digits, `_`, `.` and `-` only
+	Tags []string `json:"tags" validate:"max=16,dive,min=1,max=32"`
+}
+
+// Group is a device group.
+type Group struct {
+	// Group name
+	Name string `json:"name"`
+	// Number of devices in the group
+	Devices int `json:"devices"`
+}
+
+func newGroup(group devices.Group) Group {
+	return Group(group)
+}
+
+type listQueryParams struct {
+	Tag string `query:"tag" validate:"omitempty,max=32"`
+}
+
+// Signal is the signal quality of a SIM.
+type Signal struct {
+	// SIM number, starting from 1
+	SimNumber int `json:"simNumber" validate:"min=1,max=255"`
+	// Signal level from 0 to 4 as shown in the status bar
+	Level *int `json:"level,omitempty" validate:"omitempty,min=0,max=4"`
+	// Signal strength in dBm
+	Dbm *int `json:"dbm,omitempty" validate:"omitempty,min=-200,max=0"`
+}
+
+// Telemetry is the state of a device. Values unknown to the device are omitted.
+type Telemetry struct {
+	// Battery level in percent
+	BatteryLevel *int `json:"batteryLevel,omitempty" validate:"omitempty,min=0,max=100"`
+	// The device is charging
+	Charging *bool `json:"charging,omitempty"`
+	// Signal quality per SIM
+	Signals []Signal `json:"signals,omitempty" validate:"max=8,unique=SimNumber,dive"`
+	// Free storage in bytes
+	FreeStorage *int64 `json:"freeStorage,omitempty" validate:"omitempty,min=0"`
+	// App version
+	AppVersion *string `json:"appVersion,omitempty" validate:"omitempty,max=32"`
+	// Messages waiting in the local queue of the device
+	QueueLength *int `json:"queueLength,omitempty" validate:"omitempty,min=0,max=1000000"`
+}
+
+func (t Telemetry) toDomain() telemetry.Report {
+	return telemetry.Report{
+		BatteryLevel: t.BatteryLevel,
+		Charging:     t.Charging,
+		Signals:      lo.Map(t.Signals, func(item Signal, _ int) telemetry.Signal { return telemetry.Signal(item) }),
+		FreeStorage:  t.FreeStorage,
+		AppVersion:   t.AppVersion,
+		QueueLength:  t.QueueLength,
+	}
+}
+
+// TelemetrySample is the telemetry reported by a device at a time.
+type TelemetrySample struct {
+	Telemetry
+
+	// Time the telemetry was received
+	ReportedAt time.Time `json:"reportedAt"`
+}
+
+func newTelemetrySample(sample telemetry.Sample) TelemetrySample {
+	return TelemetrySample{
+		Telemetry: Telemetry{
+			BatteryLevel: sample.BatteryLevel,
+			Charging:     sample.Charging,
+			Signals:      lo.Map(sample.Signals, func(item telemetry.Signal, _ int) Signal { return Signal(item) }),
+			FreeStorage:  sample.FreeStorage,
+			AppVersion:   sample.AppVersion,
+			QueueLength:  sample.QueueLength,
+		},
+		ReportedAt: sample.CreatedAt,
+	}
+}
+
+type telemetryQueryParams struct {
+	From  *time.Time `query:"from"`
+	To    *time.Time `query:"to"`
+	Limit *int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+func (p *telemetryQueryParams) toFilter() telemetry.HistoryFilter {
+	return telemetry.HistoryFilter{
+		From:  lo.FromPtr(p.From),
+		To:    lo.FromPtr(p.To),
+		Limit: lo.FromPtr(p.Limit),
+	}
+}
+
+// OfflineAlerts configures the notifications about devices that stop checking in.
+// At least one of `callbackUrl` and `email` is required.
+type OfflineAlerts struct {
+	// Seconds since the device was last seen after which it is offline
+	OfflineAfterSeconds uint32 `json:"offlineAfterSeconds" validate:"required,min=120,max=604800"`
+	// HTTPS URL with a public address receiving a POST request for each notification
+	CallbackURL *string `json:"callbackUrl,omitempty" validate:"omitempty,max=2048,https_url"`
+	// Email address receiving a message for each notification
+	Email *string `json:"email,omitempty" validate:"omitempty,max=254,email"`
+}
+
+func newOfflineAlerts(settings alerts.Settings) OfflineAlerts {
+	return OfflineAlerts{
+		OfflineAfterSeconds: uint32(settings.OfflineAfter / time.Second), //nolint:gosec // limited by validation
+		CallbackURL:         settings.CallbackURL,
+		Email:               settings.Email,
+	}
+}
+
+func (a OfflineAlerts) toDomain() alerts.Settings {
+	return alerts.Settings{
+		OfflineAfter: time.Duration(a.OfflineAfterSeconds) * time.Second,
+		CallbackURL:  a.CallbackURL,
+		Email:        a.Email,
+	}
+}
+
+// Incident is a period a device was offline.
+type Incident struct {
+	// Incident ID
+	ID uint64 `json:"id"`
+	// Device ID
+	DeviceID string `json:"deviceId"`
+	// Last time the device was seen before going offline
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	// Time the device was detected offline
+	StartedAt time.Time `json:"startedAt"`
+	// Time the device checked in again
+	ReturnedAt *time.Time `json:"returnedAt,omitempty"`
+	// Time the device was considered recovered after staying online
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	// Number of times the device went offline again before recovering
+	Flaps int `json:"flaps"`
+}
+
+func newIncident(incident alerts.Incident) Incident {
+	return Incident(incident)
+}
+
+type incidentsQueryParams struct {
+	DeviceID string `query:"deviceId" validate:"omitempty,max=21"`
+	Open     *bool  `query:"open"`
+	Limit    *int   `query:"limit"    validate:"omitempty,min=1,max=500"`
+}
+
+func (p *incidentsQueryParams) toFilter() alerts.IncidentsFilter {
+	return alerts.IncidentsFilter{
+		DeviceID: p.DeviceID,
+		Open:     p.Open,
+		Limit:    lo.FromPtr(p.Limit),
+	}
+}
+
+type queueQueryParams struct {
+	Order  string `query:"order"  validate:"omitempty,oneof=lifo fifo"`
+	Limit  *int   `query:"limit"  validate:"omitempty,min=1,max=500"`
+	Offset *int   `query:"offset" validate:"omitempty,min=0"`
+}
+
+// QueuedMessage is a message waiting for the device.
+type QueuedMessage struct {
+	// Message ID
+	ID string `json:"id"`
+	// Message type
+	Type string `json:"type" enums:"Text,Data,MMS"`
+	// Message state
+	State string `json:"state" enums:"Pending,Cancelling"`
+	// Recipients
+	PhoneNumbers []string `json:"phoneNumbers"`
+	// SIM card number, omitted for the default SIM
+	SimNumber *uint8 `json:"simNumber,omitempty"`
+	// Priority
+	Priority int8 `json:"priority"`
+	// Scheduled time or the delivery slot of a paced message
+	ScheduleAt *time.Time `json:"scheduleAt,omitempty"`
+	// A paced message whose delivery slot hasn't arrived yet, the device doesn't receive it until then
+	Held bool `json:"held"`
+	// Campaign the message belongs to
+	CampaignID *string `json:"campaignId,omitempty"`
+	// Creation time
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newQueuedMessage(message messages.QueuedMessage) QueuedMessage {
+	return QueuedMessage{
+		ID:           message.ID,
+		Type:         string(message.Type),
+		State:        string(message.State),
+		PhoneNumbers: message.PhoneNumbers,
+		SimNumber:    message.SimNumber,
+		Priority:     message.Priority,
+		ScheduleAt:   message.ScheduleAt,
+		Held:         message.Held,
+		CampaignID:   message.CampaignID,
+		CreatedAt:    message.CreatedAt,
+	}
+}
+
+// QueueLimit is the verdict of the queue limiter for the current queue.
+type QueueLimit struct {
+	// Whether the queue limits are configured on the server
+	Enabled bool `json:"enabled"`
+	// Whether new messages for the device are refused
+	Exceeded bool `json:"exceeded"`
+	// Why new messages are refused
+	Reason *string `json:"reason,omitempty"`
+}
+
+// DeviceQueue describes the messages waiting for the device.
+type DeviceQueue struct {
+	// Messages waiting for the device
+	Total int64 `json:"total"`
+	// Pending messages
+	Pending int64 `json:"pending"`
+	// Messages being cancelled
+	Cancelling int64 `json:"cancelling"`
+	// Paced messages whose delivery slot hasn't arrived yet
+	Held int64 `json:"held"`
+	// Creation time of the oldest waiting message
+	OldestCreatedAt *time.Time `json:"oldestCreatedAt,omitempty"`
+	// Age of the oldest waiting message in seconds
+	OldestAgeSeconds *int64 `json:"oldestAgeSeconds,omitempty"`
+	// Queue limiter verdict
+	Limit QueueLimit `json:"limit"`
+	// The page of messages in the order the device receives them
+	Messages []QueuedMessage `json:"messages"`
+}
+
+func newDeviceQueue(queue messages.Queue, now time.Time) DeviceQueue {
+	var oldestAge *int64
+	if queue.OldestAt != nil {
+		oldestAge = lo.ToPtr(int64(now.Sub(*queue.OldestAt).Seconds()))
+	}
+
+	return DeviceQueue{
+		Total:            queue.Total(),
+		Pending:          queue.Pending,
+		Cancelling:       queue.Cancelling,
+		Held:             queue.Held,
+		OldestCreatedAt:  queue.OldestAt,
+		OldestAgeSeconds: oldestAge,
+		Limit: QueueLimit{
+			Enabled:  queue.LimitEnabled,
+			Exceeded: queue.LimitReason != nil,
+			Reason:   queue.LimitReason,
+		},
+		Messages: lo.Map(queue.Messages, func(m messages.QueuedMessage, _ int) QueuedMessage { return newQueuedMessage(m) }),
+	}
+}
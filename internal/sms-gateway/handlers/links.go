@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// linksHandler serves the short links of tracked messages.
+type linksHandler struct {
+	messagesSvc *messages.Service
+	logger      *zap.Logger
+}
+
+func newLinksHandler(messagesSvc *messages.Service, logger *zap.Logger) *linksHandler {
+	return &linksHandler{
+		messagesSvc: messagesSvc,
+		logger:      logger,
+	}
+}
+
+//	@Summary		Follow short link
+//	@Description	Redirects to the original URL of a tracked link and records the click
+//	@Tags			Links
+//	@Param			code	path	string	true	"Short link code"
+//	@Success		302		"Redirect to the original URL"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Link not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/s/{code} [get]
+//
+// Follow short link.
+func (h *linksHandler) get(c *fiber.Ctx) error {
+	url, err := h.messagesSvc.ResolveLink(c.Context(), c.Params("code"), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		if errors.Is(err, messages.ErrLinkNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+
+		h.logger.Error("failed to resolve link", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to resolve link")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Redirect(url, fiber.StatusFound)
+}
+
+func (h *linksHandler) Register(router fiber.Router) {
+	router.Get("/s/:code", h.get)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/inbox"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/capcom6/go-helpers/slices"
@@ -24,6 +26,7 @@ import (
 
 const (
 	route3rdPartyGetMessage = "3rdparty.get.message"
+	route3rdPartyGetJob     = "3rdparty.get.messages.job"
 )
 
 type thirdPartyControllerParams struct {
@@ -32,6 +35,9 @@ type thirdPartyControllerParams struct {
 	MessagesSvc *messages.Service
 	DevicesSvc  *devices.Service
 	InboxSvc    *inbox.Service
+	ContactsSvc *contacts.Service
+
+	AttachmentsSvc *attachments.Service
 
 	Validator *validator.Validate
 	Logger    *zap.Logger
@@ -43,6 +49,9 @@ type ThirdPartyController struct {
 	messagesSvc *messages.Service
 	devicesSvc  *devices.Service
 	inboxSvc    *inbox.Service
+	contactsSvc *contacts.Service
+
+	attachmentsSvc *attachments.Service
 }
 
 func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
@@ -55,24 +64,36 @@ func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyContr
 		messagesSvc: params.MessagesSvc,
 		devicesSvc:  params.DevicesSvc,
 		inboxSvc:    params.InboxSvc,
+		contactsSvc: params.ContactsSvc,
+
+		attachmentsSvc: params.AttachmentsSvc,
 	}
 }
 
 //	@Summary		Enqueue message
-//	@Description	Enqueues a message for sending. If `deviceId` is set, the specified device is used; otherwise a random registered device is chosen.
+//	@Description	Enqueues a message for sending. If `deviceId` is set, the specified device is used; otherwise a random registered device is chosen, from `deviceGroup` if set.
+//	@Description	With `fromNumber` or `iccid` the message is sent from the device and slot currently holding that SIM card; an unknown SIM card is rejected.
+//	@Description	Recipients may be given by `contactIds` and `groupIds` in addition to `phoneNumbers`. A personalized text with `{{field}}` placeholders to several recipients must be enqueued with the batch endpoint.
 //	@Security		ApiAuth
 //	@Security		JWTAuth
 //	@Tags			User, Messages
 //	@Accept			json
 //	@Produce		json
 //	@Param			skipPhoneValidation	query		bool							false	"Skip phone validation"
+//	@Param			phoneRegion			query		string							false	"Region for phone numbers without a country code, overrides the user and server defaults"	minLength(2)	maxLength(2)
+//	@Param			normalize			query		string							false	"Comma-separated text normalization rules applied before enqueueing: punctuation, cyrillic, greek, latin, strip; `none` disables the user default"
+//	@Param			trackLinks			query		bool							false	"Replace links in the text with short links counting clicks, the message must have a single recipient"
 //	@Param			deviceActiveWithin	query		int								false	"Filter devices active within the specified number of hours"	default(0)	minimum(0)
-//	@Param			request				body		smsgateway.Message				true	"Send message request"
-//	@Success		202					{object}	smsgateway.GetMessageResponse	"Message enqueued"
+//	@Param			dripWindow			query		int								false	"Spread the messages evenly over the specified number of seconds"	minimum(0)	maximum(604800)
+//	@Param			dripRate			query		int								false	"Maximum number of messages per hour for the device or SIM"	minimum(1)	maximum(3600)
+//	@Param			dripPerSim			query		bool							false	"Apply the drip rate to each SIM separately"
+//	@Param			dripTimezone		query		string							false	"IANA timezone of the device work hours"	default(UTC)
+//	@Param			request				body		EnqueueMessageRequest			true	"Send message request"
+//	@Success		202					{object}	GetMessageResponse				"Message enqueued"
 //	@Failure		400					{object}	smsgateway.ErrorResponse		"Invalid request"
 //	@Failure		401					{object}	smsgateway.ErrorResponse		"Unauthorized"
 //	@Failure		403					{object}	smsgateway.ErrorResponse		"Forbidden"
-//	@Failure		409					{object}	smsgateway.ErrorResponse		"Message with the same ID already exists"
+//	@Failure		409					{object}	smsgateway.ErrorResponse		"Message with the same ID already exists, the message is a duplicate, see `data.originalId`, or the device is paused or pending approval"
 //	@Failure		500					{object}	smsgateway.ErrorResponse		"Internal server error"
 //	@Failure		503					{object}	smsgateway.ErrorResponse		"Queue limits exceeded; ensure device is online"
 //	@Header			202					{string}	Location						"Get message state URL"
@@ -85,16 +106,63 @@ func (h *ThirdPartyController) post(userID string, c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	var req smsgateway.Message
-	if err := h.BodyParserValidator(c, &req); err != nil {
+	var dripParams dripQueryParams
+	if err := h.QueryParserValidator(c, &dripParams); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	drip, err := dripParams.ToOptions()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var phoneParams phoneQueryParams
+	if err := h.QueryParserValidator(c, &phoneParams); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var normalizeParams normalizeQueryParams
+	if err := h.QueryParserValidator(c, &normalizeParams); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	device, err := h.devicesSvc.GetAny(
+	var linksParams linksQueryParams
+	if err := h.QueryParserValidator(c, &linksParams); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	normalize, err := normalizeParams.ToOptions()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var body EnqueueMessageRequest
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	items, mmsContent, err := h.parseEnqueueRequest(c.Context(), userID, body, lo.FromPtr(phoneParams.PhoneRegion))
+	if err != nil {
+		return err
+	}
+	if len(items) > 1 {
+		return fiber.NewError(
+			fiber.StatusBadRequest,
+			"personalized message with several recipients must be enqueued with the batch endpoint",
+		)
+	}
+	req := items[0]
+
+	msg, ok := newMessageInput(req, mmsContent)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "No message content provided")
+	}
+
+	device, err := h.selectDevice(
 		c.Context(),
 		userID,
 		req.DeviceID,
 		time.Duration(lo.FromPtrOr(params.DeviceActiveWithin, 0))*time.Hour,
+		body.DeviceRouting,
+		&msg,
 	)
 	if err != nil {
 		h.Logger.Error(
@@ -102,49 +170,23 @@ func (h *ThirdPartyController) post(userID string, c *fiber.Ctx) error {
 			zap.Error(err),
 			zap.String("user_id", userID),
 			zap.String("device_id", req.DeviceID),
+			zap.String("device_group", body.DeviceGroup),
 		)
 
 		return fmt.Errorf("failed to select device: %w", err)
 	}
 
-	var textContent *messages.TextMessageContent
-	var dataContent *messages.DataMessageContent
-	if text := req.GetTextMessage(); text != nil {
-		textContent = &messages.TextMessageContent{
-			Text: text.Text,
-		}
-	} else if data := req.GetDataMessage(); data != nil {
-		dataContent = &messages.DataMessageContent{
-			Data: data.Data,
-			Port: data.Port,
-		}
-	} else {
-		return fiber.NewError(fiber.StatusBadRequest, "No message content provided")
-	}
-
-	msg := messages.MessageInput{
-		MessageContent: messages.MessageContent{
-			TextContent: textContent,
-			DataContent: dataContent,
-		},
-
-		ID: req.ID,
-
-		PhoneNumbers: req.PhoneNumbers,
-		IsEncrypted:  req.IsEncrypted,
-
-		SimNumber:          req.SimNumber,
-		WithDeliveryReport: req.WithDeliveryReport,
-		TTL:                req.TTL,
-		ValidUntil:         req.ValidUntil,
-		ScheduleAt:         req.ScheduleAt,
-		Priority:           req.Priority,
-	}
 	state, err := h.messagesSvc.Enqueue(
 		c.Context(),
 		*device,
 		msg,
-		messages.EnqueueOptions{SkipPhoneValidation: lo.FromPtrOr(params.SkipPhoneValidation, false)},
+		messages.EnqueueOptions{
+			SkipPhoneValidation: lo.FromPtrOr(params.SkipPhoneValidation, false),
+			PhoneRegion:         lo.FromPtr(phoneParams.PhoneRegion),
+			Normalize:           normalize,
+			TrackLinks:          lo.FromPtr(linksParams.TrackLinks),
+			Drip:                drip,
+		},
 	)
 	if err != nil {
 		h.Logger.Error(
@@ -172,7 +214,137 @@ func (h *ThirdPartyController) post(userID string, c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusAccepted).
-		JSON(smsgateway.GetMessageResponse(converters.MessageStateToDTO(*state)))
+		JSON(newGetMessageResponse(*state))
+}
+
+//	@Summary		Enqueue messages batch
+//	@Description	Enqueues up to 100 messages at once. Either all messages are enqueued or none. Drip options are applied across the whole batch.
+//	@Description	A message with a personalized text is enqueued once per recipient; the batch may expand to at most 100 messages.
+//	@Description	Duplicates are suppressed per message, including repeats within the batch: a merged duplicate reports the state of its original, a rejected one fails the whole batch.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Accept			json
+//	@Produce		json
+//	@Param			skipPhoneValidation	query		bool							false	"Skip phone validation"
+//	@Param			phoneRegion			query		string							false	"Region for phone numbers without a country code, overrides the user and server defaults"	minLength(2)	maxLength(2)
+//	@Param			normalize			query		string							false	"Comma-separated text normalization rules applied before enqueueing: punctuation, cyrillic, greek, latin, strip; `none` disables the user default"
+//	@Param			trackLinks			query		bool							false	"Replace links in the text with short links counting clicks, the message must have a single recipient"
+//	@Param			deviceActiveWithin	query		int								false	"Filter devices active within the specified number of hours"	default(0)	minimum(0)
+//	@Param			dripWindow			query		int								false	"Spread the messages evenly over the specified number of seconds"	minimum(0)	maximum(604800)
+//	@Param			dripRate			query		int								false	"Maximum number of messages per hour for the device or SIM"	minimum(1)	maximum(3600)
+//	@Param			dripPerSim			query		bool							false	"Apply the drip rate to each SIM separately"
+//	@Param			dripTimezone		query		string							false	"IANA timezone of the device work hours"	default(UTC)
+//	@Param			request				body		BatchMessagesRequest			true	"Send messages request"
+//	@Success		202					{object}	[]GetMessageResponse			"Messages enqueued"
+//	@Failure		400					{object}	smsgateway.ErrorResponse		"Invalid request"
+//	@Failure		401					{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse		"Forbidden"
+//	@Failure		409					{object}	smsgateway.ErrorResponse		"Message with the same ID already exists, a message is a duplicate, see `data.originalId`, or the device is paused or pending approval"
+//	@Failure		500					{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Failure		503					{object}	smsgateway.ErrorResponse		"Queue limits exceeded; ensure device is online"
+//	@Router			/3rdparty/v1/messages/batch [post]
+//
+// Enqueue messages batch.
+func (h *ThirdPartyController) postBatch(userID string, c *fiber.Ctx) error {
+	var params thirdPartyPostQueryParams
+	if err := h.QueryParserValidator(c, &params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var dripParams dripQueryParams
+	if err := h.QueryParserValidator(c, &dripParams); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	drip, err := dripParams.ToOptions()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var phoneParams phoneQueryParams
+	if err := h.QueryParserValidator(c, &phoneParams); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var normalizeParams normalizeQueryParams
+	if err := h.QueryParserValidator(c, &normalizeParams); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var linksParams linksQueryParams
+	if err := h.QueryParserValidator(c, &linksParams); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	normalize, err := normalizeParams.ToOptions()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	req := new(BatchMessagesRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	activeWithin := time.Duration(lo.FromPtrOr(params.DeviceActiveWithin, 0)) * time.Hour
+	items := make([]messages.BatchItem, 0, len(req.Messages))
+	for i, reqItem := range req.Messages {
+		expanded, mmsContent, expErr := h.parseEnqueueRequest(c.Context(), userID, reqItem, lo.FromPtr(phoneParams.PhoneRegion))
+		var fiberErr *fiber.Error
+		if errors.As(expErr, &fiberErr) {
+			return fiber.NewError(fiberErr.Code, fmt.Sprintf("message %d: %s", i+1, fiberErr.Message))
+		}
+		if expErr != nil {
+			return fmt.Errorf("message %d: %w", i+1, expErr)
+		}
+		if len(items)+len(expanded) > maxExpandedMessages {
+			return fiber.NewError(
+				fiber.StatusBadRequest,
+				fmt.Sprintf("batch expands to more than %d messages", maxExpandedMessages),
+			)
+		}
+
+		for _, item := range expanded {
+			msg, ok := newMessageInput(item, mmsContent)
+			if !ok {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("message %d: no message content provided", i+1))
+			}
+
+			device, devErr := h.selectDevice(
+				c.Context(),
+				userID,
+				item.DeviceID,
+				activeWithin,
+				reqItem.DeviceRouting,
+				&msg,
+			)
+			if devErr != nil {
+				return fmt.Errorf("failed to select device for message %d: %w", i+1, devErr)
+			}
+
+			items = append(items, messages.BatchItem{Device: *device, Message: msg})
+		}
+	}
+
+	states, err := h.messagesSvc.EnqueueBatch(
+		c.Context(),
+		userID,
+		items,
+		messages.EnqueueOptions{
+			SkipPhoneValidation: lo.FromPtrOr(params.SkipPhoneValidation, false),
+			PhoneRegion:         lo.FromPtr(phoneParams.PhoneRegion),
+			Normalize:           normalize,
+			TrackLinks:          lo.FromPtr(linksParams.TrackLinks),
+			Drip:                drip,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue messages: %w", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).
+		JSON(lo.Map(states, func(state messages.MessageState, _ int) GetMessageResponse {
+			return newGetMessageResponse(state)
+		}))
 }
 
 //	@Summary		Get messages
@@ -227,7 +399,7 @@ func (h *ThirdPartyController) list(userID string, c *fiber.Ctx) error {
 //	@Tags			User, Messages
 //	@Produce		json
 //	@Param			id	path		string							true	"Message ID"
-//	@Success		200	{object}	smsgateway.GetMessageResponse	"Message state"
+//	@Success		200	{object}	GetMessageResponse				"Message state"
 //	@Failure		400	{object}	smsgateway.ErrorResponse		"Invalid request"
 //	@Failure		401	{object}	smsgateway.ErrorResponse		"Unauthorized"
 //	@Failure		403	{object}	smsgateway.ErrorResponse		"Forbidden"
@@ -248,7 +420,56 @@ func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to get message state")
 	}
 
-	return c.JSON(converters.MessageStateToDTO(*state))
+	return c.JSON(newGetMessageResponse(*state))
+}
+
+//	@Summary		Update message
+//	@Description	Updates a pending message by ID. The message must be in Pending state; edits are rejected once the device has reported progress.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		string						true	"Message ID"
+//	@Param			skipPhoneValidation	query		bool						false	"Skip phone validation"
+//	@Param			phoneRegion			query		string						false	"Region for phone numbers without a country code, overrides the user and server defaults"	minLength(2)	maxLength(2)
+//	@Param			request				body		UpdateMessageRequest		true	"Message changes"
+//	@Success		200					{object}	GetMessageResponse			"Message state after update"
+//	@Failure		400					{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401					{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404					{object}	smsgateway.ErrorResponse	"Message not found"
+//	@Failure		409					{object}	smsgateway.ErrorResponse	"Message is not pending"
+//	@Failure		500					{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/messages/{id} [patch]
+//
+// Update message.
+func (h *ThirdPartyController) patch(userID string, c *fiber.Ctx) error {
+	var params thirdPartyPatchQueryParams
+	if err := h.QueryParserValidator(c, &params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	req := new(UpdateMessageRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	state, err := h.messagesSvc.UpdateMessage(
+		c.Context(),
+		userID,
+		c.Params("id"),
+		req.ToDomain(),
+		messages.EnqueueOptions{
+			SkipPhoneValidation: lo.FromPtrOr(params.SkipPhoneValidation, false),
+			PhoneRegion:         lo.FromPtr(params.PhoneRegion),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+
+	return c.JSON(newGetMessageResponse(*state))
 }
 
 //	@Summary		Cancel message
@@ -257,7 +478,7 @@ func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
 //	@Security		JWTAuth
 //	@Tags			User, Messages
 //	@Param			id	path		string							true	"Message ID"
-//	@Success		200	{object}	smsgateway.GetMessageResponse	"Message state after cancellation"
+//	@Success		200	{object}	GetMessageResponse				"Message state after cancellation"
 //	@Failure		400	{object}	smsgateway.ErrorResponse		"Invalid request"
 //	@Failure		401	{object}	smsgateway.ErrorResponse		"Unauthorized"
 //	@Failure		403	{object}	smsgateway.ErrorResponse		"Forbidden"
@@ -274,7 +495,230 @@ func (h *ThirdPartyController) delete(userID string, c *fiber.Ctx) error {
 		return fmt.Errorf("failed to cancel message: %w", err)
 	}
 
-	return c.JSON(converters.MessageStateToDTO(*state))
+	return c.JSON(newGetMessageResponse(*state))
+}
+
+//	@Summary		Get message clicks
+//	@Description	Returns the click statistics of the tracked links of the message by recipient.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Produce		json
+//	@Param			id	path		string							true	"Message ID"
+//	@Success		200	{object}	[]LinkClicksResponse			"Click statistics by link"
+//	@Failure		400	{object}	smsgateway.ErrorResponse		"Invalid request"
+//	@Failure		401	{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse		"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse		"Message not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Router			/3rdparty/v1/messages/{id}/clicks [get]
+//
+// Get message clicks.
+func (h *ThirdPartyController) getMessageClicks(userID string, c *fiber.Ctx) error {
+	clicks, err := h.messagesSvc.GetMessageClicks(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to get message clicks: %w", err)
+	}
+
+	return c.JSON(lo.Map(clicks, func(item messages.LinkClicks, _ int) LinkClicksResponse {
+		return newLinkClicksResponse(item)
+	}))
+}
+
+//	@Summary		Get clicks over time
+//	@Description	Returns the number of clicks on the tracked links of all user's messages in the time range grouped by hour or day (UTC). Periods without clicks are omitted.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Produce		json
+//	@Param			from	query		string							true	"Start of the time range (RFC3339)"
+//	@Param			to		query		string							true	"End of the time range (RFC3339), exclusive"
+//	@Param			period	query		string							false	"Bucket size"	Enums(hour,day)	default(day)
+//	@Success		200		{object}	[]ClicksBucketResponse			"Clicks by period"
+//	@Failure		400		{object}	smsgateway.ErrorResponse		"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse		"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Router			/3rdparty/v1/messages/clicks [get]
+//
+// Get clicks over time.
+func (h *ThirdPartyController) getClicks(userID string, c *fiber.Ctx) error {
+	var params clicksQueryParams
+	if err := h.QueryParserValidator(c, &params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	period := messages.ClicksPeriodDay
+	if params.Period != "" {
+		period = messages.ClicksPeriod(params.Period)
+	}
+
+	buckets, err := h.messagesSvc.GetClicks(c.Context(), userID, params.From, params.To, period)
+	if err != nil {
+		return fmt.Errorf("failed to get clicks: %w", err)
+	}
+
+	return c.JSON(lo.Map(buckets, func(item messages.ClicksBucket, _ int) ClicksBucketResponse {
+		return ClicksBucketResponse{Start: item.Start, Clicks: item.Clicks, Links: item.Links}
+	}))
+}
+
+//	@Summary		Approve quarantined message
+//	@Description	Approves a message quarantined by a content policy. The message becomes pending and is sent to the device. Messages quarantined by the server policy can only be reviewed by the server reviewers.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Produce		json
+//	@Param			id	path		string							true	"Message ID"
+//	@Success		200	{object}	GetMessageResponse				"Message state after approval"
+//	@Failure		400	{object}	smsgateway.ErrorResponse		"Invalid request"
+//	@Failure		401	{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse		"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse		"Message not found"
+//	@Failure		409	{object}	smsgateway.ErrorResponse		"Message is not quarantined"
+//	@Failure		500	{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Router			/3rdparty/v1/messages/{id}/approve [post]
+//
+// Approve quarantined message.
+func (h *ThirdPartyController) postApprove(userID string, c *fiber.Ctx) error {
+	state, err := h.messagesSvc.ReviewMessage(c.Context(), userID, c.Params("id"), true)
+	if err != nil {
+		return fmt.Errorf("failed to approve message: %w", err)
+	}
+
+	return c.JSON(newGetMessageResponse(*state))
+}
+
+//	@Summary		Decline quarantined message
+//	@Description	Declines a message quarantined by a content policy. The message fails without being sent. Messages quarantined by the server policy can only be reviewed by the server reviewers.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Produce		json
+//	@Param			id	path		string							true	"Message ID"
+//	@Success		200	{object}	GetMessageResponse				"Message state after declining"
+//	@Failure		400	{object}	smsgateway.ErrorResponse		"Invalid request"
+//	@Failure		401	{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse		"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse		"Message not found"
+//	@Failure		409	{object}	smsgateway.ErrorResponse		"Message is not quarantined"
+//	@Failure		500	{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Router			/3rdparty/v1/messages/{id}/decline [post]
+//
+// Decline quarantined message.
+func (h *ThirdPartyController) postDecline(userID string, c *fiber.Ctx) error {
+	state, err := h.messagesSvc.ReviewMessage(c.Context(), userID, c.Params("id"), false)
+	if err != nil {
+		return fmt.Errorf("failed to decline message: %w", err)
+	}
+
+	return c.JSON(newGetMessageResponse(*state))
+}
+
+//	@Summary		Cancel messages
+//	@Description	Starts a background job cancelling all pending messages matching the filter. At least one of `deviceId`, `ids`, `from` or `to` is required. Devices receive a `MessagesCancelled` event with the comma-separated `messageIds` per batch of messages.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CancelMessagesRequest		true	"Messages filter"
+//	@Success		202		{object}	BulkJobResponse				"Job started"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Failure		503		{object}	smsgateway.ErrorResponse	"Too many bulk jobs in progress"
+//	@Header			202		{string}	Location					"Get job progress URL"
+//	@Router			/3rdparty/v1/messages/cancel [post]
+//
+// Cancel messages.
+func (h *ThirdPartyController) postCancel(userID string, c *fiber.Ctx) error {
+	req := new(CancelMessagesRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	job, err := h.messagesSvc.CancelMessages(c.Context(), userID, req.ToFilter())
+	if err != nil {
+		return fmt.Errorf("failed to start cancellation: %w", err)
+	}
+
+	return h.jobAccepted(c, job)
+}
+
+//	@Summary		Reschedule messages
+//	@Description	Starts a background job changing the scheduled send time of all pending messages matching the filter. At least one of `deviceId`, `ids`, `from` or `to` is required.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RescheduleMessagesRequest	true	"Messages filter and new schedule"
+//	@Success		202		{object}	BulkJobResponse				"Job started"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Failure		503		{object}	smsgateway.ErrorResponse	"Too many bulk jobs in progress"
+//	@Header			202		{string}	Location					"Get job progress URL"
+//	@Router			/3rdparty/v1/messages/reschedule [post]
+//
+// Reschedule messages.
+func (h *ThirdPartyController) postReschedule(userID string, c *fiber.Ctx) error {
+	req := new(RescheduleMessagesRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	job, err := h.messagesSvc.RescheduleMessages(c.Context(), userID, req.ToFilter(), req.ScheduleAt)
+	if err != nil {
+		return fmt.Errorf("failed to start rescheduling: %w", err)
+	}
+
+	return h.jobAccepted(c, job)
+}
+
+//	@Summary		Get bulk job progress
+//	@Description	Returns the progress of a bulk cancel or reschedule job. Jobs are kept for 24 hours.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Messages
+//	@Produce		json
+//	@Param			id	path		string						true	"Job ID"
+//	@Success		200	{object}	BulkJobResponse				"Job progress"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Job not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/messages/jobs/{id} [get]
+//
+// Get bulk job progress.
+func (h *ThirdPartyController) getJob(userID string, c *fiber.Ctx) error {
+	job, err := h.messagesSvc.GetBulkJob(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return c.JSON(newBulkJobResponse(*job))
+}
+
+func (h *ThirdPartyController) jobAccepted(c *fiber.Ctx, job *messages.BulkJob) error {
+	location, err := c.GetRouteURL(route3rdPartyGetJob, fiber.Map{
+		"id": job.ID,
+	})
+	if err != nil {
+		h.Logger.Warn(
+			"failed to get route URL",
+			zap.String("route", route3rdPartyGetJob),
+			zap.String("id", job.ID),
+			zap.Error(err),
+		)
+	} else {
+		c.Location(location)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(newBulkJobResponse(*job))
 }
 
 // Export inbox.
@@ -313,9 +757,33 @@ func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
 	}
 
 	var msgValidationError messages.ValidationError
+	var contactsValidationError contacts.ValidationError
+	var attachmentsValidationError attachments.ValidationError
+	var duplicateError *messages.DuplicateMessageError
+	var contentError *messages.ContentRejectedError
 	switch {
 	case errors.As(err, &msgValidationError):
 		return fiber.NewError(fiber.StatusBadRequest, msgValidationError.Error())
+	case errors.As(err, &contactsValidationError):
+		return fiber.NewError(fiber.StatusBadRequest, contactsValidationError.Error())
+	case errors.As(err, &attachmentsValidationError):
+		return fiber.NewError(fiber.StatusBadRequest, attachmentsValidationError.Error())
+	case errors.As(err, &duplicateError):
+		return c.Status(fiber.StatusConflict).JSON(smsgateway.ErrorResponse{
+			Message: duplicateError.Error(),
+			Code:    0,
+			Data:    DuplicateMessageDetails{OriginalID: duplicateError.OriginalID},
+		})
+	case errors.As(err, &contentError):
+		return c.Status(fiber.StatusBadRequest).JSON(smsgateway.ErrorResponse{
+			Message: contentError.Error(),
+			Code:    0,
+			Data:    ContentRejectionDetails{Reasons: contentError.Reasons},
+		})
+	case errors.Is(err, messages.ErrMessageNotQuarantined):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, messages.ErrReviewForbidden):
+		return fiber.NewError(fiber.StatusForbidden, messages.ErrReviewForbidden.Error())
 	case errors.Is(err, messages.ErrMultipleMessagesFound):
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	case errors.Is(err, messages.ErrNoContent):
@@ -328,7 +796,17 @@ func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
 	case errors.Is(err, messages.ErrMessageNotPending):
 		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, messages.ErrBulkJobNotFound):
+		return fiber.NewError(fiber.StatusNotFound, messages.ErrBulkJobNotFound.Error())
+	case errors.Is(err, messages.ErrBulkQueueFull):
+		return fiber.NewError(fiber.StatusServiceUnavailable, messages.ErrBulkQueueFull.Error())
 
+	case errors.Is(err, devices.ErrDevicePaused):
+		fallthrough
+	case errors.Is(err, devices.ErrPending):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, devices.ErrSimNotFound):
+		fallthrough
 	case errors.Is(err, devices.ErrNotFound):
 		fallthrough
 	case errors.Is(err, devices.ErrInvalidFilter):
@@ -348,8 +826,17 @@ func (h *ThirdPartyController) Register(router fiber.Router) {
 
 	router.Get("", permissions.RequireScope(ScopeList), userauth.WithUserID(h.list))
 	router.Post("", permissions.RequireScope(ScopeSend), userauth.WithUserID(h.post))
+	router.Post("batch", permissions.RequireScope(ScopeSend), userauth.WithUserID(h.postBatch))
+	router.Post("cancel", permissions.RequireScope(ScopeCancel), userauth.WithUserID(h.postCancel))
+	router.Post("reschedule", permissions.RequireScope(ScopeSend), userauth.WithUserID(h.postReschedule))
+	router.Get("jobs/:id", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.getJob)).Name(route3rdPartyGetJob)
+	router.Get("clicks", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.getClicks))
 	router.Get(":id", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.get)).Name(route3rdPartyGetMessage)
+	router.Get(":id/clicks", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.getMessageClicks))
+	router.Patch(":id", permissions.RequireScope(ScopeSend), userauth.WithUserID(h.patch))
 	router.Delete(":id", permissions.RequireScope(ScopeCancel), userauth.WithUserID(h.delete))
+	router.Post(":id/approve", permissions.RequireScope(ScopeReview), userauth.WithUserID(h.postApprove))
+	router.Post(":id/decline", permissions.RequireScope(ScopeReview), userauth.WithUserID(h.postDecline))
 
 	router.Post("inbox/export", permissions.RequireScope(ScopeExport), userauth.WithUserID(h.postInboxExport))
 }
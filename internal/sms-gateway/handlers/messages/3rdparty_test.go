@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	messages "github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/gofiber/fiber/v2"
@@ -76,6 +78,12 @@ func TestThirdPartyControllerErrorHandler(t *testing.T) {
 			wantStatus: fiber.StatusBadRequest,
 			wantBody:   `{"message":""}`,
 		},
+		{
+			name:       "wrapped contacts validation error emits its own text",
+			handlerErr: fmt.Errorf("failed to resolve contacts: %w", contacts.ValidationError(`contact "x" not found`)),
+			wantStatus: fiber.StatusBadRequest,
+			wantBody:   `{"message":"contact \"x\" not found"}`,
+		},
 		{
 			name:       "not found keeps 404",
 			handlerErr: fmt.Errorf(enqueueWrap, messages.ErrMessageNotFound),
@@ -94,6 +102,18 @@ func TestThirdPartyControllerErrorHandler(t *testing.T) {
 			wantStatus: fiber.StatusConflict,
 			wantBody:   `{"message":"failed to enqueue message: message is not pending"}`,
 		},
+		{
+			name:       "bulk job not found emits sentinel text",
+			handlerErr: fmt.Errorf("failed to get job: %w", messages.ErrBulkJobNotFound),
+			wantStatus: fiber.StatusNotFound,
+			wantBody:   `{"message":"bulk job not found"}`,
+		},
+		{
+			name:       "bulk queue full emits sentinel text",
+			handlerErr: fmt.Errorf("failed to start cancellation: %w", messages.ErrBulkQueueFull),
+			wantStatus: fiber.StatusServiceUnavailable,
+			wantBody:   `{"message":"too many bulk jobs in progress"}`,
+		},
 		{
 			name:       "device not found keeps 400",
 			handlerErr: fmt.Errorf("failed to select device: %w", devices.ErrNotFound),
@@ -147,3 +167,34 @@ func TestThirdPartyControllerErrorHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestMessagesFilterValidate(t *testing.T) {
+	deviceID := "000000000000000000001"
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	cases := []struct {
+		name    string
+		filter  MessagesFilter
+		wantErr bool
+	}{
+		{name: "empty", filter: MessagesFilter{}, wantErr: true},
+		{name: "device", filter: MessagesFilter{DeviceID: &deviceID}, wantErr: false},
+		{name: "ids", filter: MessagesFilter{IDs: []string{"id"}}, wantErr: false},
+		{name: "from", filter: MessagesFilter{From: &from}, wantErr: false},
+		{name: "to", filter: MessagesFilter{To: &to}, wantErr: false},
+		{name: "reversed range", filter: MessagesFilter{From: &to, To: &from}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.filter.Validate()
+			if tc.wantErr {
+				var validationErr messages.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
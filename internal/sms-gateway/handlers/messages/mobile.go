@@ -6,7 +6,6 @@ import (
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
-	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/deviceauth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
@@ -43,13 +42,13 @@ func NewMobileController(params mobileControllerParams) *MobileController {
 }
 
 //	@Summary		Get messages for sending
-//	@Description	Returns list of pending messages
+//	@Description	Returns list of pending messages. The attachments of MMS messages are downloaded from `/mobile/v1/attachments/{id}`; MMS messages are only returned to devices reporting the `mms` feature.
 //	@Security		MobileToken
 //	@Tags			Device, Messages
 //	@Accept			json
 //	@Produce		json
 //	@Param			order	query		string									false	"Message processing order: lifo (default) or fifo"	Enums(lifo,fifo)	default(lifo)
-//	@Success		200		{object}	smsgateway.MobileGetMessagesResponse	"List of pending messages"
+//	@Success		200		{object}	[]MobileMessage							"List of pending messages"
 //	@Failure		400		{object}	smsgateway.ErrorResponse				"Invalid request"
 //	@Failure		500		{object}	smsgateway.ErrorResponse				"Internal server error"
 //	@Router			/mobile/v1/message [get]
@@ -62,19 +61,12 @@ func (h *MobileController) list(device devices.Device, c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	msgs, err := h.messagesSvc.SelectPending(device.ID, params.OrderOrDefault())
+	msgs, err := h.messagesSvc.SelectPending(device, params.OrderOrDefault())
 	if err != nil {
 		return fmt.Errorf("failed to get messages: %w", err)
 	}
 
-	return c.JSON(
-		smsgateway.MobileGetMessagesResponse(
-			slices.Map(
-				msgs,
-				converters.MessageToMobileDTO,
-			),
-		),
-	)
+	return c.JSON(slices.Map(msgs, newMobileMessage))
 }
 
 //	@Summary		Update message state
@@ -84,7 +76,7 @@ func (h *MobileController) list(device devices.Device, c *fiber.Ctx) error {
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		smsgateway.MobilePatchMessageRequest	true	"List of message state updates"
-//	@Success		204		{object}	nil										"Successfully updated"
+//	@Success		200		{object}	MobilePatchMessageResponse				"Per-message update results"
 //	@Failure		400		{object}	smsgateway.ErrorResponse				"Invalid request"
 //	@Failure		500		{object}	smsgateway.ErrorResponse				"Internal server error"
 //	@Router			/mobile/v1/message [patch]
@@ -96,6 +88,7 @@ func (h *MobileController) patch(device devices.Device, c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
+	res := make(MobilePatchMessageResponse, 0, len(req))
 	for _, v := range req {
 		messageState := messages.MessageStateInput{
 			ID:         v.ID,
@@ -104,16 +97,42 @@ func (h *MobileController) patch(device devices.Device, c *fiber.Ctx) error {
 			States:     v.States,
 		}
 
-		err := h.messagesSvc.UpdateState(&device, messageState)
-		if err != nil && !errors.Is(err, messages.ErrMessageNotFound) {
-			h.Logger.Error("failed to update message status",
-				zap.String("message_id", v.ID),
-				zap.Error(err),
-			)
-		}
+		update, err := h.messagesSvc.UpdateState(&device, messageState)
+		res = append(res, h.patchResult(v.ID, update, err))
+	}
+
+	return c.JSON(res)
+}
+
+func (h *MobileController) patchResult(
+	id string,
+	update *messages.StateUpdate,
+	err error,
+) MobilePatchMessageResult {
+	result := MobilePatchMessageResult{
+		ID:     id,
+		Result: MobilePatchMessageResultFailed,
+		State:  "",
+		Error:  "",
+	}
+
+	switch {
+	case errors.Is(err, messages.ErrMessageNotFound):
+		result.Result = MobilePatchMessageResultNotFound
+	case errors.Is(err, messages.ErrStateConflict):
+		result.Error = err.Error()
+	case err != nil:
+		h.Logger.Error("failed to update message status",
+			zap.String("message_id", id),
+			zap.Error(err),
+		)
+		result.Error = "internal error"
+	default:
+		result.Result = MobilePatchMessageResultCode(update.Result)
+		result.State = smsgateway.ProcessingState(update.State)
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
+	return result
 }
 
 func (h *MobileController) Register(router fiber.Router) {
@@ -1,8 +1,13 @@
 package messages
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/samber/lo"
 )
 
 // thirdPartyPostQueryParams aliases smsgateway.SendOptions so that the query
@@ -13,6 +18,78 @@ import (
 type thirdPartyPostQueryParams smsgateway.SendOptions
 type thirdPartyGetQueryParams smsgateway.ListMessagesOptions
 
+type thirdPartyPatchQueryParams struct {
+	SkipPhoneValidation *bool   `query:"skipPhoneValidation"`
+	PhoneRegion         *string `query:"phoneRegion"         validate:"omitempty,len=2,alpha"`
+}
+
+// phoneQueryParams overrides the phone number parsing for a single request.
+type phoneQueryParams struct {
+	PhoneRegion *string `query:"phoneRegion" validate:"omitempty,len=2,alpha"`
+}
+
+// dripQueryParams configures server-side pacing of enqueued messages.
+type dripQueryParams struct {
+	DripWindow   *uint32 `query:"dripWindow"   validate:"omitempty,max=604800"`
+	DripRate     *uint32 `query:"dripRate"     validate:"omitempty,min=1,max=3600"`
+	DripPerSIM   *bool   `query:"dripPerSim"`
+	DripTimezone *string `query:"dripTimezone" validate:"omitempty,timezone"`
+}
+
+// linksQueryParams enables link tracking for a single request.
+type linksQueryParams struct {
+	TrackLinks *bool `query:"trackLinks"`
+}
+
+// clicksQueryParams selects the time range of click statistics.
+type clicksQueryParams struct {
+	From   time.Time `query:"from"   validate:"required"`
+	To     time.Time `query:"to"     validate:"required"`
+	Period string    `query:"period" validate:"omitempty,oneof=hour day"`
+}
+
+// normalizeQueryParams selects the text normalization rules for a single request.
+type normalizeQueryParams struct {
+	Normalize *string `query:"normalize" validate:"omitempty,max=128"`
+}
+
+// ToOptions returns nil when the parameter is absent so that the user default applies.
+func (p *normalizeQueryParams) ToOptions() (*messages.NormalizeOptions, error) {
+	if p.Normalize == nil {
+		return nil, nil //nolint:nilnil // absence of options is not an error
+	}
+
+	opts, err := messages.ParseNormalizeOptions(strings.Split(*p.Normalize, ","))
+	if err != nil {
+		return nil, fmt.Errorf("invalid normalize: %w", err)
+	}
+
+	return &opts, nil
+}
+
+func (p *dripQueryParams) ToOptions() (messages.DripOptions, error) {
+	opts := messages.DripOptions{
+		Window:   time.Duration(lo.FromPtr(p.DripWindow)) * time.Second,
+		Interval: 0,
+		PerSIM:   lo.FromPtr(p.DripPerSIM),
+		Location: time.UTC,
+	}
+
+	if p.DripRate != nil {
+		opts.Interval = time.Hour / time.Duration(*p.DripRate)
+	}
+
+	if p.DripTimezone != nil {
+		loc, err := time.LoadLocation(*p.DripTimezone)
+		if err != nil {
+			return opts, fmt.Errorf("invalid dripTimezone: %w", err)
+		}
+		opts.Location = loc
+	}
+
+	return opts, nil
+}
+
 func (p *thirdPartyGetQueryParams) ToFilter() messages.SelectFilter {
 	var filter messages.SelectFilter
 
@@ -14,4 +14,6 @@ const (
 	ScopeCancel = smsgateway.ScopeMessagesCancel
 	// ScopeExport is the permission scope required for exporting messages.
 	ScopeExport = smsgateway.ScopeMessagesExport
+	// ScopeReview is the permission scope required for reviewing quarantined messages.
+	ScopeReview = "messages:review"
 )
@@ -0,0 +1,157 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+// maxExpandedMessages limits the number of messages enqueued by a single request.
+const maxExpandedMessages = 100
+
+// parseEnqueueRequest resolves the contacts of the request and returns the validated messages to enqueue
+// together with the MMS content shared by all of them, if any.
+func (h *ThirdPartyController) parseEnqueueRequest(
+	ctx context.Context,
+	userID string,
+	req EnqueueMessageRequest,
+	phoneRegion string,
+) ([]smsgateway.Message, *messages.MmsMessageContent, error) {
+	if err := h.ValidateStruct(&req.ContactRecipients); err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := h.ValidateStruct(&req.DeviceRouting); err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if !req.Sim().IsEmpty() && req.SimNumber != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "fromNumber and iccid can't be combined with simNumber")
+	}
+
+	mmsContent, err := h.parseMmsMessage(ctx, userID, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recipients []contacts.Contact
+	phoneKeys := req.PhoneNumbers
+	if len(req.ContactIDs) > 0 || len(req.GroupIDs) > 0 {
+		if recipients, err = h.contactsSvc.Resolve(ctx, userID, req.ContactIDs, req.GroupIDs); err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve contacts: %w", err)
+		}
+
+		// the numbers of the contacts are stored normalized
+		if phoneKeys, err = h.messagesSvc.NormalizePhoneNumbers(userID, phoneRegion, req.PhoneNumbers); err != nil {
+			var validationErr messages.ValidationError
+			if errors.As(err, &validationErr) {
+				return nil, nil, fiber.NewError(fiber.StatusBadRequest, validationErr.Error())
+			}
+			return nil, nil, fmt.Errorf("failed to normalize phone numbers: %w", err)
+		}
+	}
+
+	items, err := expandMessage(req.Message, phoneKeys, recipients)
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	for i := range items {
+		if validErr := h.ValidateStruct(&items[i]); validErr != nil {
+			return nil, nil, fiber.NewError(fiber.StatusBadRequest, validErr.Error())
+		}
+	}
+
+	return items, mmsContent, nil
+}
+
+// parseMmsMessage validates the MMS content of the request and resolves its attachments.
+func (h *ThirdPartyController) parseMmsMessage(
+	ctx context.Context,
+	userID string,
+	req EnqueueMessageRequest,
+) (*messages.MmsMessageContent, error) {
+	if req.MmsMessage == nil {
+		return nil, nil //nolint:nilnil // not an MMS message
+	}
+
+	if req.Message.Message != "" || req.GetTextMessage() != nil || req.GetDataMessage() != nil {
+		return nil, fiber.NewError(
+			fiber.StatusBadRequest,
+			"mmsMessage can't be combined with message, textMessage or dataMessage",
+		)
+	}
+	if err := h.ValidateStruct(req.MmsMessage); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	items, err := h.attachmentsSvc.Resolve(ctx, userID, req.MmsMessage.AttachmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve attachments: %w", err)
+	}
+
+	return newMmsContent(req.MmsMessage.Text, items), nil
+}
+
+// expandMessage adds the contacts to the recipients of the message.
+//
+// A text with `{{field}}` placeholders is personalized for every recipient, so
+// a message per recipient is returned. Phone numbers of the request that don't
+// belong to a resolved contact only get the `{{phone_number}}` placeholder filled.
+//
+// phoneKeys are the normalized phone numbers of the message in the same order,
+// they are matched against the numbers of the contacts.
+func expandMessage(
+	msg smsgateway.Message,
+	phoneKeys []string,
+	recipients []contacts.Contact,
+) ([]smsgateway.Message, error) {
+	if len(recipients) == 0 {
+		return []smsgateway.Message{msg}, nil
+	}
+
+	all := make([]contacts.Contact, 0, len(msg.PhoneNumbers)+len(recipients))
+	index := make(map[string]int, cap(all))
+	for i, phone := range msg.PhoneNumbers {
+		index[phoneKeys[i]] = len(all)
+		//nolint:exhaustruct // only the phone number is known
+		all = append(all, contacts.Contact{PhoneNumber: phone})
+	}
+	for _, contact := range recipients {
+		if i, ok := index[contact.PhoneNumber]; ok {
+			all[i] = contact
+			continue
+		}
+		index[contact.PhoneNumber] = len(all)
+		all = append(all, contact)
+	}
+	phones := lo.Map(all, func(c contacts.Contact, _ int) string { return c.PhoneNumber })
+
+	text := msg.GetTextMessage()
+	if text == nil || !contacts.IsPersonalized(text.Text) {
+		msg.PhoneNumbers = phones
+		return []smsgateway.Message{msg}, nil
+	}
+
+	if len(all) > maxExpandedMessages {
+		return nil, fmt.Errorf("personalized message has %d recipients, max %d", len(all), maxExpandedMessages)
+	}
+	if msg.ID != "" && len(all) > 1 {
+		return nil, errors.New("message ID can't be set for a personalized message with several recipients")
+	}
+
+	items := make([]smsgateway.Message, 0, len(all))
+	for _, recipient := range all {
+		item := msg
+		item.Message = ""
+		item.TextMessage = &smsgateway.TextMessage{Text: contacts.Personalize(text.Text, recipient)}
+		item.PhoneNumbers = []string{recipient.PhoneNumber}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
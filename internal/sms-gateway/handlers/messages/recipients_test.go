@@ -0,0 +1,69 @@
+//nolint:testpackage // expandMessage is unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandMessage(t *testing.T) {
+	recipients := []contacts.Contact{
+		//nolint:exhaustruct // only personalization fields are relevant
+		{Name: "Ann", PhoneNumber: "+79000000001", Fields: map[string]string{"code": "1"}},
+		//nolint:exhaustruct // only personalization fields are relevant
+		{Name: "Bob", PhoneNumber: "+79000000002", Fields: map[string]string{"code": "2"}},
+	}
+
+	//nolint:exhaustruct // only recipients and content are relevant
+	plain := smsgateway.Message{
+		TextMessage:  &smsgateway.TextMessage{Text: "Hello"},
+		PhoneNumbers: []string{"+79000000002", "+79000000003"},
+	}
+	items, err := expandMessage(plain, plain.PhoneNumbers, recipients)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, []string{"+79000000002", "+79000000003", "+79000000001"}, items[0].PhoneNumbers)
+	require.Equal(t, []string{"+79000000002", "+79000000003"}, plain.PhoneNumbers)
+
+	personalized := plain
+	personalized.TextMessage = &smsgateway.TextMessage{Text: "Hi {{name}}, code {{code}}"}
+	items, err = expandMessage(personalized, personalized.PhoneNumbers, recipients)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	require.Equal(t, []string{"+79000000002"}, items[0].PhoneNumbers)
+	require.Equal(t, "Hi Bob, code 2", items[0].TextMessage.Text)
+	require.Equal(t, "Hi , code ", items[1].TextMessage.Text)
+	require.Equal(t, []string{"+79000000001"}, items[2].PhoneNumbers)
+	require.Equal(t, "Hi Ann, code 1", items[2].TextMessage.Text)
+
+	personalized.ID = "custom-id"
+	_, err = expandMessage(personalized, personalized.PhoneNumbers, recipients)
+	require.Error(t, err)
+
+	items, err = expandMessage(personalized, personalized.PhoneNumbers, nil)
+	require.NoError(t, err)
+	require.Equal(t, []smsgateway.Message{personalized}, items)
+}
+
+func TestExpandMessageNormalizedPhones(t *testing.T) {
+	recipients := []contacts.Contact{
+		//nolint:exhaustruct // only personalization fields are relevant
+		{Name: "Bob", PhoneNumber: "+79000000002"},
+	}
+
+	//nolint:exhaustruct // only recipients and content are relevant
+	msg := smsgateway.Message{
+		TextMessage:  &smsgateway.TextMessage{Text: "Hi {{name}}"},
+		PhoneNumbers: []string{"8 (900) 000-00-02", "89000000003"},
+	}
+	items, err := expandMessage(msg, []string{"+79000000002", "+79000000003"}, recipients)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, []string{"+79000000002"}, items[0].PhoneNumbers)
+	require.Equal(t, "Hi Bob", items[0].TextMessage.Text)
+	require.Equal(t, []string{"89000000003"}, items[1].PhoneNumbers)
+	require.Equal(t, "Hi ", items[1].TextMessage.Text)
+}
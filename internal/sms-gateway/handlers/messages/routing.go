@@ -0,0 +1,49 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/samber/lo"
+)
+
+// selectDevice picks the device to send the message from. If the routing names
+// a SIM card, the device holding the card is picked and the message is sent
+// from the card's slot.
+func (h *ThirdPartyController) selectDevice(
+	ctx context.Context,
+	userID string,
+	deviceID string,
+	activeWithin time.Duration,
+	routing DeviceRouting,
+	msg *messages.MessageInput,
+) (*devices.Device, error) {
+	filter := routing.DeviceFilter()
+	// an explicitly chosen device without MMS support is refused on enqueue
+	if msg.MmsContent != nil && deviceID == "" {
+		filter = append(filter, devices.WithFeature(devices.FeatureMms))
+	}
+
+	sim := routing.Sim()
+	if sim.IsEmpty() {
+		return h.devicesSvc.GetAny(ctx, userID, deviceID, activeWithin, filter...)
+	}
+
+	device, simCard, err := h.devicesSvc.GetAnyWithSim(
+		ctx,
+		userID,
+		deviceID,
+		activeWithin,
+		sim,
+		filter...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.SimNumber = lo.ToPtr(uint8(simCard.SimNumber)) //nolint:gosec // sim numbers are small
+
+	return device, nil
+}
@@ -0,0 +1,503 @@
+package messages
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/samber/lo"
+)
+
+// MobilePatchMessageResultCode describes how a single state update was handled.
+type MobilePatchMessageResultCode string
+
+const (
+	MobilePatchMessageResultApplied  MobilePatchMessageResultCode = "applied"
+	MobilePatchMessageResultIgnored  MobilePatchMessageResultCode = "ignored"
+	MobilePatchMessageResultRejected MobilePatchMessageResultCode = "rejected"
+	MobilePatchMessageResultNotFound MobilePatchMessageResultCode = "not_found"
+	MobilePatchMessageResultFailed   MobilePatchMessageResultCode = "failed"
+)
+
+// MobilePatchMessageResult is the result of a single message state update.
+type MobilePatchMessageResult struct {
+	// Message ID
+	ID string `json:"id"`
+	// Update result
+	Result MobilePatchMessageResultCode `json:"result" enums:"applied,ignored,rejected,not_found,failed"`
+	// Message state stored on the server after the update
+	State smsgateway.ProcessingState `json:"state,omitempty"`
+	// Error description
+	Error string `json:"error,omitempty"`
+}
+
+// MobilePatchMessageResponse lists results in the order of the request items.
+type MobilePatchMessageResponse []MobilePatchMessageResult
+
+// RecipientState extends smsgateway.RecipientState with the history of recipient states.
+type RecipientState struct {
+	smsgateway.RecipientState
+
+	// History of states
+	States map[string]time.Time `json:"states,omitempty"`
+}
+
+// DuplicateMessageDetails is returned in the error data when a message is rejected as a duplicate.
+type DuplicateMessageDetails struct {
+	// ID of the message sent earlier with the same content to the same recipient
+	OriginalID string `json:"originalId"`
+}
+
+// ContentRejectionDetails is returned in the error data when a message violates a content policy.
+type ContentRejectionDetails struct {
+	// Violated rules
+	Reasons []string `json:"reasons"`
+}
+
+// ContentVerdict describes the content policy violations of a message.
+type ContentVerdict struct {
+	// Applied action
+	Action string `json:"action" enums:"flag,quarantine"`
+	// Policy that determined the action
+	Source string `json:"source" enums:"server,user"`
+	// Violated rules
+	Reasons []string `json:"reasons"`
+}
+
+// TextChange is a replacement made by text normalization.
+type TextChange struct {
+	// Original character
+	From string `json:"from"`
+	// Replacement, empty if the character was removed
+	To string `json:"to"`
+	// Number of replacements
+	Count int `json:"count"`
+}
+
+// TextNormalization reports the changes made to the message text.
+type TextNormalization struct {
+	// Changes in the order of their first occurrence
+	Changes []TextChange `json:"changes"`
+	// Encoding required by the resulting text
+	Encoding string `json:"encoding" enums:"gsm7,ucs2"`
+}
+
+// MmsMessage is the content of an MMS message.
+type MmsMessage struct {
+	// Message text
+	Text string `json:"text,omitempty" validate:"max=65535"`
+	// IDs of the files uploaded with `POST /3rdparty/v1/attachments`
+	AttachmentIDs []string `json:"attachmentIds" validate:"required,min=1,max=10,dive,len=21"`
+}
+
+// MmsAttachment describes a file sent with an MMS message.
+type MmsAttachment struct {
+	// Attachment ID; devices download the content from `/mobile/v1/attachments/{id}`
+	ID string `json:"id"`
+	// File name
+	Name string `json:"name"`
+	// MIME type
+	ContentType string `json:"contentType"`
+	// Size in bytes
+	Size int64 `json:"size"`
+}
+
+// MmsMessageContent is the stored content of an MMS message.
+type MmsMessageContent struct {
+	// Message text
+	Text string `json:"text"`
+	// Attached files
+	Attachments []MmsAttachment `json:"attachments"`
+}
+
+func newMmsMessageContent(content *messages.MmsMessageContent) *MmsMessageContent {
+	if content == nil {
+		return nil
+	}
+
+	return &MmsMessageContent{
+		Text: content.Text,
+		Attachments: lo.Map(content.Attachments, func(item messages.MmsAttachment, _ int) MmsAttachment {
+			return MmsAttachment{
+				ID:          item.ID,
+				Name:        item.Name,
+				ContentType: item.ContentType,
+				Size:        item.Size,
+			}
+		}),
+	}
+}
+
+// newMmsContent describes the resolved attachments in the message content.
+func newMmsContent(text string, items []attachments.Attachment) *messages.MmsMessageContent {
+	return &messages.MmsMessageContent{
+		Text: text,
+		Attachments: lo.Map(items, func(item attachments.Attachment, _ int) messages.MmsAttachment {
+			return messages.MmsAttachment{
+				ID:          item.ID,
+				Name:        item.Name,
+				ContentType: item.ContentType,
+				Size:        item.Size,
+			}
+		}),
+	}
+}
+
+// MobileMessage extends smsgateway.MobileMessage with the MMS content.
+type MobileMessage struct {
+	smsgateway.MobileMessage
+
+	// MMS content
+	MmsMessage *MmsMessageContent `json:"mmsMessage,omitempty"`
+}
+
+func newMobileMessage(message messages.Message) MobileMessage {
+	return MobileMessage{
+		MobileMessage: converters.MessageToMobileDTO(message),
+		MmsMessage:    newMmsMessageContent(message.MmsContent),
+	}
+}
+
+// GetMessageResponse extends smsgateway.GetMessageResponse with per-recipient state history.
+type GetMessageResponse struct {
+	smsgateway.GetMessageResponse
+
+	// MMS content
+	MmsMessage *MmsMessageContent `json:"mmsMessage,omitempty"`
+	// Recipients states
+	Recipients []RecipientState `json:"recipients"`
+	// Text changes made by normalization, only returned on enqueue
+	Normalization *TextNormalization `json:"normalization,omitempty"`
+	// Content policy violations; quarantined messages wait for review
+	ContentVerdict *ContentVerdict `json:"contentVerdict,omitempty"`
+	// Edits made while the message was pending, oldest first
+	Edits []MessageEdit `json:"edits,omitempty"`
+}
+
+// MessageEdit lists the fields changed by an edit of a pending message.
+type MessageEdit struct {
+	// Names of the changed fields
+	Fields []string `json:"fields"`
+	// Time of the edit
+	EditedAt time.Time `json:"editedAt"`
+}
+
+func newGetMessageResponse(state messages.MessageState) GetMessageResponse {
+	return GetMessageResponse{
+		GetMessageResponse: smsgateway.GetMessageResponse(converters.MessageStateToDTO(state)),
+		MmsMessage:         newMmsMessageContent(state.MmsContent),
+		Recipients: lo.Map(
+			state.Recipients,
+			func(item smsgateway.RecipientState, _ int) RecipientState {
+				return RecipientState{
+					RecipientState: item,
+					States:         state.RecipientsStates[item.PhoneNumber],
+				}
+			},
+		),
+		Normalization:  newTextNormalization(state.Normalization),
+		ContentVerdict: newContentVerdict(state.ContentVerdict),
+		Edits: lo.Map(state.Edits, func(item messages.MessageEdit, _ int) MessageEdit {
+			return MessageEdit{Fields: item.Fields, EditedAt: item.EditedAt}
+		}),
+	}
+}
+
+func newContentVerdict(verdict *messages.ContentVerdict) *ContentVerdict {
+	if verdict == nil {
+		return nil
+	}
+
+	return &ContentVerdict{
+		Action:  string(verdict.Action),
+		Source:  string(verdict.Source),
+		Reasons: verdict.Reasons,
+	}
+}
+
+func newTextNormalization(report *messages.NormalizationReport) *TextNormalization {
+	if report == nil {
+		return nil
+	}
+
+	return &TextNormalization{
+		Changes: lo.Map(report.Changes, func(item messages.TextChange, _ int) TextChange {
+			return TextChange{From: item.From, To: item.To, Count: item.Count}
+		}),
+		Encoding: string(report.Encoding),
+	}
+}
+
+// LinkClicksResponse is the click statistics of a tracked link.
+type LinkClicksResponse struct {
+	// Short link code
+	Code string `json:"code"`
+	// Original URL
+	URL string `json:"url"`
+	// Number of clicks
+	Clicks int64 `json:"clicks"`
+	// Number of clicks by recipient phone number
+	Recipients map[string]int64 `json:"recipients"`
+	// Time of the first click
+	FirstClick *time.Time `json:"firstClick,omitempty"`
+	// Time of the last click
+	LastClick *time.Time `json:"lastClick,omitempty"`
+}
+
+func newLinkClicksResponse(clicks messages.LinkClicks) LinkClicksResponse {
+	return LinkClicksResponse{
+		Code:       clicks.Code,
+		URL:        clicks.URL,
+		Clicks:     clicks.Clicks,
+		Recipients: clicks.Recipients,
+		FirstClick: clicks.FirstClick,
+		LastClick:  clicks.LastClick,
+	}
+}
+
+// ClicksBucketResponse is the number of clicks within a period.
+type ClicksBucketResponse struct {
+	// Start of the period
+	Start time.Time `json:"start"`
+	// Number of clicks
+	Clicks int64 `json:"clicks"`
+	// Number of distinct links clicked
+	Links int64 `json:"links"`
+}
+
+// MessagesFilter selects messages for a bulk operation.
+// Only messages in the Pending state are affected. At least one of device ID,
+// message IDs or the creation date range is required.
+type MessagesFilter struct {
+	// Device ID
+	DeviceID *string `json:"deviceId,omitempty" validate:"omitempty,len=21"`
+	// Processing state; only Pending messages are affected
+	State *smsgateway.ProcessingState `json:"state,omitempty"`
+	// Start of the creation date range
+	From *time.Time `json:"from,omitempty"`
+	// End of the creation date range
+	To *time.Time `json:"to,omitempty"`
+	// Message IDs
+	IDs []string `json:"ids,omitempty" validate:"omitempty,max=1000,dive,required,max=36"`
+}
+
+func (f *MessagesFilter) Validate() error {
+	if f.DeviceID == nil && len(f.IDs) == 0 && f.From == nil && f.To == nil {
+		return messages.ValidationError("one of `deviceId`, `ids`, `from` or `to` is required")
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return messages.ValidationError("`from` must be before `to`")
+	}
+
+	return nil
+}
+
+func (f *MessagesFilter) ToFilter() messages.SelectFilter {
+	filter := new(messages.SelectFilter).WithExtIDs(f.IDs...)
+
+	if f.DeviceID != nil {
+		filter.WithDeviceID(*f.DeviceID)
+	}
+
+	if f.State != nil {
+		filter.WithState(messages.ProcessingState(*f.State))
+	}
+
+	if f.From != nil {
+		filter.StartDate = *f.From
+	}
+
+	if f.To != nil {
+		filter.EndDate = *f.To
+	}
+
+	return *filter
+}
+
+// CancelMessagesRequest is a request to cancel pending messages matching the filter.
+type CancelMessagesRequest struct {
+	MessagesFilter
+}
+
+// RescheduleMessagesRequest is a request to change the schedule of pending messages matching the filter.
+type RescheduleMessagesRequest struct {
+	MessagesFilter
+
+	// New scheduled send time; messages without it are sent as soon as possible
+	ScheduleAt *time.Time `json:"scheduleAt,omitempty"`
+}
+
+// BulkJobResponse describes the progress of a bulk operation.
+type BulkJobResponse struct {
+	// Job ID
+	ID string `json:"id"`
+	// Operation
+	Action string `json:"action" enums:"cancel,reschedule"`
+	// Execution status
+	Status string `json:"status" enums:"queued,running,completed,failed"`
+	// Number of matching pending messages at start
+	Total int64 `json:"total"`
+	// Number of updated messages
+	Processed int64 `json:"processed"`
+	// Failure reason
+	Error string `json:"error,omitempty"`
+	// Creation time
+	CreatedAt time.Time `json:"createdAt"`
+	// Last progress update time
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func newBulkJobResponse(job messages.BulkJob) BulkJobResponse {
+	return BulkJobResponse{
+		ID:        job.ID,
+		Action:    string(job.Action),
+		Status:    string(job.Status),
+		Total:     job.Total,
+		Processed: job.Processed,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
+
+// UpdateMessageRequest lists changes to a pending message. Omitted fields are left unchanged.
+type UpdateMessageRequest struct {
+	// New text content; only for text messages
+	TextMessage *smsgateway.TextMessage `json:"textMessage,omitempty"`
+	// New recipients; replace the existing ones
+	PhoneNumbers []string `json:"phoneNumbers,omitempty" validate:"omitempty,min=1,max=100,dive,required,min=10,max=128"`
+	// New SIM card number (1-based)
+	SimNumber *uint8 `json:"simNumber,omitempty" validate:"omitempty,max=3"`
+	// New expiration time
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+	// New scheduled send time
+	ScheduleAt *time.Time `json:"scheduleAt,omitempty"`
+	// New priority
+	Priority *smsgateway.MessagePriority `json:"priority,omitempty"`
+}
+
+func (r *UpdateMessageRequest) Validate() error {
+	if r.TextMessage == nil && r.PhoneNumbers == nil && r.SimNumber == nil &&
+		r.ValidUntil == nil && r.ScheduleAt == nil && r.Priority == nil {
+		return messages.ValidationError("no changes provided")
+	}
+
+	return nil
+}
+
+func (r *UpdateMessageRequest) ToDomain() messages.MessageUpdate {
+	var textContent *messages.TextMessageContent
+	if r.TextMessage != nil {
+		textContent = &messages.TextMessageContent{
+			Text: r.TextMessage.Text,
+		}
+	}
+
+	return messages.MessageUpdate{
+		TextContent:  textContent,
+		PhoneNumbers: r.PhoneNumbers,
+		SimNumber:    r.SimNumber,
+		ValidUntil:   r.ValidUntil,
+		ScheduleAt:   r.ScheduleAt,
+		Priority:     r.Priority,
+	}
+}
+
+// ContactRecipients lists the contacts a message is sent to in addition to its phone numbers.
+type ContactRecipients struct {
+	// Contacts to send the message to
+	ContactIDs []string `json:"contactIds,omitempty" validate:"omitempty,max=100,dive,len=21"`
+	// Contact groups whose members the message is sent to
+	GroupIDs []string `json:"groupIds,omitempty" validate:"omitempty,max=10,dive,len=21"`
+}
+
+// DeviceRouting narrows the devices the message may be sent from.
+type DeviceRouting struct {
+	// Device group to pick a random device from, may be combined with `deviceId`
+	DeviceGroup string `json:"deviceGroup,omitempty" validate:"omitempty,max=32"`
+	// Phone number of the SIM card to send from; the device and slot holding the card are picked automatically.
+	// Exclusive with `iccid` and `simNumber`
+	FromNumber string `json:"fromNumber,omitempty" validate:"omitempty,max=32,excluded_with=ICCID"`
+	// ICCID of the SIM card to send from; the device and slot holding the card are picked automatically.
+	// Exclusive with `fromNumber` and `simNumber`
+	ICCID string `json:"iccid,omitempty" validate:"omitempty,max=32"`
+}
+
+// Sim returns the SIM card the message must be sent from, if any.
+func (r DeviceRouting) Sim() devices.SimSelector {
+	return devices.SimSelector{
+		PhoneNumber: r.FromNumber,
+		ICCID:       r.ICCID,
+	}
+}
+
+// DeviceFilter returns the device filters of the routing options.
+func (r DeviceRouting) DeviceFilter() []devices.SelectFilter {
+	if r.DeviceGroup == "" {
+		return nil
+	}
+
+	return []devices.SelectFilter{devices.WithTag(r.DeviceGroup)}
+}
+
+// EnqueueMessageRequest extends smsgateway.Message with contact recipients, device routing and MMS content.
+// Text placeholders like `{{name}}` or `{{field}}` are filled from the contact of each recipient.
+type EnqueueMessageRequest struct {
+	smsgateway.Message
+	ContactRecipients
+	DeviceRouting
+
+	// MMS content, exclusive with `textMessage` and `dataMessage`; only sent from devices reporting the `mms` feature
+	MmsMessage *MmsMessage `json:"mmsMessage,omitempty"`
+}
+
+// BatchMessagesRequest is a batch of messages enqueued at once.
+type BatchMessagesRequest struct {
+	// Messages to enqueue
+	Messages []EnqueueMessageRequest `json:"messages" validate:"required,min=1,max=100"`
+}
+
+// newMessageInput converts the request message; ok is false if it has no content.
+// The MMS content, if set, takes the place of the text and data content.
+func newMessageInput(req smsgateway.Message, mmsContent *messages.MmsMessageContent) (messages.MessageInput, bool) {
+	var textContent *messages.TextMessageContent
+	var dataContent *messages.DataMessageContent
+	text, data := req.GetTextMessage(), req.GetDataMessage()
+	switch {
+	case mmsContent != nil:
+	case text != nil:
+		textContent = &messages.TextMessageContent{
+			Text: text.Text,
+		}
+	case data != nil:
+		dataContent = &messages.DataMessageContent{
+			Data: data.Data,
+			Port: data.Port,
+		}
+	default:
+		return messages.MessageInput{}, false
+	}
+
+	return messages.MessageInput{
+		MessageContent: messages.MessageContent{
+			TextContent: textContent,
+			DataContent: dataContent,
+			MmsContent:  mmsContent,
+		},
+
+		ID: req.ID,
+
+		PhoneNumbers: req.PhoneNumbers,
+		IsEncrypted:  req.IsEncrypted,
+
+		SimNumber:          req.SimNumber,
+		WithDeliveryReport: req.WithDeliveryReport,
+		TTL:                req.TTL,
+		ValidUntil:         req.ValidUntil,
+		ScheduleAt:         req.ScheduleAt,
+		Priority:           req.Priority,
+	}, true
+}
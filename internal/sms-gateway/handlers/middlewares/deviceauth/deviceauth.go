@@ -18,16 +18,11 @@ const LocalsDevice = "device"
 // invalid, the middleware will call c.Next() and continue with the request.
 func New(authSvc *auth.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get authorization header
-		auth := c.Get(fiber.HeaderAuthorization)
-
-		if len(auth) <= 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+		token := GetToken(c)
+		if token == "" {
 			return c.Next()
 		}
 
-		// Get the token
-		token := auth[7:]
-
 		device, err := authSvc.AuthorizeDevice(c.Context(), token)
 		if errors.Is(err, devices.ErrNotFound) {
 			return c.Next()
@@ -42,6 +37,18 @@ func New(authSvc *auth.Service) fiber.Handler {
 	}
 }
 
+// GetToken returns the device auth token from the "Authorization" header or an
+// empty string if the header is not in the form of "Bearer <token>".
+func GetToken(c *fiber.Ctx) string {
+	auth := c.Get(fiber.HeaderAuthorization)
+
+	if len(auth) <= 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+		return ""
+	}
+
+	return auth[7:]
+}
+
 // HasDevice checks if a device is present in the Locals of the given context.
 // It returns true if the Locals contain a device under the key LocalsDevice,
 // otherwise returns false.
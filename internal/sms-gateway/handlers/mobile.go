@@ -4,10 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/attachments"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
+	devicesHandler "github.com/android-sms-gateway/server/internal/sms-gateway/handlers/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/events"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/messages"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/deviceauth"
@@ -26,6 +29,50 @@ import (
 	"go.uber.org/zap"
 )
 
+// mobileDeviceResponse extends smsgateway.MobileDeviceResponse with the pause state set by the owner.
+type mobileDeviceResponse struct {
+	smsgateway.MobileDeviceResponse
+
+	// Messages are not routed to the device while it is paused
+	Paused bool `json:"paused"`
+	// Why the device is paused
+	PauseReason *string `json:"pauseReason,omitempty"`
+	// Messages are not routed to the device until an admin approves it
+	PendingApproval bool `json:"pendingApproval"`
+}
+
+// mobileRegisterRequest extends smsgateway.MobileRegisterRequest with the app features.
+type mobileRegisterRequest struct {
+	smsgateway.MobileRegisterRequest
+
+	// Optional capabilities of the app, MMS messages are only routed to devices with `mms`
+	Features []string `json:"features,omitempty" validate:"omitempty,max=16,dive,oneof=mms"`
+}
+
+// mobileUpdateRequest extends smsgateway.MobileUpdateRequest with the app features.
+type mobileUpdateRequest struct {
+	smsgateway.MobileUpdateRequest
+
+	// Optional capabilities of the app, omit to keep the reported ones
+	Features []string `json:"features,omitempty" validate:"omitempty,max=16,dive,oneof=mms"`
+}
+
+// mobileRegisterResponse extends smsgateway.MobileRegisterResponse with the approval state.
+type mobileRegisterResponse struct {
+	smsgateway.MobileRegisterResponse
+
+	// Messages are not routed to the device until an admin approves it
+	PendingApproval bool `json:"pendingApproval"`
+}
+
+// mobileRotateTokenResponse is the new auth token of the device.
+type mobileRotateTokenResponse struct {
+	// New auth token
+	Token string `json:"token"`
+	// The previous token is accepted until this time
+	PreviousTokenExpiresAt time.Time `json:"previousTokenExpiresAt"`
+}
+
 type mobileHandler struct {
 	base.Handler
 
@@ -33,10 +80,12 @@ type mobileHandler struct {
 	usersSvc   *users.Service
 	devicesSvc *devices.Service
 
-	messagesCtrl *messages.MobileController
-	webhooksCtrl *webhooks.MobileController
-	settingsCtrl *settings.MobileController
-	eventsCtrl   *events.MobileController
+	messagesCtrl    *messages.MobileController
+	attachmentsCtrl *attachments.MobileController
+	webhooksCtrl    *webhooks.MobileController
+	settingsCtrl    *settings.MobileController
+	eventsCtrl      *events.MobileController
+	devicesCtrl     *devicesHandler.MobileController
 
 	idGen func() string
 }
@@ -47,9 +96,11 @@ func newMobileHandler(
 	devicesSvc *devices.Service,
 
 	messagesCtrl *messages.MobileController,
+	attachmentsCtrl *attachments.MobileController,
 	webhooksCtrl *webhooks.MobileController,
 	settingsCtrl *settings.MobileController,
 	eventsCtrl *events.MobileController,
+	devicesCtrl *devicesHandler.MobileController,
 
 	logger *zap.Logger,
 	validator *validator.Validate,
@@ -66,10 +117,12 @@ func newMobileHandler(
 		usersSvc:   usersSvc,
 		devicesSvc: devicesSvc,
 
-		messagesCtrl: messagesCtrl,
-		webhooksCtrl: webhooksCtrl,
-		settingsCtrl: settingsCtrl,
-		eventsCtrl:   eventsCtrl,
+		messagesCtrl:    messagesCtrl,
+		attachmentsCtrl: attachmentsCtrl,
+		webhooksCtrl:    webhooksCtrl,
+		settingsCtrl:    settingsCtrl,
+		eventsCtrl:      eventsCtrl,
+		devicesCtrl:     devicesCtrl,
 
 		idGen: idGen,
 	}
@@ -88,8 +141,8 @@ func (h *mobileHandler) Register(router fiber.Router) {
 				// 2. User is already authenticated - allowing device registration for existing users
 				return h.authSvc.IsPublic() || userauth.HasUser(c)
 			},
-			Validator: func(_ *fiber.Ctx, token string) (bool, error) {
-				err := h.authSvc.AuthorizeRegistration(token)
+			Validator: func(c *fiber.Ctx, token string) (bool, error) {
+				err := h.authSvc.AuthorizeRegistration(c.Context(), token)
 				if err != nil {
 					return false, fmt.Errorf("authorization failed: %w", err)
 				}
@@ -115,30 +168,38 @@ func (h *mobileHandler) Register(router fiber.Router) {
 	router.Use(deviceauth.DeviceRequired())
 
 	router.Patch("/device", deviceauth.WithDevice(h.patchDevice))
+	router.Post("/device/token", deviceauth.WithDevice(h.rotateToken))
 
 	// Should be under `userauth.NewBasic` protection instead of `deviceauth`
 	router.Patch("/user/password", deviceauth.WithDevice(h.changePassword))
 
 	h.messagesCtrl.Register(router.Group("/message"))
 	h.messagesCtrl.Register(router.Group("/messages"))
+	h.attachmentsCtrl.Register(router.Group("/attachments"))
 	h.webhooksCtrl.Register(router.Group("/webhooks"))
 	h.settingsCtrl.Register(router.Group("/settings"))
 	h.eventsCtrl.Register(router.Group("/events"))
+	h.devicesCtrl.Register(router.Group("/telemetry"))
 }
 
 //	@Summary		Get device information
 //	@Description	Returns device information
 //	@Tags			Device
 //	@Produce		json
-//	@Success		200	{object}	smsgateway.MobileDeviceResponse	"Device information"
-//	@Failure		500	{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Success		200	{object}	mobileDeviceResponse		"Device information"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
 //	@Router			/mobile/v1/device [get]
 //
 // Get device information.
 func (h *mobileHandler) getDevice(device devices.Device, c *fiber.Ctx) error {
-	res := smsgateway.MobileDeviceResponse{
-		ExternalIP: c.IP(),
-		Device:     nil,
+	res := mobileDeviceResponse{
+		MobileDeviceResponse: smsgateway.MobileDeviceResponse{
+			ExternalIP: c.IP(),
+			Device:     nil,
+		},
+		Paused:          device.IsPaused(),
+		PauseReason:     device.PauseReason,
+		PendingApproval: device.IsPendingApproval(),
 	}
 
 	if !device.IsEmpty() {
@@ -150,23 +211,25 @@ func (h *mobileHandler) getDevice(device devices.Device, c *fiber.Ctx) error {
 
 //	@Summary		Register device
 //	@Description	Registers new device for new or existing user. Returns user credentials only for new users
+//	@Description	In private mode the server may require an admin approval, messages are not routed to the device until it is approved.
 //	@Security		ApiAuth
 //	@Security		UserCode
 //	@Security		ServerKey
 //	@Tags			Device
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		smsgateway.MobileRegisterRequest	true	"Device registration request"
-//	@Success		201		{object}	smsgateway.MobileRegisterResponse	"Device registered"
+//	@Param			request	body		mobileRegisterRequest				true	"Device registration request"
+//	@Success		201		{object}	mobileRegisterResponse				"Device registered"
 //	@Failure		400		{object}	smsgateway.ErrorResponse			"Invalid request"
 //	@Failure		401		{object}	smsgateway.ErrorResponse			"Unauthorized (private mode only)"
+//	@Failure		403		{object}	smsgateway.ErrorResponse			"Device limit reached"
 //	@Failure		429		{object}	smsgateway.ErrorResponse			"Too many requests"
 //	@Failure		500		{object}	smsgateway.ErrorResponse			"Internal server error"
 //	@Router			/mobile/v1/device [post]
 //
 // Register device.
 func (h *mobileHandler) postDevice(c *fiber.Ctx) error {
-	req := new(smsgateway.MobileRegisterRequest)
+	req := new(mobileRegisterRequest)
 
 	if err := h.BodyParserValidator(c, req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -197,29 +260,36 @@ func (h *mobileHandler) postDevice(c *fiber.Ctx) error {
 			DeviceUpdate: devices.DeviceUpdate{
 				PushToken: req.PushToken,
 				SimCards:  h.simCardsToDomain(req.SimCards),
+				Features:  featuresToDomain(req.Features),
 			},
 			Name: req.Name,
 		},
 	)
+	if errors.Is(err, auth.ErrDeviceLimitReached) {
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	}
 	if err != nil {
 		return fmt.Errorf("failed to register device: %w", err)
 	}
 
 	return c.Status(fiber.StatusCreated).
-		JSON(smsgateway.MobileRegisterResponse{
-			Id:       device.ID,
-			Token:    device.AuthToken,
-			Login:    userID,
-			Password: password,
+		JSON(mobileRegisterResponse{
+			MobileRegisterResponse: smsgateway.MobileRegisterResponse{
+				Id:       device.ID,
+				Token:    device.AuthToken,
+				Login:    userID,
+				Password: password,
+			},
+			PendingApproval: device.IsPendingApproval(),
 		})
 }
 
 //	@Summary		Update device
-//	@Description	Updates push token for device
+//	@Description	Updates push token, SIM cards and features of the device
 //	@Security		MobileToken
 //	@Tags			Device
 //	@Accept			json
-//	@Param			request	body	smsgateway.MobileUpdateRequest	true	"Device update request"
+//	@Param			request	body	mobileUpdateRequest	true	"Device update request"
 //	@Success		204		"Successfully updated"
 //	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
 //	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden (wrong device ID)"
@@ -228,7 +298,7 @@ func (h *mobileHandler) postDevice(c *fiber.Ctx) error {
 //
 // Update device.
 func (h *mobileHandler) patchDevice(device devices.Device, c *fiber.Ctx) error {
-	req := new(smsgateway.MobileUpdateRequest)
+	req := new(mobileUpdateRequest)
 
 	if err := h.BodyParserValidator(c, req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -241,6 +311,7 @@ func (h *mobileHandler) patchDevice(device devices.Device, c *fiber.Ctx) error {
 	err := h.devicesSvc.Update(c.Context(), req.Id, devices.DeviceUpdate{
 		PushToken: lo.EmptyableToPtr(req.PushToken),
 		SimCards:  h.simCardsToDomain(req.SimCards),
+		Features:  featuresToDomain(req.Features),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update device: %w", err)
@@ -249,6 +320,33 @@ func (h *mobileHandler) patchDevice(device devices.Device, c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+//	@Summary		Rotate auth token
+//	@Description	Issues a new auth token for the device. The replaced token stays valid for a grace period: if the response is lost, the request can be retried with the replaced token during the period and returns the current token without rotating it again.
+//	@Security		MobileToken
+//	@Tags			Device
+//	@Produce		json
+//	@Success		200	{object}	mobileRotateTokenResponse	"New token"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Neither the current token nor the replaced one in its grace period"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/device/token [post]
+//
+// Rotate auth token.
+func (h *mobileHandler) rotateToken(device devices.Device, c *fiber.Ctx) error {
+	updated, graceUntil, err := h.devicesSvc.RotateToken(c.Context(), device, deviceauth.GetToken(c))
+	if errors.Is(err, devices.ErrTokenNotCurrent) {
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rotate token: %w", err)
+	}
+
+	return c.JSON(mobileRotateTokenResponse{
+		Token:                  updated.AuthToken,
+		PreviousTokenExpiresAt: graceUntil,
+	})
+}
+
 //	@Summary		Get one-time code for device registration
 //	@Description	Returns one-time code for device registration
 //	@Security		ApiAuth
@@ -314,6 +412,15 @@ func (h *mobileHandler) simCardsToDomain(simCards []smsgateway.SimCard) []device
 	})
 }
 
+// featuresToDomain keeps nil, so the update doesn't reset the features the request has omitted.
+func featuresToDomain(features []string) []devices.Feature {
+	if features == nil {
+		return nil
+	}
+
+	return lo.Uniq(lo.Map(features, func(f string, _ int) devices.Feature { return devices.Feature(f) }))
+}
+
 func (h *mobileHandler) errorsHandler(c *fiber.Ctx) error {
 	err := c.Next()
 	if err == nil {
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/admin"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/attachments"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/campaigns"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/contacts"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/events"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/inbox"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/logs"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/numbers"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/settings"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/thirdparty"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/webhooks"
@@ -26,18 +31,26 @@ func Module() fx.Option {
 			http.AsApiHandler(newThirdPartyHandler),
 			http.AsApiHandler(newMobileHandler),
 			http.AsApiHandler(newUpstreamHandler),
+			http.AsApiHandler(newLinksHandler),
 		),
 		fx.Provide(
 			NewHealthHandler,
 			messages.NewThirdPartyController,
 			messages.NewMobileController,
+			campaigns.NewThirdPartyController,
+			contacts.NewThirdPartyController,
+			attachments.NewThirdPartyController,
+			attachments.NewMobileController,
+			numbers.NewThirdPartyController,
 			webhooks.NewThirdPartyController,
 			webhooks.NewMobileController,
 			devices.NewThirdPartyController,
+			devices.NewMobileController,
 			settings.NewThirdPartyController,
 			settings.NewMobileController,
 			inbox.NewThirdPartyController,
 			logs.NewThirdPartyController,
+			admin.NewThirdPartyController,
 			events.NewMobileController,
 			fx.Private,
 		),
@@ -0,0 +1,105 @@
+package numbers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	MessagesSvc *messages.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	messagesSvc *messages.Service
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger,
+			Validator: params.Validator,
+		},
+		messagesSvc: params.MessagesSvc,
+	}
+}
+
+//	@Summary		Look up phone numbers
+//	@Description	Validates and normalizes phone numbers the same way as the send endpoint does and returns their country, type, carrier and timezones.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Numbers
+//	@Accept			json
+//	@Produce		json
+//	@Param			phoneRegion	query		string						false	"Region for phone numbers without a country code, overrides the user and server defaults"	minLength(2)	maxLength(2)
+//	@Param			request		body		LookupRequest				true	"Phone numbers"
+//	@Success		200			{object}	[]LookupResponse			"Phone number details in the request order"
+//	@Failure		400			{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401			{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500			{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/numbers/lookup [post]
+//
+// Look up phone numbers.
+func (h *ThirdPartyController) postLookup(userID string, c *fiber.Ctx) error {
+	params := new(lookupQueryParams)
+	if err := h.QueryParserValidator(c, params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	req := new(LookupRequest)
+	if err := h.BodyParserValidator(c, req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	items, err := h.messagesSvc.LookupPhoneNumbers(userID, req.PhoneNumbers, lo.FromPtr(params.PhoneRegion))
+	if err != nil {
+		return fmt.Errorf("failed to look up phone numbers: %w", err)
+	}
+
+	return c.JSON(lo.Map(items, func(item messages.PhoneNumberInfo, _ int) LookupResponse {
+		return newLookupResponse(item)
+	}))
+}
+
+func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError
+	}
+
+	var validationError messages.ValidationError
+	if errors.As(err, &validationError) {
+		return fiber.NewError(fiber.StatusBadRequest, validationError.Error())
+	}
+
+	h.Logger.Error("failed to handle request", zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "failed to handle request")
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Use(h.errorHandler)
+
+	router.Post("lookup", permissions.RequireScope(ScopeLookup), userauth.WithUserID(h.postLookup))
+}
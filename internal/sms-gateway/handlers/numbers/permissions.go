@@ -0,0 +1,5 @@
+package numbers
+
+const (
+	ScopeLookup = "numbers:lookup"
+)
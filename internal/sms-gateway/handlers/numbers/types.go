@@ -0,0 +1,51 @@
+package numbers
+
+import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+)
+
+// LookupRequest lists the phone numbers to look up.
+type LookupRequest struct {
+	// Phone numbers in any format accepted by the send endpoint
+	PhoneNumbers []string `json:"phoneNumbers" validate:"required,min=1,max=1000,dive,required,max=128"`
+}
+
+// LookupResponse describes a phone number.
+type LookupResponse struct {
+	// Phone number as provided
+	Input string `json:"input"`
+	// Normalized phone number in E.164 format, digits only for short codes
+	PhoneNumber string `json:"phoneNumber,omitempty"`
+	// ISO 3166-1 alpha-2 country code
+	Country string `json:"country,omitempty"`
+	// Number type
+	Type string `json:"type,omitempty" enums:"mobile,fixed_line,voip,short_code,other"`
+	// Original carrier of the number, it may have been ported since
+	Carrier string `json:"carrier,omitempty"`
+	// Timezones of the number
+	Timezones []string `json:"timezones,omitempty"`
+	// Whether the number would be accepted by the send endpoint
+	Valid bool `json:"valid"`
+	// Validity reason
+	Reason string `json:"reason" enums:"valid,not_a_number,invalid_country_code,too_short,too_long,invalid_length,invalid_number,type_not_allowed,country_not_allowed,country_denied"`
+	// Human-readable description of the rejection
+	Error string `json:"error,omitempty"`
+}
+
+func newLookupResponse(info messages.PhoneNumberInfo) LookupResponse {
+	return LookupResponse{
+		Input:       info.Input,
+		PhoneNumber: info.PhoneNumber,
+		Country:     info.Country,
+		Type:        string(info.Type),
+		Carrier:     info.Carrier,
+		Timezones:   info.Timezones,
+		Valid:       info.Valid,
+		Reason:      string(info.Reason),
+		Error:       info.Error,
+	}
+}
+
+type lookupQueryParams struct {
+	PhoneRegion *string `query:"phoneRegion" validate:"omitempty,len=2,alpha"`
+}
@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
@@ -82,18 +83,12 @@ func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
 //
 // Update settings.
 func (h *ThirdPartyController) put(userID string, c *fiber.Ctx) error {
-	if err := h.BodyParserValidator(c, new(smsgateway.DeviceSettings)); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid settings format: %v", err))
-	}
-
-	settings := make(map[string]any)
-
-	if err := c.BodyParser(&settings); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request body: %v", err))
+	settings, err := h.parseSettings(c)
+	if err != nil {
+		return err
 	}
 
 	updated, err := h.settingsSvc.ReplaceSettings(userID, settings)
-
 	if err != nil {
 		return fmt.Errorf("failed to update settings: %w", err)
 	}
@@ -118,26 +113,164 @@ func (h *ThirdPartyController) put(userID string, c *fiber.Ctx) error {
 //
 // Partially update settings.
 func (h *ThirdPartyController) patch(userID string, c *fiber.Ctx) error {
-	if err := h.BodyParserValidator(c, new(smsgateway.DeviceSettings)); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid settings format: %v", err))
+	settings, err := h.parseSettings(c)
+	if err != nil {
+		return err
 	}
 
-	settings := make(map[string]any)
+	updated, err := h.settingsSvc.UpdateSettings(userID, settings)
+	if err != nil {
+		return fmt.Errorf("failed to update settings: %w", err)
+	}
 
-	if err := c.BodyParser(&settings); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request body: %v", err))
+	return c.JSON(updated)
+}
+
+//	@Summary		Get group settings
+//	@Description	Returns the settings overridden for the devices of a group
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Settings
+//	@Produce		json
+//	@Param			group	path		string						true	"Device group"
+//	@Success		200		{object}	smsgateway.DeviceSettings	"Settings"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/settings/groups/{group} [get]
+//
+// Get group settings.
+func (h *ThirdPartyController) getGroup(userID string, c *fiber.Ctx) error {
+	settings, err := h.settingsSvc.GetGroupSettings(userID, c.Params("group"))
+	if err != nil {
+		return fmt.Errorf("failed to get group settings: %w", err)
 	}
 
-	updated, err := h.settingsSvc.UpdateSettings(userID, settings)
+	return c.JSON(settings)
+}
+
+//	@Summary		Replace group settings
+//	@Description	Replaces the settings overridden for the devices of a group. Only the settings applied by devices may be overridden.
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Settings
+//	@Accept			json
+//	@Produce		json
+//	@Param			group	path		string						true	"Device group"
+//	@Param			request	body		smsgateway.DeviceSettings	true	"Settings"
+//	@Success		200		{object}	object						"Settings updated"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/settings/groups/{group} [put]
+//
+// Replace group settings.
+func (h *ThirdPartyController) putGroup(userID string, c *fiber.Ctx) error {
+	settings, err := h.parseSettings(c)
 	if err != nil {
-		return fmt.Errorf("failed to update settings: %w", err)
+		return err
+	}
+
+	updated, err := h.settingsSvc.ReplaceGroupSettings(userID, c.Params("group"), settings)
+	if err != nil {
+		return fmt.Errorf("failed to update group settings: %w", err)
 	}
 
 	return c.JSON(updated)
 }
 
+//	@Summary		Partially update group settings
+//	@Description	Partially updates the settings overridden for the devices of a group
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Settings
+//	@Accept			json
+//	@Produce		json
+//	@Param			group	path		string						true	"Device group"
+//	@Param			request	body		smsgateway.DeviceSettings	true	"Settings"
+//	@Success		200		{object}	object						"Settings updated"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/settings/groups/{group} [patch]
+//
+// Partially update group settings.
+func (h *ThirdPartyController) patchGroup(userID string, c *fiber.Ctx) error {
+	settings, err := h.parseSettings(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.settingsSvc.UpdateGroupSettings(userID, c.Params("group"), settings)
+	if err != nil {
+		return fmt.Errorf("failed to update group settings: %w", err)
+	}
+
+	return c.JSON(updated)
+}
+
+//	@Summary		Delete group settings
+//	@Description	Removes the settings overridden for the devices of a group
+//	@Security		ApiAuth
+//	@Security		JWTAuth
+//	@Tags			User, Settings
+//	@Param			group	path	string	true	"Device group"
+//	@Success		204		"Successfully removed"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/settings/groups/{group} [delete]
+//
+// Delete group settings.
+func (h *ThirdPartyController) deleteGroup(userID string, c *fiber.Ctx) error {
+	if err := h.settingsSvc.DeleteGroupSettings(userID, c.Params("group")); err != nil {
+		return fmt.Errorf("failed to delete group settings: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// parseSettings validates the request body and returns it as a map.
+func (h *ThirdPartyController) parseSettings(c *fiber.Ctx) (map[string]any, error) {
+	if err := h.BodyParserValidator(c, new(smsgateway.DeviceSettings)); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid settings format: %v", err))
+	}
+
+	settings := make(map[string]any)
+	if err := c.BodyParser(&settings); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request body: %v", err))
+	}
+
+	return settings, nil
+}
+
+func (h *ThirdPartyController) errorHandler(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	var validationErr devices.ValidationError
+	if errors.As(err, &validationErr) {
+		return fiber.NewError(fiber.StatusBadRequest, validationErr.Error())
+	}
+
+	return err
+}
+
 func (h *ThirdPartyController) Register(app fiber.Router) {
+	app.Use(h.errorHandler)
+
 	app.Get("", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.get))
 	app.Patch("", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.patch))
 	app.Put("", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.put))
+
+	app.Get("groups/:group", permissions.RequireScope(ScopeRead), userauth.WithUserID(h.getGroup))
+	app.Patch("groups/:group", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.patchGroup))
+	app.Put("groups/:group", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.putGroup))
+	app.Delete("groups/:group", permissions.RequireScope(ScopeWrite), userauth.WithUserID(h.deleteGroup))
 }
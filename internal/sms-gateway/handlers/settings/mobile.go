@@ -31,7 +31,7 @@ func NewMobileController(
 }
 
 //	@Summary		Get settings
-//	@Description	Returns settings for a device
+//	@Description	Returns settings for a device, overridden by the settings of its groups
 //	@Security		MobileToken
 //	@Tags			Device, Settings
 //	@Produce		json
@@ -42,7 +42,7 @@ func NewMobileController(
 //
 // Get settings.
 func (h *MobileController) get(device devices.Device, c *fiber.Ctx) error {
-	settings, err := h.settingsSvc.GetSettings(device.UserID, false)
+	settings, err := h.settingsSvc.GetDeviceSettings(device.UserID, device.Tags)
 	if err != nil {
 		h.Logger.Error(
 			"failed to get settings",
@@ -3,7 +3,6 @@ package webhooks
 import (
 	"fmt"
 
-	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/permissions"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
@@ -45,7 +44,7 @@ func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyContr
 //	@Security		JWTAuth
 //	@Tags			User, Webhooks
 //	@Produce		json
-//	@Success		200	{object}	[]smsgateway.Webhook		"Webhook list"
+//	@Success		200	{object}	[]webhooks.WebhookDTO		"Webhook list"
 //	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
 //	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
 //	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
@@ -63,13 +62,14 @@ func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
 
 //	@Summary		Register webhook
 //	@Description	Registers webhook. If webhook with same ID already exists, it will be replaced
+//	@Description	A webhook may be limited to a device with `deviceId` or to a device group with `deviceGroup`.
 //	@Security		ApiAuth
 //	@Security		JWTAuth
 //	@Tags			User, Webhooks
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		smsgateway.Webhook			true	"Webhook"
-//	@Success		201		{object}	smsgateway.Webhook			"Created"
+//	@Param			request	body		webhooks.WebhookDTO			true	"Webhook"
+//	@Success		201		{object}	webhooks.WebhookDTO			"Created"
 //	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
 //	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
 //	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
@@ -78,7 +78,7 @@ func (h *ThirdPartyController) get(userID string, c *fiber.Ctx) error {
 //
 // Register webhook.
 func (h *ThirdPartyController) post(userID string, c *fiber.Ctx) error {
-	dto := new(smsgateway.Webhook)
+	dto := new(webhooks.WebhookDTO)
 
 	if err := h.BodyParserValidator(c, dto); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
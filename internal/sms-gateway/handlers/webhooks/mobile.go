@@ -33,18 +33,22 @@ func NewMobileController(
 }
 
 //	@Summary		List webhooks
-//	@Description	Returns list of registered webhooks for device
+//	@Description	Returns list of registered webhooks for device, including the webhooks of its groups
 //	@Security		MobileToken
 //	@Tags			Device, Webhooks
 //	@Produce		json
-//	@Success		200	{object}	[]smsgateway.Webhook		"Webhook list"
+//	@Success		200	{object}	[]webhooks.WebhookDTO		"Webhook list"
 //	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
 //	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
 //	@Router			/mobile/v1/webhooks [get]
 //
 // List webhooks.
 func (h *MobileController) get(device devices.Device, c *fiber.Ctx) error {
-	items, err := h.webhooksSvc.Select(device.UserID, webhooks.WithDeviceID(device.ID, false))
+	items, err := h.webhooksSvc.Select(
+		device.UserID,
+		webhooks.WithDeviceID(device.ID, false),
+		webhooks.WithDeviceGroups(device.Tags),
+	)
 	if err != nil {
 		return fmt.Errorf("failed to select webhooks: %w", err)
 	}
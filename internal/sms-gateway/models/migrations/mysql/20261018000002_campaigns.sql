@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `campaigns` (
+    `id` char(21) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `name` varchar(128) NOT NULL,
+    `text` text NOT NULL,
+    `device_pool` json NULL,
+    `throttle_window` int unsigned NOT NULL DEFAULT 0,
+    `status` enum('Active', 'Paused', 'Completed', 'Aborted') NOT NULL DEFAULT 'Active',
+    `total` int unsigned NOT NULL,
+    `generated` int unsigned NOT NULL DEFAULT 0,
+    `started_at` datetime(3) NOT NULL,
+    `paused_at` datetime(3) NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    INDEX `idx_campaigns_user` (`user_id`),
+    INDEX `idx_campaigns_status` (`status`),
+    CONSTRAINT `fk_campaigns_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `campaign_recipients` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `campaign_id` char(21) NOT NULL,
+    `position` int unsigned NOT NULL,
+    `phone_number` varchar(128) NOT NULL,
+    `message_id` varchar(36) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_campaign_recipients_campaign_position` (`campaign_id`, `position`),
+    CONSTRAINT `fk_campaigns_recipients` FOREIGN KEY (`campaign_id`) REFERENCES `campaigns`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `messages`
+ADD `campaign_id` char(21) NULL DEFAULT NULL,
+ADD INDEX `idx_messages_campaign` (`campaign_id`),
+ADD CONSTRAINT `fk_messages_campaign` FOREIGN KEY (`campaign_id`) REFERENCES `campaigns`(`id`) ON DELETE SET NULL;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `messages`
+DROP FOREIGN KEY `fk_messages_campaign`,
+DROP INDEX `idx_messages_campaign`,
+DROP `campaign_id`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `campaign_recipients`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `campaigns`;
+-- +goose StatementEnd
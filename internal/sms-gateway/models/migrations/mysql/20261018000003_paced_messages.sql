@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `messages`
+ADD `is_paced` tinyint(1) unsigned NOT NULL DEFAULT 0,
+ADD INDEX `idx_messages_paced` (`is_paced`, `schedule_at`);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `messages`
+DROP INDEX `idx_messages_paced`,
+DROP `is_paced`;
+-- +goose StatementEnd
@@ -0,0 +1,129 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `messages`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Quarantined',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL DEFAULT 'Pending';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `message_recipients`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Quarantined',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL DEFAULT 'Pending';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `message_states`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Quarantined',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `message_recipient_states`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Quarantined',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `messages`
+ADD `content_verdict` text NULL;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `messages`
+DROP `content_verdict`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE `messages`
+SET `state` = 'Failed'
+WHERE `state` = 'Quarantined';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `messages`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL DEFAULT 'Pending';
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE `message_recipients`
+SET `state` = 'Failed'
+WHERE `state` = 'Quarantined';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `message_recipients`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL DEFAULT 'Pending';
+-- +goose StatementEnd
+-- +goose StatementBegin
+DELETE FROM `message_states`
+WHERE `state` = 'Quarantined';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `message_states`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DELETE FROM `message_recipient_states`
+WHERE `state` = 'Quarantined';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `message_recipient_states`
+MODIFY COLUMN `state` enum(
+        'Pending',
+        'Cancelling',
+        'Cancelled',
+        'Processed',
+        'Sent',
+        'Delivered',
+        'Failed'
+    ) NOT NULL;
+-- +goose StatementEnd
\ No newline at end of file
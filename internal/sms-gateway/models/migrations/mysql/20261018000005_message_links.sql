@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `message_links` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `message_id` BIGINT UNSIGNED NOT NULL,
+    `code` varchar(16) NOT NULL,
+    `url` text NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_message_links_code` (`code`),
+    INDEX `idx_message_links_message_id` (`message_id`),
+    CONSTRAINT `fk_messages_links` FOREIGN KEY (`message_id`) REFERENCES `messages`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `message_link_clicks` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `link_id` BIGINT UNSIGNED NOT NULL,
+    `recipient_id` BIGINT UNSIGNED NULL,
+    `user_agent` varchar(512) NOT NULL,
+    `clicked_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_message_link_clicks_link_id` (`link_id`),
+    INDEX `idx_message_link_clicks_clicked_at` (`clicked_at`),
+    CONSTRAINT `fk_message_links_clicks` FOREIGN KEY (`link_id`) REFERENCES `message_links`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_message_link_clicks_recipient` FOREIGN KEY (`recipient_id`) REFERENCES `message_recipients`(`id`) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `message_link_clicks`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `message_links`;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `attachments` (
+    `id` char(21) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `content_type` varchar(128) NOT NULL,
+    `size` BIGINT UNSIGNED NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_attachments_user_id` (`user_id`),
+    INDEX `idx_attachments_created_at` (`created_at`),
+    CONSTRAINT `fk_attachments_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `message_attachments` (
+    `message_id` BIGINT UNSIGNED NOT NULL,
+    `attachment_id` char(21) NOT NULL,
+    PRIMARY KEY (`message_id`, `attachment_id`),
+    INDEX `idx_message_attachments_attachment_id` (`attachment_id`),
+    CONSTRAINT `fk_messages_attachments` FOREIGN KEY (`message_id`) REFERENCES `messages`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_message_attachments_attachment` FOREIGN KEY (`attachment_id`) REFERENCES `attachments`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `messages`
+MODIFY COLUMN `type` enum('Text', 'Data', 'MMS') NOT NULL DEFAULT 'Text';
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM `messages`
+WHERE `type` = 'MMS';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `messages`
+MODIFY COLUMN `type` enum('Text', 'Data') NOT NULL DEFAULT 'Text';
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `message_attachments`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `attachments`;
+-- +goose StatementEnd
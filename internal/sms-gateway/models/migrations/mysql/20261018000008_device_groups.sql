@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `devices`
+ADD `tags` json NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `webhooks`
+ADD `device_group` varchar(32) NULL,
+ADD INDEX `idx_webhooks_device_group` (`device_group`);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `device_group_settings` (
+    `user_id` varchar(32) NOT NULL,
+    `device_group` varchar(32) NOT NULL,
+    `settings` json NOT NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`user_id`, `device_group`),
+    CONSTRAINT `fk_device_group_settings_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `device_group_settings`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DELETE FROM `webhooks`
+WHERE `device_group` IS NOT NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `webhooks`
+DROP INDEX `idx_webhooks_device_group`,
+DROP `device_group`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `devices`
+DROP `tags`;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `devices`
+ADD `paused_at` datetime(3) NULL,
+ADD `pause_reason` varchar(256) NULL;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `devices`
+DROP `pause_reason`,
+DROP `paused_at`;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `device_telemetry` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `device_id` char(21) NOT NULL,
+    `battery_level` TINYINT UNSIGNED NULL,
+    `charging` tinyint(1) NULL,
+    `signals` json NULL,
+    `free_storage` BIGINT UNSIGNED NULL,
+    `app_version` varchar(32) NULL,
+    `queue_length` INT UNSIGNED NULL,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_device_telemetry_device_id_created_at` (`device_id`, `created_at`),
+    CONSTRAINT `fk_device_telemetry_device` FOREIGN KEY (`device_id`) REFERENCES `devices`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `device_telemetry`;
+-- +goose StatementEnd
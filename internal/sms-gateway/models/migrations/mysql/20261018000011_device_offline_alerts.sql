@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `device_alert_settings` (
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    `user_id` varchar(32) NOT NULL,
+    `offline_after_seconds` INT UNSIGNED NOT NULL,
+    `callback_url` varchar(2048) NULL,
+    `email` varchar(254) NULL,
+    PRIMARY KEY (`user_id`),
+    CONSTRAINT `fk_device_alert_settings_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `device_incidents` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `user_id` varchar(32) NOT NULL,
+    `device_id` char(21) NOT NULL,
+    `last_seen_at` datetime(3) NOT NULL,
+    `started_at` datetime(3) NOT NULL,
+    `returned_at` datetime(3) NULL,
+    `resolved_at` datetime(3) NULL,
+    `flaps` INT UNSIGNED NOT NULL DEFAULT 0,
+    `alert_sent_at` datetime(3) NULL,
+    `recovery_sent_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_device_incidents_user_id` (`user_id`),
+    INDEX `idx_device_incidents_device_id_resolved_at` (`device_id`, `resolved_at`),
+    CONSTRAINT `fk_device_incidents_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_device_incidents_device` FOREIGN KEY (`device_id`) REFERENCES `devices`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `device_incidents`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `device_alert_settings`;
+-- +goose StatementEnd
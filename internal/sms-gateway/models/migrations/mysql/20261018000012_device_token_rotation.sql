@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `devices`
+ADD `prev_auth_token` char(21) NULL,
+ADD `prev_auth_token_expires_at` datetime(3) NULL,
+ADD UNIQUE INDEX `idx_devices_prev_auth_token` (`prev_auth_token`);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `devices`
+DROP INDEX `idx_devices_prev_auth_token`,
+DROP `prev_auth_token_expires_at`,
+DROP `prev_auth_token`;
+-- +goose StatementEnd
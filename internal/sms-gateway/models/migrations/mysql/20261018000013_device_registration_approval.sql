@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `devices`
+ADD `status` enum('active', 'pending_approval') NOT NULL DEFAULT 'active',
+ADD INDEX `idx_devices_status` (`status`);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `registration_tokens` (
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    `id` char(21) NOT NULL,
+    `name` varchar(128) NOT NULL,
+    `token_hash` char(64) NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_registration_tokens_token_hash` (`token_hash`),
+    INDEX `idx_registration_tokens_expires_at` (`expires_at`)
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `registration_tokens`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `devices`
+DROP INDEX `idx_devices_status`,
+DROP `status`;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `message_edits` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `message_id` BIGINT UNSIGNED NOT NULL,
+    `fields` json NOT NULL,
+    `edited_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_message_edits_message_id` (`message_id`),
+    CONSTRAINT `fk_messages_edits` FOREIGN KEY (`message_id`) REFERENCES `messages`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `message_edits`;
+-- +goose StatementEnd
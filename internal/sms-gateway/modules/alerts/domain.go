@@ -0,0 +1,59 @@
+package alerts
+
+import "time"
+
+// Settings configures the offline alerts of a user. At least one channel is set.
+type Settings struct {
+	// OfflineAfter is the time since the device was last seen after which it is offline.
+	OfflineAfter time.Duration
+	// CallbackURL receives a POST request for each notification.
+	CallbackURL *string
+	// Email receives a message for each notification.
+	Email *string
+}
+
+// Incident is a period a device was offline. A device that checks in again is
+// only considered recovered after it stays online for a while, so a flapping
+// connection extends the incident instead of opening a new one.
+type Incident struct {
+	ID       uint64
+	DeviceID string
+
+	// LastSeenAt is the last time the device was seen before going offline.
+	LastSeenAt time.Time
+	// StartedAt is when the device was detected offline.
+	StartedAt time.Time
+	// ReturnedAt is when the device checked in again, nil while it is offline.
+	ReturnedAt *time.Time
+	// ResolvedAt is when the device was considered recovered.
+	ResolvedAt *time.Time
+	// Flaps is the number of times the device went offline again before recovering.
+	Flaps int
+}
+
+// IncidentsFilter selects the incidents of a user, newest first.
+type IncidentsFilter struct {
+	DeviceID string
+	Open     *bool
+	Limit    int
+}
+
+// NotificationKind is the kind of a notification sent to the user.
+type NotificationKind string
+
+const (
+	NotificationOffline   NotificationKind = "device:offline"
+	NotificationRecovered NotificationKind = "device:recovered"
+)
+
+// Notification is an incident update to be sent to the user.
+type Notification struct {
+	Kind     NotificationKind
+	Incident Incident
+
+	UserID     string
+	DeviceName *string
+
+	CallbackURL *string
+	Email       *string
+}
@@ -0,0 +1,13 @@
+package alerts
+
+import "errors"
+
+var (
+	ErrNotConfigured = errors.New("offline alerts are not configured")
+)
+
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
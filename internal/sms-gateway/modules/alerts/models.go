@@ -0,0 +1,110 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/users"
+	"gorm.io/gorm"
+)
+
+type settingsModel struct {
+	models.TimedModel
+
+	UserID       string  `gorm:"primaryKey;not null;type:varchar(32)"`
+	OfflineAfter uint32  `gorm:"not null;type:INT UNSIGNED;column:offline_after_seconds"`
+	CallbackURL  *string `gorm:"type:varchar(2048)"`
+	Email        *string `gorm:"type:varchar(254)"`
+
+	User users.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func newSettingsModel(userID string, settings Settings) *settingsModel {
+	//nolint:exhaustruct // partial constructor
+	return &settingsModel{
+		UserID:       userID,
+		OfflineAfter: uint32(settings.OfflineAfter / time.Second), //nolint:gosec // validated
+		CallbackURL:  settings.CallbackURL,
+		Email:        settings.Email,
+	}
+}
+
+func (*settingsModel) TableName() string {
+	return "device_alert_settings"
+}
+
+func (m *settingsModel) toDomain() Settings {
+	return Settings{
+		OfflineAfter: time.Duration(m.OfflineAfter) * time.Second,
+		CallbackURL:  m.CallbackURL,
+		Email:        m.Email,
+	}
+}
+
+type incidentModel struct {
+	ID       uint64 `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	UserID   string `gorm:"not null;type:varchar(32);index:idx_device_incidents_user_id"`
+	DeviceID string `gorm:"not null;type:char(21);index:idx_device_incidents_device_id_resolved_at,priority:1"`
+
+	LastSeenAt time.Time  `gorm:"not null;type:datetime(3)"`
+	StartedAt  time.Time  `gorm:"not null;type:datetime(3)"`
+	ReturnedAt *time.Time `gorm:"type:datetime(3)"`
+	ResolvedAt *time.Time `gorm:"type:datetime(3);index:idx_device_incidents_device_id_resolved_at,priority:2"`
+	Flaps      uint32     `gorm:"not null;type:INT UNSIGNED;default:0"`
+
+	AlertSentAt    *time.Time `gorm:"type:datetime(3)"`
+	RecoverySentAt *time.Time `gorm:"type:datetime(3)"`
+
+	User   users.User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Device devices.DeviceModel `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+}
+
+func (*incidentModel) TableName() string {
+	return "device_incidents"
+}
+
+func (m *incidentModel) toDomain() Incident {
+	return Incident{
+		ID:         m.ID,
+		DeviceID:   m.DeviceID,
+		LastSeenAt: m.LastSeenAt,
+		StartedAt:  m.StartedAt,
+		ReturnedAt: m.ReturnedAt,
+		ResolvedAt: m.ResolvedAt,
+		Flaps:      int(m.Flaps),
+	}
+}
+
+// notificationModel is an incident joined with the device and the alert settings.
+type notificationModel struct {
+	incidentModel
+
+	DeviceName  *string
+	CallbackURL *string
+	Email       *string
+}
+
+func (m *notificationModel) toDomain(kind NotificationKind) Notification {
+	return Notification{
+		Kind:     kind,
+		Incident: m.incidentModel.toDomain(),
+
+		UserID:     m.UserID,
+		DeviceName: m.DeviceName,
+
+		CallbackURL: m.CallbackURL,
+		Email:       m.Email,
+	}
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(new(settingsModel)); err != nil {
+		return fmt.Errorf("device_alert_settings migration failed: %w", err)
+	}
+	if err := db.AutoMigrate(new(incidentModel)); err != nil {
+		return fmt.Errorf("device_incidents migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// offlineCondition is true for the devices not seen for longer than the threshold of their user.
+const offlineCondition = "d.last_seen < DATE_SUB(?, INTERVAL s.offline_after_seconds SECOND)"
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) getSettings(ctx context.Context, userID string) (*settingsModel, error) {
+	settings := new(settingsModel)
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Take(settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotConfigured
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (r *Repository) replaceSettings(ctx context.Context, settings *settingsModel) error {
+	if err := r.db.WithContext(ctx).Omit("User").Save(settings).Error; err != nil {
+		return fmt.Errorf("failed to replace alert settings: %w", err)
+	}
+
+	return nil
+}
+
+// deleteSettings disables the alerts of the user. Open incidents are resolved
+// without notification.
+func (r *Repository) deleteSettings(ctx context.Context, userID string, now time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ?", userID).Delete(new(settingsModel))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotConfigured
+		}
+
+		return tx.Model(new(incidentModel)).
+			Where("user_id = ? AND resolved_at IS NULL", userID).
+			Updates(map[string]any{"resolved_at": now, "recovery_sent_at": now}).Error
+	})
+	if errors.Is(err, ErrNotConfigured) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete alert settings: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) selectIncidents(
+	ctx context.Context,
+	userID string,
+	filter IncidentsFilter,
+) ([]incidentModel, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if filter.DeviceID != "" {
+		query = query.Where("device_id = ?", filter.DeviceID)
+	}
+	if filter.Open != nil {
+		query = query.Where(lo.Ternary(*filter.Open, "resolved_at IS NULL", "resolved_at IS NOT NULL"))
+	}
+
+	incidents := []incidentModel{}
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&incidents).Error; err != nil {
+		return nil, fmt.Errorf("failed to select incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// Detect updates the incidents according to the last seen time of the devices:
+//   - an incident is opened for an offline device without one;
+//   - an incident is marked as returned when its device checks in again;
+//   - a returned incident is reopened when its device goes offline again;
+//   - a returned incident is resolved when its device stays online for flapWindow.
+//
+// It returns the number of opened and resolved incidents.
+func (r *Repository) Detect(ctx context.Context, now time.Time, flapWindow time.Duration) (int64, int64, error) {
+	var opened, resolved int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(
+			"INSERT INTO device_incidents (user_id, device_id, last_seen_at, started_at, flaps) "+
+				"SELECT d.user_id, d.id, d.last_seen, ?, 0 FROM devices d "+
+				"JOIN device_alert_settings s ON s.user_id = d.user_id "+
+				"WHERE d.deleted_at IS NULL AND "+offlineCondition+" "+
+				"AND NOT EXISTS (SELECT 1 FROM device_incidents i WHERE i.device_id = d.id AND i.resolved_at IS NULL)",
+			now, now,
+		)
+		if res.Error != nil {
+			return res.Error
+		}
+		opened = res.RowsAffected
+
+		if err := tx.Exec(
+			"UPDATE device_incidents i JOIN devices d ON d.id = i.device_id " +
+				"SET i.returned_at = d.last_seen " +
+				"WHERE i.resolved_at IS NULL AND i.returned_at IS NULL AND d.last_seen > i.last_seen_at",
+		).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(
+			"UPDATE device_incidents i JOIN devices d ON d.id = i.device_id "+
+				"JOIN device_alert_settings s ON s.user_id = i.user_id "+
+				"SET i.returned_at = NULL, i.last_seen_at = d.last_seen, i.flaps = i.flaps + 1 "+
+				"WHERE i.resolved_at IS NULL AND i.returned_at IS NOT NULL AND "+offlineCondition,
+			now,
+		).Error; err != nil {
+			return err
+		}
+
+		res = tx.Model(new(incidentModel)).
+			Where("resolved_at IS NULL AND returned_at IS NOT NULL AND returned_at < ?", now.Add(-flapWindow)).
+			Update("resolved_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		resolved = res.RowsAffected
+
+		// the deleted devices won't return
+		return tx.Exec(
+			"UPDATE device_incidents i JOIN devices d ON d.id = i.device_id "+
+				"SET i.resolved_at = ?, i.recovery_sent_at = ? "+
+				"WHERE i.resolved_at IS NULL AND d.deleted_at IS NOT NULL",
+			now, now,
+		).Error
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to detect offline devices: %w", err)
+	}
+
+	return opened, resolved, nil
+}
+
+// SelectNotifications returns the notifications not sent yet for the incidents
+// updated since the given time. An offline alert is not sent for an already
+// resolved incident, a recovery notification only follows a sent alert.
+func (r *Repository) SelectNotifications(ctx context.Context, since time.Time) ([]Notification, error) {
+	query := func(where string) ([]notificationModel, error) {
+		items := []notificationModel{}
+		err := r.db.WithContext(ctx).
+			Table("device_incidents i").
+			Select("i.*, d.name AS device_name, s.callback_url, s.email").
+			Joins("JOIN devices d ON d.id = i.device_id").
+			Joins("JOIN device_alert_settings s ON s.user_id = i.user_id").
+			Where(where, since).
+			Order("i.id").
+			Find(&items).Error
+		return items, err
+	}
+
+	alerts, err := query("i.alert_sent_at IS NULL AND i.resolved_at IS NULL AND i.started_at >= ?")
+	if err != nil {
+		return nil, fmt.Errorf("failed to select offline alerts: %w", err)
+	}
+
+	recoveries, err := query(
+		"i.alert_sent_at IS NOT NULL AND i.recovery_sent_at IS NULL AND i.resolved_at >= ?",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select recovery notifications: %w", err)
+	}
+
+	notifications := make([]Notification, 0, len(alerts)+len(recoveries))
+	for _, item := range alerts {
+		notifications = append(notifications, item.toDomain(NotificationOffline))
+	}
+	for _, item := range recoveries {
+		notifications = append(notifications, item.toDomain(NotificationRecovered))
+	}
+
+	return notifications, nil
+}
+
+// MarkSent records that the notification was delivered.
+func (r *Repository) MarkSent(ctx context.Context, notification Notification, sentAt time.Time) error {
+	column := lo.Ternary(notification.Kind == NotificationOffline, "alert_sent_at", "recovery_sent_at")
+	if err := r.db.WithContext(ctx).
+		Model(new(incidentModel)).
+		Where("id = ?", notification.Incident.ID).
+		Update(column, sentAt).Error; err != nil {
+		return fmt.Errorf("failed to mark notification as sent: %w", err)
+	}
+
+	return nil
+}
+
+// Cleanup deletes the incidents resolved before the given time.
+func (r *Repository) Cleanup(ctx context.Context, until time.Time) (int64, error) {
+	res := r.db.
+		WithContext(ctx).
+		Where("resolved_at < ?", until).
+		Delete(new(incidentModel))
+
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,78 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+const (
+	minOfflineAfter = 2 * time.Minute
+	maxOfflineAfter = 7 * 24 * time.Hour
+
+	defaultIncidentsLimit = 50
+	maxIncidentsLimit     = 500
+)
+
+type Service struct {
+	alerts *Repository
+}
+
+func NewService(alerts *Repository) *Service {
+	return &Service{
+		alerts: alerts,
+	}
+}
+
+// GetSettings returns the offline alert settings of the user or ErrNotConfigured.
+func (s *Service) GetSettings(ctx context.Context, userID string) (*Settings, error) {
+	settings, err := s.alerts.getSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.ToPtr(settings.toDomain()), nil
+}
+
+// ReplaceSettings enables the offline alerts of the user.
+func (s *Service) ReplaceSettings(ctx context.Context, userID string, settings Settings) (*Settings, error) {
+	if settings.OfflineAfter < minOfflineAfter || settings.OfflineAfter > maxOfflineAfter {
+		return nil, ValidationError(
+			fmt.Sprintf("offline threshold must be between %s and %s", minOfflineAfter, maxOfflineAfter),
+		)
+	}
+	if lo.FromPtr(settings.CallbackURL) == "" && lo.FromPtr(settings.Email) == "" {
+		return nil, ValidationError("callback URL or email is required")
+	}
+
+	settings.CallbackURL = lo.EmptyableToPtr(lo.FromPtr(settings.CallbackURL))
+	settings.Email = lo.EmptyableToPtr(lo.FromPtr(settings.Email))
+
+	if err := s.alerts.replaceSettings(ctx, newSettingsModel(userID, settings)); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// DeleteSettings disables the offline alerts of the user.
+func (s *Service) DeleteSettings(ctx context.Context, userID string) error {
+	return s.alerts.deleteSettings(ctx, userID, time.Now())
+}
+
+// SelectIncidents returns the offline incidents of the user's devices, newest first.
+func (s *Service) SelectIncidents(ctx context.Context, userID string, filter IncidentsFilter) ([]Incident, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultIncidentsLimit
+	}
+	filter.Limit = min(filter.Limit, maxIncidentsLimit)
+
+	incidents, err := s.alerts.selectIncidents(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(incidents, func(item incidentModel, _ int) Incident { return item.toDomain() }), nil
+}
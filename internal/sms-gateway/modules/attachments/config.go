@@ -0,0 +1,8 @@
+package attachments
+
+type Config struct {
+	// MaxSize is the maximum size of an uploaded file in bytes.
+	MaxSize int64
+	// AllowedTypes lists the accepted MIME types detected from the file content.
+	AllowedTypes []string
+}
@@ -0,0 +1,13 @@
+package attachments
+
+import "time"
+
+// Attachment describes an uploaded file. The content is kept in the blob store.
+type Attachment struct {
+	ID          string
+	UserID      string
+	Name        string
+	ContentType string
+	Size        int64
+	CreatedAt   time.Time
+}
@@ -0,0 +1,15 @@
+package attachments
+
+import "errors"
+
+var (
+	ErrNotFound        = errors.New("attachment not found")
+	ErrTooLarge        = errors.New("attachment is too large")
+	ErrUnsupportedType = errors.New("attachment type is not supported")
+)
+
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,44 @@
+package attachments
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/users"
+	"gorm.io/gorm"
+)
+
+// attachmentModel is referenced by MMS messages through the `message_attachments` table.
+type attachmentModel struct {
+	ID          string    `gorm:"primaryKey;type:char(21)"`
+	UserID      string    `gorm:"not null;type:varchar(32);index:idx_attachments_user_id"`
+	Name        string    `gorm:"not null;type:varchar(255)"`
+	ContentType string    `gorm:"not null;type:varchar(128)"`
+	Size        int64     `gorm:"not null;type:BIGINT UNSIGNED"`
+	StorageKey  string    `gorm:"not null;type:varchar(255)"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime:false;index:idx_attachments_created_at"`
+
+	User users.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (*attachmentModel) TableName() string {
+	return "attachments"
+}
+
+func (m *attachmentModel) toDomain() Attachment {
+	return Attachment{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Name:        m.Name,
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(new(attachmentModel)); err != nil {
+		return fmt.Errorf("attachments migration failed: %w", err)
+	}
+	return nil
+}
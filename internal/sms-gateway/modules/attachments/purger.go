@@ -0,0 +1,58 @@
+package attachments
+
+import (
+	"context"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/blobs"
+	"go.uber.org/zap"
+)
+
+const purgeBatchSize = 100
+
+// Purger removes the attachments no longer needed by messages together with their content.
+// It is used by the worker tasks.
+type Purger struct {
+	attachments *Repository
+	store       blobs.Store
+
+	logger *zap.Logger
+}
+
+func NewPurger(attachments *Repository, store blobs.Store, logger *zap.Logger) *Purger {
+	return &Purger{
+		attachments: attachments,
+		store:       store,
+
+		logger: logger,
+	}
+}
+
+// Purge removes the attachments uploaded before until that aren't referenced by a
+// message with content and returns their number. The rows are deleted first, so
+// content that failed to be deleted is left orphaned rather than referenced.
+func (p *Purger) Purge(ctx context.Context, until time.Time) (int64, error) {
+	var total int64
+	for {
+		purged, err := p.attachments.purge(ctx, until, purgeBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += int64(len(purged))
+
+		for _, attachment := range purged {
+			if delErr := p.store.Delete(ctx, attachment.StorageKey); delErr != nil {
+				p.logger.Error(
+					"failed to delete attachment content",
+					zap.String("id", attachment.ID),
+					zap.String("key", attachment.StorageKey),
+					zap.Error(delErr),
+				)
+			}
+		}
+
+		if len(purged) < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// purgeableCondition selects the attachments not needed by any message: the
+// content of hashed messages is gone and deleted messages don't reference them.
+const purgeableCondition = "created_at < ? AND NOT EXISTS (" +
+	"SELECT 1 FROM message_attachments ma JOIN messages m ON m.id = ma.message_id " +
+	"WHERE ma.attachment_id = attachments.id AND m.is_hashed = 0 AND m.deleted_at IS NULL)"
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) insert(ctx context.Context, attachment *attachmentModel) error {
+	if err := r.db.WithContext(ctx).Omit("User").Create(attachment).Error; err != nil {
+		return fmt.Errorf("failed to insert attachment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) get(ctx context.Context, userID, id string) (*attachmentModel, error) {
+	attachment := new(attachmentModel)
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Take(attachment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (r *Repository) selectByIDs(ctx context.Context, userID string, ids []string) ([]attachmentModel, error) {
+	attachments := []attachmentModel{}
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to select attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// getForDevice returns the attachment if it is sent by a message of the device
+// that still has its content.
+func (r *Repository) getForDevice(ctx context.Context, deviceID, id string) (*attachmentModel, error) {
+	attachment := new(attachmentModel)
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Where(
+			"EXISTS (SELECT 1 FROM message_attachments ma JOIN messages m ON m.id = ma.message_id "+
+				"WHERE ma.attachment_id = attachments.id AND m.device_id = ? AND m.is_hashed = 0 AND m.deleted_at IS NULL)",
+			deviceID,
+		).
+		Take(attachment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// purge deletes up to limit attachments uploaded before until that are not needed
+// by any message and returns them. The rows are locked while deleting, so a
+// message being enqueued can't reference a purged attachment.
+func (r *Repository) purge(ctx context.Context, until time.Time, limit int) ([]attachmentModel, error) {
+	attachments := []attachmentModel{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Where(purgeableCondition, until).
+			Order("created_at").
+			Limit(limit).
+			Find(&attachments).Error; err != nil {
+			return err
+		}
+		if len(attachments) == 0 {
+			return nil
+		}
+
+		ids := lo.Map(attachments, func(item attachmentModel, _ int) string { return item.ID })
+		return tx.Where("id IN ?", ids).Delete(new(attachmentModel)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge attachments: %w", err)
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,195 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/blobs"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	pkgblobs "github.com/android-sms-gateway/server/pkg/blobs"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+const (
+	maxNameLength = 255
+	defaultName   = "attachment"
+
+	// sniffLength is the number of bytes used to detect the content type.
+	sniffLength = 512
+)
+
+type Service struct {
+	config Config
+
+	attachments *Repository
+	store       blobs.Store
+
+	idgen  db.IDGen
+	logger *zap.Logger
+}
+
+func NewService(
+	config Config,
+	attachments *Repository,
+	store blobs.Store,
+	idgen db.IDGen,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		config: config,
+
+		attachments: attachments,
+		store:       store,
+
+		idgen:  idgen,
+		logger: logger,
+	}
+}
+
+// Upload stores the file of the given size. The content type is detected from the
+// content rather than trusted from the client and must be one of the allowed types.
+func (s *Service) Upload(ctx context.Context, userID, name string, r io.Reader, size int64) (*Attachment, error) {
+	if size <= 0 {
+		return nil, ValidationError("file is empty")
+	}
+	if s.config.MaxSize > 0 && size > s.config.MaxSize {
+		return nil, fmt.Errorf("%w: max %d bytes", ErrTooLarge, s.config.MaxSize)
+	}
+
+	head := make([]byte, min(size, sniffLength))
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	contentType, err := s.detectContentType(head)
+	if err != nil {
+		return nil, err
+	}
+
+	id := s.idgen()
+	//nolint:exhaustruct // User is not inserted
+	attachment := &attachmentModel{
+		ID:          id,
+		UserID:      userID,
+		Name:        prepareName(name),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  path.Join("attachments", userID, id),
+		CreatedAt:   time.Now(),
+	}
+
+	if putErr := s.store.Put(
+		ctx,
+		attachment.StorageKey,
+		io.MultiReader(bytes.NewReader(head), r),
+		size,
+		contentType,
+	); putErr != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", putErr)
+	}
+
+	if insErr := s.attachments.insert(ctx, attachment); insErr != nil {
+		if delErr := s.store.Delete(context.Background(), attachment.StorageKey); delErr != nil {
+			s.logger.Error("failed to delete attachment content", zap.String("id", id), zap.Error(delErr))
+		}
+		return nil, insErr
+	}
+
+	return lo.ToPtr(attachment.toDomain()), nil
+}
+
+func (s *Service) Get(ctx context.Context, userID, id string) (*Attachment, error) {
+	attachment, err := s.attachments.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.ToPtr(attachment.toDomain()), nil
+}
+
+// Resolve returns the user's attachments in the order of IDs. A missing attachment is a validation error.
+func (s *Service) Resolve(ctx context.Context, userID string, ids []string) ([]Attachment, error) {
+	ids = lo.Uniq(ids)
+	models, err := s.attachments.selectByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := lo.KeyBy(models, func(item attachmentModel) string { return item.ID })
+	attachments := make([]Attachment, 0, len(ids))
+	for _, id := range ids {
+		attachment, ok := byID[id]
+		if !ok {
+			return nil, ValidationError(fmt.Sprintf("attachment %q not found", id))
+		}
+		attachments = append(attachments, attachment.toDomain())
+	}
+
+	return attachments, nil
+}
+
+// Open returns the attachment content for the device. Only the attachments of
+// the device's messages are available, and only until the messages are hashed.
+func (s *Service) Open(ctx context.Context, deviceID, id string) (*Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachments.getForDevice(ctx, deviceID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, pkgblobs.ErrNotFound) {
+		s.logger.Error("attachment content is missing", zap.String("id", id))
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment content: %w", err)
+	}
+
+	return lo.ToPtr(attachment.toDomain()), content, nil
+}
+
+func (s *Service) detectContentType(head []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "", fmt.Errorf("failed to detect content type: %w", err)
+	}
+
+	if !slices.Contains(s.config.AllowedTypes, mediaType) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, mediaType)
+	}
+
+	return mediaType, nil
+}
+
+// prepareName keeps the base name of the file without control characters.
+func prepareName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	name = strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return defaultName
+	}
+
+	for utf8.RuneCountInString(name) > maxNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	return name
+}
@@ -0,0 +1,75 @@
+//nolint:testpackage // name and content type helpers are unexported; in-package test required.
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPrepareName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain", input: "picture.png", expected: "picture.png"},
+		{name: "unix path", input: "/home/user/picture.png", expected: "picture.png"},
+		{name: "windows path", input: `C:\Users\user\picture.png`, expected: "picture.png"},
+		{name: "control characters", input: "pic\r\nture\".png", expected: "picture.png"},
+		{name: "empty", input: "", expected: defaultName},
+		{name: "spaces", input: "   ", expected: defaultName},
+		{name: "dot", input: ".", expected: defaultName},
+		{name: "root", input: "/", expected: defaultName},
+		{name: "long", input: strings.Repeat("я", maxNameLength+10), expected: strings.Repeat("я", maxNameLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, prepareName(tt.input))
+		})
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	//nolint:exhaustruct // only the config is used
+	s := &Service{config: Config{AllowedTypes: []string{"image/png", "text/plain"}}}
+
+	contentType, err := s.detectContentType([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+
+	contentType, err = s.detectContentType([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "text/plain", contentType)
+
+	_, err = s.detectContentType([]byte("%PDF-1.7"))
+	require.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestUploadValidation(t *testing.T) {
+	s := NewService(
+		Config{MaxSize: 16, AllowedTypes: []string{"image/png"}},
+		nil,
+		nil,
+		func() string { return "id" },
+		zap.NewNop(),
+	)
+
+	var validationErr ValidationError
+	_, err := s.Upload(context.Background(), "user", "empty.png", bytes.NewReader(nil), 0)
+	require.ErrorAs(t, err, &validationErr)
+
+	_, err = s.Upload(context.Background(), "user", "large.png", bytes.NewReader(make([]byte, 17)), 17)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = s.Upload(context.Background(), "user", "text.png", strings.NewReader("hello"), 5)
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = s.Upload(context.Background(), "user", "short.png", strings.NewReader("hel"), 5)
+	require.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package auth
+
+import "time"
+
+// RegistrationToken authorizes device registration in private mode until it expires.
+type RegistrationToken struct {
+	ID        string
+	Name      string
+	Token     string // The token itself, only known right after creation
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// IsExpired reports whether the token no longer authorizes registration.
+func (t RegistrationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// RegistrationTokenInput describes a new registration token.
+type RegistrationTokenInput struct {
+	Name      string
+	ExpiresAt time.Time
+}
@@ -4,4 +4,12 @@ import "errors"
 
 var (
 	ErrAuthorizationFailed = errors.New("authorization failed")
+	ErrDeviceLimitReached  = errors.New("device limit reached")
+	ErrTokenNotFound       = errors.New("registration token not found")
 )
+
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type registrationTokenModel struct {
+	models.TimedModel
+
+	ID        string    `gorm:"primaryKey;type:char(21)"`
+	Name      string    `gorm:"not null;type:varchar(128)"`
+	TokenHash string    `gorm:"not null;type:char(64);uniqueIndex:idx_registration_tokens_token_hash"`
+	ExpiresAt time.Time `gorm:"not null;type:datetime(3);index:idx_registration_tokens_expires_at"`
+}
+
+func newRegistrationTokenModel(id, token string, input RegistrationTokenInput) *registrationTokenModel {
+	//nolint:exhaustruct // timestamps are set by the database
+	return &registrationTokenModel{
+		ID:        id,
+		Name:      input.Name,
+		TokenHash: hashToken(token),
+		ExpiresAt: input.ExpiresAt,
+	}
+}
+
+func (registrationTokenModel) TableName() string {
+	return "registration_tokens"
+}
+
+func (m registrationTokenModel) toDomain() RegistrationToken {
+	return RegistrationToken{
+		ID:        m.ID,
+		Name:      m.Name,
+		Token:     "",
+		ExpiresAt: m.ExpiresAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// hashToken keeps the tokens out of the database, only their hashes are stored.
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(new(registrationTokenModel)); err != nil {
+		return fmt.Errorf("registration tokens migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+//nolint:testpackage // the models are unexported; in-package test required.
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrationTokenModel(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	model := newRegistrationTokenModel("id", "secret", RegistrationTokenInput{Name: "phones", ExpiresAt: expiresAt})
+
+	// only the hash of the token is stored
+	require.NotContains(t, model.TokenHash, "secret")
+	require.Len(t, model.TokenHash, 64)
+	require.Equal(t, hashToken("secret"), model.TokenHash)
+	require.NotEqual(t, hashToken("other"), model.TokenHash)
+
+	token := model.toDomain()
+	require.Equal(t, "id", token.ID)
+	require.Equal(t, "phones", token.Name)
+	require.Empty(t, token.Token)
+
+	require.False(t, token.IsExpired(expiresAt.Add(-time.Millisecond)))
+	require.True(t, token.IsExpired(expiresAt))
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"github.com/capcom6/go-infra-fx/db"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -11,6 +12,12 @@ func Module() fx.Option {
 		fx.Decorate(func(log *zap.Logger) *zap.Logger {
 			return log.Named("auth")
 		}),
+		fx.Provide(newRepository, fx.Private),
 		fx.Provide(New),
 	)
 }
+
+//nolint:gochecknoinits //framework-specific
+func init() {
+	db.RegisterMigration(Migrate)
+}
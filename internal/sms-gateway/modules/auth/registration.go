@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/samber/lo"
+)
+
+const maxTokenNameLength = 128
+
+// CreateRegistrationToken creates a token authorizing device registration until
+// it expires. The token itself is returned only once, the database keeps its hash.
+func (s *Service) CreateRegistrationToken(
+	ctx context.Context,
+	input RegistrationTokenInput,
+) (*RegistrationToken, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, ValidationError("name is required")
+	}
+	if utf8.RuneCountInString(input.Name) > maxTokenNameLength {
+		return nil, ValidationError(fmt.Sprintf("name must be at most %d characters", maxTokenNameLength))
+	}
+	if !input.ExpiresAt.After(time.Now()) {
+		return nil, ValidationError("expiration must be in the future")
+	}
+
+	token := s.idGen()
+	model := newRegistrationTokenModel(s.idGen(), token, input)
+	if err := s.registration.insert(ctx, model); err != nil {
+		return nil, err
+	}
+
+	created, err := s.registration.get(ctx, model.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := created.toDomain()
+	result.Token = token
+
+	return &result, nil
+}
+
+// SelectRegistrationTokens returns all the registration tokens including the expired ones.
+func (s *Service) SelectRegistrationTokens(ctx context.Context) ([]RegistrationToken, error) {
+	items, err := s.registration.selectAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(items, func(m registrationTokenModel, _ int) RegistrationToken { return m.toDomain() }), nil
+}
+
+// DeleteRegistrationToken revokes the registration token. Devices registered
+// with it are not affected.
+func (s *Service) DeleteRegistrationToken(ctx context.Context, id string) error {
+	return s.registration.delete(ctx, id)
+}
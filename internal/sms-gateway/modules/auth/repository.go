@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) insert(ctx context.Context, token *registrationTokenModel) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("failed to insert registration token: %w", err)
+	}
+
+	return nil
+}
+
+// get returns the token by ID, the timestamps are filled by the database.
+func (r *repository) get(ctx context.Context, id string) (*registrationTokenModel, error) {
+	token := new(registrationTokenModel)
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *repository) selectAll(ctx context.Context) ([]registrationTokenModel, error) {
+	tokens := []registrationTokenModel{}
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to select registration tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// isValid reports whether the token with the hash exists and is not expired at now.
+func (r *repository) isValid(ctx context.Context, tokenHash string, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model((*registrationTokenModel)(nil)).
+		Where("token_hash = ? AND expires_at > ?", tokenHash, now).
+		Count(&count).
+		Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check registration token: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *repository) delete(ctx context.Context, id string) error {
+	res := r.db.WithContext(ctx).Where("id = ?", id).Delete(new(registrationTokenModel))
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete registration token: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
@@ -3,9 +3,12 @@ package auth
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/online"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/otp"
@@ -15,12 +18,19 @@ import (
 
 type Config struct {
 	Mode         Mode
-	PrivateToken string
+	PrivateToken string // Static registration token in private mode, empty to accept registration tokens only
+
+	RegistrationApproval bool     // New devices wait for an admin approval in private mode
+	MaxDevices           int      // Max devices per user, 0 for unlimited
+	Admins               []string // Users allowed to approve devices and manage registration tokens
 }
 
 type Service struct {
 	config Config
 
+	registration *repository
+	idGen        db.IDGen
+
 	usersSvc   *users.Service
 	otpSvc     *otp.Service
 	devicesSvc *devices.Service
@@ -31,6 +41,8 @@ type Service struct {
 
 func New(
 	config Config,
+	registration *repository,
+	idGen db.IDGen,
 	usersSvc *users.Service,
 	otpSvc *otp.Service,
 	devicesSvc *devices.Service,
@@ -40,6 +52,9 @@ func New(
 	return &Service{
 		config: config,
 
+		registration: registration,
+		idGen:        idGen,
+
 		usersSvc: usersSvc,
 
 		otpSvc:     otpSvc,
@@ -60,13 +75,22 @@ func (s *Service) GenerateUserCode(ctx context.Context, userID string) (*otp.Cod
 	return code, nil
 }
 
+// RegisterDevice registers a new device of the user. In private mode with
+// approval enabled the device is pending until an admin approves it.
 func (s *Service) RegisterDevice(
 	ctx context.Context,
 	userID string,
 	info devices.DeviceInfo,
 ) (*devices.Device, error) {
-	device, err := s.devicesSvc.Insert(ctx, userID, info)
+	status := devices.StatusActive
+	if !s.IsPublic() && s.config.RegistrationApproval {
+		status = devices.StatusPendingApproval
+	}
 
+	device, err := s.devicesSvc.Insert(ctx, userID, info, status, s.config.MaxDevices)
+	if errors.Is(err, devices.ErrLimitReached) {
+		return nil, fmt.Errorf("%w: max %d devices per user", ErrDeviceLimitReached, s.config.MaxDevices)
+	}
 	if err != nil {
 		return device, fmt.Errorf("failed to create device: %w", err)
 	}
@@ -78,12 +102,28 @@ func (s *Service) IsPublic() bool {
 	return s.config.Mode == ModePublic
 }
 
-func (s *Service) AuthorizeRegistration(token string) error {
+// IsAdmin reports whether the user may approve devices and manage registration tokens.
+func (s *Service) IsAdmin(userID string) bool {
+	return slices.Contains(s.config.Admins, userID)
+}
+
+// AuthorizeRegistration checks the token against the static private token and
+// the registration tokens not yet expired.
+func (s *Service) AuthorizeRegistration(ctx context.Context, token string) error {
 	if s.IsPublic() {
 		return nil
 	}
 
-	if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.PrivateToken)) == 1 {
+	if s.config.PrivateToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(s.config.PrivateToken)) == 1 {
+		return nil
+	}
+
+	valid, err := s.registration.isValid(ctx, hashToken(token), time.Now())
+	if err != nil {
+		return err
+	}
+	if valid {
 		return nil
 	}
 
@@ -0,0 +1,10 @@
+package campaigns
+
+import "time"
+
+type Config struct {
+	// ExpansionInterval is how often messages are generated, one interval ahead.
+	ExpansionInterval time.Duration
+	// ExpansionBatchSize limits the messages generated per transaction.
+	ExpansionBatchSize int
+}
@@ -0,0 +1,65 @@
+package campaigns
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+)
+
+type Status string
+
+const (
+	// StatusActive means the campaign is being expanded into messages.
+	StatusActive Status = "Active"
+	// StatusPaused means the expansion is suspended until the campaign is resumed.
+	StatusPaused Status = "Paused"
+	// StatusCompleted means a message has been generated for every recipient.
+	StatusCompleted Status = "Completed"
+	// StatusAborted means the expansion was stopped and pending messages were cancelled.
+	StatusAborted Status = "Aborted"
+)
+
+type CampaignInput struct {
+	Name string
+	Text string
+
+	PhoneNumbers []string
+	// DevicePool limits the devices used for sending. All user devices are used when empty.
+	DevicePool []string
+	// ThrottleWindow spreads the messages evenly over the given duration. Messages are sent as soon as possible when zero.
+	ThrottleWindow time.Duration
+}
+
+type Campaign struct {
+	ID     string
+	UserID string
+
+	Name           string
+	Text           string
+	DevicePool     []string
+	ThrottleWindow time.Duration
+
+	Status    Status
+	Total     int // Number of recipients
+	Generated int // Number of recipients with a generated message
+
+	StartedAt time.Time
+	PausedAt  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Recipient is a campaign recipient awaiting or linked to a generated message.
+type Recipient struct {
+	ID          uint64
+	Position    int
+	PhoneNumber string
+	MessageID   *string
+}
+
+// Stats aggregates the progress of a campaign.
+type Stats struct {
+	Total     int
+	Generated int
+	States    map[messages.ProcessingState]int64
+}
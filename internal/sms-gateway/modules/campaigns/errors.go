@@ -0,0 +1,14 @@
+package campaigns
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("campaign not found")
+	ErrInvalidStatus = errors.New("operation is not allowed in the current campaign status")
+)
+
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
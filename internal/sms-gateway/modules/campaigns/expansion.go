@@ -0,0 +1,247 @@
+package campaigns
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Run periodically generates the messages of the active campaigns. Messages pass
+// the same checks as the enqueued ones, so the generation runs in the API process.
+// The campaign is locked while its messages are generated, it is safe to run on
+// every replica.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.config.ExpansionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.expandActive(ctx)
+		}
+	}
+}
+
+func (s *Service) expandActive(ctx context.Context) {
+	active, err := s.campaigns.SelectActive(ctx)
+	if err != nil {
+		s.logger.Error("failed to select campaigns", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	for _, campaign := range active {
+		if expErr := s.expand(ctx, campaign, now); expErr != nil {
+			s.logger.Error("failed to expand campaign", zap.String("campaign_id", campaign.ID), zap.Error(expErr))
+		}
+	}
+}
+
+// expand generates messages for the recipients whose slots start before the next run.
+func (s *Service) expand(ctx context.Context, campaign Campaign, now time.Time) error {
+	pool, err := s.selectPool(ctx, campaign)
+	if err != nil {
+		return err
+	}
+	if len(pool) == 0 {
+		s.logger.Warn("no devices available for campaign", zap.String("campaign_id", campaign.ID))
+		return nil
+	}
+
+	until := dueUntil(campaign, now.Add(s.config.ExpansionInterval))
+	generated := 0
+loop:
+	for {
+		count, deviceIDs, genErr := s.generate(ctx, campaign, pool, until)
+		var (
+			rejectedErr   *messages.ContentRejectedError
+			validationErr messages.ValidationError
+		)
+		switch {
+		case errors.Is(genErr, ErrInvalidStatus):
+			// paused or aborted in the meantime
+			return nil
+		case errors.As(genErr, &rejectedErr), errors.As(genErr, &validationErr):
+			// the user's policies were changed after the campaign had been created
+			return s.pauseRejected(ctx, campaign, genErr)
+		case errors.Is(genErr, messages.ErrQueueLimitExceeded):
+			s.logger.Warn("campaign devices queues are full", zap.String("campaign_id", campaign.ID))
+			break loop
+		case genErr != nil:
+			return genErr
+		}
+
+		generated += count
+		s.notify(campaign.UserID, deviceIDs)
+
+		if count < s.config.ExpansionBatchSize {
+			break
+		}
+	}
+
+	if generated > 0 {
+		s.logger.Info("campaign messages generated", zap.String("campaign_id", campaign.ID), zap.Int("count", generated))
+	}
+
+	if until < campaign.Total {
+		return nil
+	}
+
+	completed, err := s.campaigns.Complete(ctx, campaign.ID)
+	if err != nil {
+		return err
+	}
+	if completed {
+		s.logger.Info("campaign completed", zap.String("campaign_id", campaign.ID))
+	}
+
+	return nil
+}
+
+// generate enqueues messages for the next batch of due recipients and returns
+// their number with the IDs of the devices used.
+func (s *Service) generate(
+	ctx context.Context,
+	campaign Campaign,
+	pool []devices.Device,
+	until int,
+) (int, []string, error) {
+	var deviceIDs []string
+	count := 0
+	release := func() {}
+
+	err := s.campaigns.Expand(
+		ctx,
+		campaign.ID,
+		until,
+		s.config.ExpansionBatchSize,
+		func(tx *gorm.DB, recipients []Recipient) (map[uint64]string, error) {
+			items := lo.Map(recipients, func(r Recipient, _ int) messages.CampaignMessage {
+				var scheduleAt *time.Time
+				if campaign.ThrottleWindow > 0 {
+					scheduleAt = lo.ToPtr(slotAt(campaign, r.Position))
+				}
+
+				return messages.CampaignMessage{
+					Device:      pool[r.Position%len(pool)],
+					PhoneNumber: r.PhoneNumber,
+					Text:        campaign.Text,
+					ScheduleAt:  scheduleAt,
+				}
+			})
+
+			ids, releaseClaims, err := s.messagesSvc.EnqueueCampaign(ctx, tx, campaign.UserID, campaign.ID, items)
+			if err != nil {
+				return nil, err
+			}
+			release = releaseClaims
+
+			links := make(map[uint64]string, len(ids))
+			for i, id := range ids {
+				links[recipients[i].ID] = id
+			}
+
+			count = len(recipients)
+			deviceIDs = lo.Uniq(lo.Map(items, func(m messages.CampaignMessage, _ int) string { return m.Device.ID }))
+
+			return links, nil
+		},
+	)
+	if err != nil {
+		// the recipients are retried, their claims would suppress them as duplicates
+		// of the messages rolled back
+		release()
+		return 0, nil, err
+	}
+
+	return count, deviceIDs, nil
+}
+
+// selectPool returns the campaign devices that still exist, are approved, not
+// paused and accept new messages in a stable order.
+func (s *Service) selectPool(ctx context.Context, campaign Campaign) ([]devices.Device, error) {
+	userDevices, err := s.devicesSvc.Select(
+		ctx,
+		campaign.UserID,
+		devices.WithStatus(devices.StatusActive),
+		devices.WithPaused(false),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select devices: %w", err)
+	}
+
+	if len(campaign.DevicePool) > 0 {
+		userDevices = lo.Filter(userDevices, func(d devices.Device, _ int) bool {
+			return slices.Contains(campaign.DevicePool, d.ID)
+		})
+	}
+
+	pool := make([]devices.Device, 0, len(userDevices))
+	for _, device := range userDevices {
+		if checkErr := s.messagesSvc.CheckQueue(ctx, device.ID); checkErr != nil {
+			if !errors.Is(checkErr, messages.ErrQueueLimitExceeded) {
+				return nil, fmt.Errorf("failed to check queue: %w", checkErr)
+			}
+			continue
+		}
+		pool = append(pool, device)
+	}
+	slices.SortFunc(pool, func(a, b devices.Device) int { return cmp.Compare(a.ID, b.ID) })
+
+	return pool, nil
+}
+
+// pauseRejected pauses the campaign whose messages can't be enqueued until the user fixes it.
+func (s *Service) pauseRejected(ctx context.Context, campaign Campaign, cause error) error {
+	if _, err := s.Pause(ctx, campaign.UserID, campaign.ID); err != nil && !errors.Is(err, ErrInvalidStatus) {
+		return fmt.Errorf("failed to pause rejected campaign: %w", err)
+	}
+
+	s.logger.Warn("campaign paused", zap.String("campaign_id", campaign.ID), zap.Error(cause))
+
+	return nil
+}
+
+func (s *Service) notify(userID string, deviceIDs []string) {
+	for _, deviceID := range deviceIDs {
+		if err := s.eventsSvc.Notify(userID, &deviceID, events.NewMessageEnqueuedEvent()); err != nil {
+			s.logger.Warn("failed to notify device", zap.String("device_id", deviceID), zap.Error(err))
+		}
+	}
+}
+
+// slotAt returns the send time of the recipient at the given position.
+// Recipients are spread evenly over the throttle window.
+func slotAt(campaign Campaign, position int) time.Time {
+	offset := time.Duration(float64(campaign.ThrottleWindow) * float64(position) / float64(campaign.Total))
+	return campaign.StartedAt.Add(offset)
+}
+
+// dueUntil returns the number of leading positions whose slots start no later than the given time.
+func dueUntil(campaign Campaign, at time.Time) int {
+	if campaign.ThrottleWindow <= 0 {
+		return campaign.Total
+	}
+
+	elapsed := at.Sub(campaign.StartedAt)
+	if elapsed < 0 {
+		return 0
+	}
+
+	due := int(math.Floor(float64(campaign.Total)*float64(elapsed)/float64(campaign.ThrottleWindow))) + 1
+
+	return min(due, campaign.Total)
+}
@@ -0,0 +1,51 @@
+//nolint:testpackage // scheduling helpers are unexported; in-package test required.
+package campaigns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueUntil(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	campaign := Campaign{
+		Total:          100,
+		ThrottleWindow: 100 * time.Minute,
+		StartedAt:      startedAt,
+	}
+
+	tests := []struct {
+		name     string
+		campaign Campaign
+		at       time.Time
+		want     int
+	}{
+		{"no throttling", Campaign{Total: 100, StartedAt: startedAt}, startedAt, 100},
+		{"not started", campaign, startedAt.Add(-time.Minute), 0},
+		{"first slot", campaign, startedAt, 1},
+		{"in the middle", campaign, startedAt.Add(10*time.Minute + time.Second), 11},
+		{"after window", campaign, startedAt.Add(200 * time.Minute), 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, dueUntil(tt.campaign, tt.at))
+		})
+	}
+}
+
+func TestSlotAt(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	campaign := Campaign{
+		Total:          4,
+		ThrottleWindow: time.Hour,
+		StartedAt:      startedAt,
+	}
+
+	require.Equal(t, startedAt, slotAt(campaign, 0))
+	require.Equal(t, startedAt.Add(45*time.Minute), slotAt(campaign, 3))
+	// the slot of a position is due exactly when dueUntil includes it
+	require.Equal(t, 4, dueUntil(campaign, slotAt(campaign, 3)))
+}
@@ -0,0 +1,105 @@
+package campaigns
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/users"
+	"github.com/samber/lo"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type campaignModel struct {
+	models.TimedModel
+
+	ID     string `gorm:"primaryKey;type:char(21)"`
+	UserID string `gorm:"not null;type:varchar(32);index:idx_campaigns_user"`
+
+	Name           string                      `gorm:"not null;type:varchar(128)"`
+	Text           string                      `gorm:"not null;type:text"`
+	DevicePool     datatypes.JSONSlice[string] `gorm:"serializer:json;type:json"`
+	ThrottleWindow uint32                      `gorm:"not null;type:int unsigned;default:0"` // seconds
+
+	Status    Status     `gorm:"not null;type:enum('Active','Paused','Completed','Aborted');default:Active;index:idx_campaigns_status"`
+	Total     int        `gorm:"not null;type:int unsigned"`
+	Generated int        `gorm:"not null;type:int unsigned;default:0"`
+	StartedAt time.Time  `gorm:"not null;type:datetime(3)"`
+	PausedAt  *time.Time `gorm:"type:datetime(3)"`
+
+	User       users.User               `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Recipients []campaignRecipientModel `gorm:"foreignKey:CampaignID;constraint:OnDelete:CASCADE"`
+}
+
+func newCampaignModel(id, userID string, input CampaignInput, startedAt time.Time) *campaignModel {
+	//nolint:exhaustruct // partial constructor
+	return &campaignModel{
+		ID:             id,
+		UserID:         userID,
+		Name:           input.Name,
+		Text:           input.Text,
+		DevicePool:     datatypes.NewJSONSlice(input.DevicePool),
+		ThrottleWindow: uint32(input.ThrottleWindow / time.Second), //nolint:gosec // validated by caller
+		Status:         StatusActive,
+		Total:          len(input.PhoneNumbers),
+		StartedAt:      startedAt,
+		Recipients: lo.Map(input.PhoneNumbers, func(phone string, i int) campaignRecipientModel {
+			//nolint:exhaustruct // partial constructor
+			return campaignRecipientModel{
+				Position:    i,
+				PhoneNumber: phone,
+			}
+		}),
+	}
+}
+
+func (*campaignModel) TableName() string {
+	return "campaigns"
+}
+
+func (m *campaignModel) toDomain() Campaign {
+	return Campaign{
+		ID:             m.ID,
+		UserID:         m.UserID,
+		Name:           m.Name,
+		Text:           m.Text,
+		DevicePool:     m.DevicePool,
+		ThrottleWindow: time.Duration(m.ThrottleWindow) * time.Second,
+		Status:         m.Status,
+		Total:          m.Total,
+		Generated:      m.Generated,
+		StartedAt:      m.StartedAt,
+		PausedAt:       m.PausedAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+type campaignRecipientModel struct {
+	ID          uint64  `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	CampaignID  string  `gorm:"not null;type:char(21);uniqueIndex:unq_campaign_recipients_campaign_position,priority:1"`
+	Position    int     `gorm:"not null;type:int unsigned;uniqueIndex:unq_campaign_recipients_campaign_position,priority:2"`
+	PhoneNumber string  `gorm:"not null;type:varchar(128)"`
+	MessageID   *string `gorm:"type:varchar(36)"`
+}
+
+func (*campaignRecipientModel) TableName() string {
+	return "campaign_recipients"
+}
+
+func (m *campaignRecipientModel) toDomain() Recipient {
+	return Recipient{
+		ID:          m.ID,
+		Position:    m.Position,
+		PhoneNumber: m.PhoneNumber,
+		MessageID:   m.MessageID,
+	}
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(new(campaignModel), new(campaignRecipientModel)); err != nil {
+		return fmt.Errorf("campaigns migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package campaigns
+
+import (
+	"github.com/capcom6/go-infra-fx/db"
+	"github.com/go-core-fx/fxutil"
+	"github.com/go-core-fx/logger"
+	"go.uber.org/fx"
+)
+
+func Module() fx.Option {
+	return fx.Module(
+		"campaigns",
+		logger.WithNamedLogger("campaigns"),
+		fx.Provide(NewRepository, fx.Private),
+		fx.Provide(NewService),
+		fx.Invoke(
+			fxutil.RegisterRunnable[*Service](),
+		),
+	)
+}
+
+//nolint:gochecknoinits //framework-specific
+func init() {
+	db.RegisterMigration(Migrate)
+}
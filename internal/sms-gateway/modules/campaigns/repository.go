@@ -0,0 +1,206 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const recipientsBatchSize = 1000
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) insert(ctx context.Context, campaign *campaignModel) error {
+	if err := r.db.WithContext(ctx).
+		Session(&gorm.Session{CreateBatchSize: recipientsBatchSize}).
+		Omit("User").
+		Create(campaign).Error; err != nil {
+		return fmt.Errorf("failed to insert campaign: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) get(ctx context.Context, userID, id string) (*campaignModel, error) {
+	campaign := new(campaignModel)
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Take(campaign).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+func (r *Repository) list(ctx context.Context, userID string, limit, offset int) ([]campaignModel, int64, error) {
+	query := r.db.WithContext(ctx).Model((*campaignModel)(nil)).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+
+	campaigns := []campaignModel{}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&campaigns).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to select campaigns: %w", err)
+	}
+
+	return campaigns, total, nil
+}
+
+// updateStatus applies the updates if the campaign is in one of the expected statuses.
+func (r *Repository) updateStatus(
+	ctx context.Context,
+	userID, id string,
+	from []Status,
+	updates map[string]any,
+) error {
+	res := r.db.WithContext(ctx).
+		Model((*campaignModel)(nil)).
+		Where("user_id = ? AND id = ? AND status IN ?", userID, id, from).
+		Updates(updates)
+	if res.Error != nil {
+		return fmt.Errorf("failed to update campaign: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		if _, err := r.get(ctx, userID, id); err != nil {
+			return err
+		}
+		return ErrInvalidStatus
+	}
+
+	return nil
+}
+
+// recipients returns the recipients after the given position ordered by position.
+func (r *Repository) recipients(ctx context.Context, campaignID string, afterPosition, limit int) ([]Recipient, error) {
+	models := []campaignRecipientModel{}
+	if err := r.db.WithContext(ctx).
+		Where("campaign_id = ? AND position > ?", campaignID, afterPosition).
+		Order("position").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to select recipients: %w", err)
+	}
+
+	return lo.Map(models, func(m campaignRecipientModel, _ int) Recipient { return m.toDomain() }), nil
+}
+
+// SelectActive returns all campaigns that are being expanded.
+func (r *Repository) SelectActive(ctx context.Context) ([]Campaign, error) {
+	models := []campaignModel{}
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", StatusActive).
+		Order("started_at").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to select active campaigns: %w", err)
+	}
+
+	return lo.Map(models, func(m campaignModel, _ int) Campaign { return m.toDomain() }), nil
+}
+
+// Expand selects up to limit recipients without a generated message whose position
+// is less than untilPosition and calls generate for them in a transaction while the
+// campaign is locked. The returned message IDs, keyed by recipient ID, are linked to
+// the recipients. generate is not called when there are no such recipients.
+// It returns ErrInvalidStatus if the campaign is no longer active.
+func (r *Repository) Expand(
+	ctx context.Context,
+	campaignID string,
+	untilPosition, limit int,
+	generate func(tx *gorm.DB, recipients []Recipient) (map[uint64]string, error),
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		campaign := new(campaignModel)
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			Where("id = ?", campaignID).
+			Take(campaign).Error; err != nil {
+			return err
+		}
+		if campaign.Status != StatusActive {
+			return ErrInvalidStatus
+		}
+
+		// selected under the lock, so concurrent replicas don't generate the same recipients
+		models := []campaignRecipientModel{}
+		if err := tx.
+			Where("campaign_id = ? AND message_id IS NULL AND position < ?", campaignID, untilPosition).
+			Order("position").
+			Limit(limit).
+			Find(&models).Error; err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+
+		messageIDs, err := generate(tx, lo.Map(models, func(m campaignRecipientModel, _ int) Recipient { return m.toDomain() }))
+		if err != nil {
+			return err
+		}
+
+		for recipientID, messageID := range messageIDs {
+			if err := tx.Model((*campaignRecipientModel)(nil)).
+				Where("id = ? AND campaign_id = ?", recipientID, campaignID).
+				Update("message_id", messageID).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(campaign).
+			UpdateColumn("generated", gorm.Expr("generated + ?", len(messageIDs))).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidStatus) {
+			return err
+		}
+		return fmt.Errorf("failed to expand campaign: %w", err)
+	}
+
+	return nil
+}
+
+// Complete marks an active campaign as completed once all its recipients are expanded.
+func (r *Repository) Complete(ctx context.Context, campaignID string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model((*campaignModel)(nil)).
+		Where("id = ? AND status = ? AND generated >= total", campaignID, StatusActive).
+		Update("status", StatusCompleted)
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to complete campaign: %w", res.Error)
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// Cleanup deletes the completed and aborted campaigns last updated before the given time.
+// Their recipients are deleted with them.
+func (r *Repository) Cleanup(ctx context.Context, until time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("status IN ? AND updated_at < ?", []Status{StatusCompleted, StatusAborted}, until).
+		Delete(new(campaignModel))
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to delete campaigns: %w", res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
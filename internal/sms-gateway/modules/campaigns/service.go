@@ -0,0 +1,313 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+const (
+	maxRecipients     = 100_000
+	maxThrottleWindow = 30 * 24 * time.Hour
+
+	exportBatchSize = 500
+)
+
+type Service struct {
+	config Config
+
+	campaigns *Repository
+
+	messagesSvc *messages.Service
+	devicesSvc  *devices.Service
+	eventsSvc   *events.Service
+
+	idgen  db.IDGen
+	logger *zap.Logger
+}
+
+func NewService(
+	config Config,
+	campaigns *Repository,
+	messagesSvc *messages.Service,
+	devicesSvc *devices.Service,
+	eventsSvc *events.Service,
+	idgen db.IDGen,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		config: config,
+
+		campaigns: campaigns,
+
+		messagesSvc: messagesSvc,
+		devicesSvc:  devicesSvc,
+		eventsSvc:   eventsSvc,
+
+		idgen:  idgen,
+		logger: logger,
+	}
+}
+
+// Create validates the campaign and stores it with its recipients.
+// The phone numbers are normalized and the text is checked against the content
+// policies for all the recipients at once. Messages are generated later by Run.
+func (s *Service) Create(ctx context.Context, userID string, input CampaignInput) (*Campaign, error) {
+	if len(input.PhoneNumbers) == 0 {
+		return nil, ValidationError("at least one phone number is required")
+	}
+	if len(input.PhoneNumbers) > maxRecipients {
+		return nil, ValidationError(fmt.Sprintf("too many phone numbers, max %d", maxRecipients))
+	}
+	if input.ThrottleWindow < 0 || input.ThrottleWindow > maxThrottleWindow {
+		return nil, ValidationError(fmt.Sprintf("throttle window must be between 0 and %s", maxThrottleWindow))
+	}
+
+	if err := s.messagesSvc.ValidateCampaign(userID, input.Text, input.PhoneNumbers); err != nil {
+		return nil, err
+	}
+
+	input.DevicePool = lo.Uniq(input.DevicePool)
+	if len(input.DevicePool) > 0 {
+		userDevices, err := s.devicesSvc.Select(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select devices: %w", err)
+		}
+
+		known := lo.SliceToMap(userDevices, func(d devices.Device) (string, struct{}) { return d.ID, struct{}{} })
+		for _, id := range input.DevicePool {
+			if _, ok := known[id]; !ok {
+				return nil, ValidationError(fmt.Sprintf("device %q not found", id))
+			}
+		}
+	}
+
+	model := newCampaignModel(s.idgen(), userID, input, time.Now())
+	if err := s.campaigns.insert(ctx, model); err != nil {
+		return nil, err
+	}
+
+	campaign := model.toDomain()
+	return &campaign, nil
+}
+
+func (s *Service) Get(ctx context.Context, userID, id string) (*Campaign, error) {
+	model, err := s.campaigns.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := model.toDomain()
+	return &campaign, nil
+}
+
+func (s *Service) List(ctx context.Context, userID string, limit, offset int) ([]Campaign, int64, error) {
+	models, total, err := s.campaigns.list(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return lo.Map(models, func(m campaignModel, _ int) Campaign { return m.toDomain() }), total, nil
+}
+
+// Pause suspends the generation of new messages. Already generated messages are not affected.
+func (s *Service) Pause(ctx context.Context, userID, id string) (*Campaign, error) {
+	if err := s.campaigns.updateStatus(
+		ctx,
+		userID,
+		id,
+		[]Status{StatusActive},
+		map[string]any{"status": StatusPaused, "paused_at": time.Now()},
+	); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, userID, id)
+}
+
+// Resume continues a paused campaign. The remaining schedule is shifted by the pause duration.
+func (s *Service) Resume(ctx context.Context, userID, id string) (*Campaign, error) {
+	campaign, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != StatusPaused {
+		return nil, ErrInvalidStatus
+	}
+
+	startedAt := campaign.StartedAt
+	if campaign.PausedAt != nil {
+		startedAt = startedAt.Add(time.Since(*campaign.PausedAt))
+	}
+
+	if updErr := s.campaigns.updateStatus(
+		ctx,
+		userID,
+		id,
+		[]Status{StatusPaused},
+		map[string]any{"status": StatusActive, "started_at": startedAt, "paused_at": nil},
+	); updErr != nil {
+		return nil, updErr
+	}
+
+	return s.Get(ctx, userID, id)
+}
+
+// Abort stops the campaign and cancels its pending messages in the background.
+// The campaign is returned to its previous status if the cancellation job is not accepted.
+func (s *Service) Abort(ctx context.Context, userID, id string) (*Campaign, error) {
+	campaign, err := s.campaigns.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status != StatusActive && campaign.Status != StatusPaused {
+		return nil, ErrInvalidStatus
+	}
+
+	// the generation is stopped before the cancellation job selects the messages
+	if updErr := s.campaigns.updateStatus(
+		ctx,
+		userID,
+		id,
+		[]Status{campaign.Status},
+		map[string]any{"status": StatusAborted, "paused_at": nil},
+	); updErr != nil {
+		return nil, updErr
+	}
+
+	job, err := s.messagesSvc.CancelMessages(ctx, userID, *new(messages.SelectFilter).WithCampaignID(id))
+	if err != nil {
+		if rbErr := s.campaigns.updateStatus(
+			ctx,
+			userID,
+			id,
+			[]Status{StatusAborted},
+			map[string]any{"status": campaign.Status, "paused_at": campaign.PausedAt},
+		); rbErr != nil {
+			s.logger.Error("failed to restore campaign status", zap.String("campaign_id", id), zap.Error(rbErr))
+		}
+		return nil, fmt.Errorf("failed to cancel campaign messages: %w", err)
+	}
+	s.logger.Info("campaign aborted", zap.String("campaign_id", id), zap.String("job_id", job.ID))
+
+	return s.Get(ctx, userID, id)
+}
+
+// Stats returns the progress of the campaign with the number of its messages by state.
+func (s *Service) Stats(ctx context.Context, userID, id string) (*Stats, error) {
+	campaign, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := s.messagesSvc.CountStates(ctx, userID, *new(messages.SelectFilter).WithCampaignID(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count campaign messages: %w", err)
+	}
+
+	return &Stats{
+		Total:     campaign.Total,
+		Generated: campaign.Generated,
+		States:    states,
+	}, nil
+}
+
+// Export writes a CSV report with a row per recipient in campaign order.
+// Recipients without a generated message have empty message columns.
+func (s *Service) Export(ctx context.Context, userID, id string, w io.Writer) error {
+	if _, err := s.campaigns.get(ctx, userID, id); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"position", "phone_number", "message_id", "state", "error"}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	position := -1
+	for {
+		recipients, err := s.campaigns.recipients(ctx, id, position, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(recipients) == 0 {
+			break
+		}
+
+		states, err := s.selectRecipientStates(userID, recipients)
+		if err != nil {
+			return err
+		}
+
+		for _, recipient := range recipients {
+			row := []string{strconv.Itoa(recipient.Position + 1), recipient.PhoneNumber, "", "", ""}
+			if recipient.MessageID != nil {
+				row[2] = *recipient.MessageID
+				if state, ok := states[*recipient.MessageID]; ok {
+					row[3] = string(state.State)
+					row[4] = lo.FromPtr(state.Error)
+				}
+			}
+
+			if writeErr := writer.Write(row); writeErr != nil {
+				return fmt.Errorf("failed to write row: %w", writeErr)
+			}
+		}
+
+		position = recipients[len(recipients)-1].Position
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush export: %w", err)
+	}
+
+	return nil
+}
+
+// selectRecipientStates returns recipient states of the generated messages keyed by message ID.
+func (s *Service) selectRecipientStates(userID string, recipients []Recipient) (map[string]recipientState, error) {
+	ids := lo.FilterMap(recipients, func(r Recipient, _ int) (string, bool) {
+		return lo.FromPtr(r.MessageID), r.MessageID != nil
+	})
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	states, _, err := s.messagesSvc.SelectStates(
+		userID,
+		*new(messages.SelectFilter).WithExtIDs(ids...),
+		*new(messages.SelectOptions).IncludeRecipients().WithLimit(len(ids)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select messages: %w", err)
+	}
+
+	result := make(map[string]recipientState, len(states))
+	for _, state := range states {
+		recipient := recipientState{State: state.State, Error: nil}
+		if len(state.Recipients) > 0 {
+			recipient.State = messages.ProcessingState(state.Recipients[0].State)
+			recipient.Error = state.Recipients[0].Error
+		}
+		result[state.ID] = recipient
+	}
+
+	return result, nil
+}
+
+type recipientState struct {
+	State messages.ProcessingState
+	Error *string
+}
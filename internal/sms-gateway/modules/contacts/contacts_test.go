@@ -0,0 +1,77 @@
+//nolint:testpackage // CSV and input helpers are unexported; in-package test required.
+package contacts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalize(t *testing.T) {
+	contact := Contact{
+		ID:          "id",
+		UserID:      "user",
+		Name:        "Ann",
+		PhoneNumber: "+79000000001",
+		Fields:      map[string]string{"code": "1234", "City": "Paris"},
+		Tags:        nil,
+	}
+
+	require.True(t, IsPersonalized("Hi {{ name }}"))
+	require.False(t, IsPersonalized("Hi {name} {{}} {{1st}}"))
+
+	require.Equal(t,
+		"Hi Ann (+79000000001), your code is 1234 in Paris. ",
+		Personalize("Hi {{Name}} ({{phone_number}}), your code is {{code}} in {{City}}. {{missing}}", contact),
+	)
+}
+
+func TestParseCSV(t *testing.T) {
+	inputs, err := parseCSV(strings.NewReader(
+		"\ufeffName,phone_number,tags,city\n" +
+			"Ann,+79000000001,vip; new ,Paris\n" +
+			"Bob,+79000000002,,\n",
+	))
+	require.NoError(t, err)
+	require.Len(t, inputs, 2)
+
+	require.Equal(t, "Ann", inputs[0].Name)
+	require.Equal(t, "+79000000001", inputs[0].PhoneNumber)
+	require.Equal(t, map[string]string{"city": "Paris"}, inputs[0].Fields)
+	require.NoError(t, prepareInput(&inputs[0]))
+	require.Equal(t, []string{"vip", "new"}, inputs[0].Tags)
+
+	require.Empty(t, inputs[1].Fields)
+	require.NoError(t, prepareInput(&inputs[1]))
+	require.Empty(t, inputs[1].Tags)
+
+	tests := map[string]string{
+		"empty":         "",
+		"no phone":      "name,city\nAnn,Paris\n",
+		"no rows":       "phone_number\n",
+		"invalid field": "phone_number,my field\n+79000000001,x\n",
+		"column count":  "phone_number,name\n+79000000001\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, parseErr := parseCSV(strings.NewReader(input))
+			require.ErrorAs(t, parseErr, new(ValidationError))
+		})
+	}
+}
+
+func TestPrepareInput(t *testing.T) {
+	tests := map[string]ContactInput{
+		"no phone":       {Name: "Ann", PhoneNumber: " ", Fields: nil, Tags: nil},
+		"reserved field": {Name: "", PhoneNumber: "1", Fields: map[string]string{"Name": "x"}, Tags: nil},
+		"invalid field":  {Name: "", PhoneNumber: "1", Fields: map[string]string{"a-b": "x"}, Tags: nil},
+		"tag separator":  {Name: "", PhoneNumber: "1", Fields: nil, Tags: []string{"a;b"}},
+		"long name":      {Name: strings.Repeat("a", maxNameLength+1), PhoneNumber: "1", Fields: nil, Tags: nil},
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorAs(t, prepareInput(&input), new(ValidationError))
+		})
+	}
+}
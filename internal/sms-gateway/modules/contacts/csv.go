@@ -0,0 +1,187 @@
+package contacts
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const (
+	maxImportRows   = 10_000
+	exportBatchSize = 500
+
+	// utf8BOM is prepended by spreadsheet editors to CSV files.
+	utf8BOM = "\ufeff"
+)
+
+// Import creates contacts from a CSV file and adds them to the group if groupID is set.
+//
+// The first row is the header. The `phone_number` column is required, `name` and
+// `tags` (separated by `;`) are optional and any other column is a custom field.
+// Contacts with a phone number that already exists are replaced. Either all rows
+// are imported or none; rows are numbered in errors without the header.
+func (s *Service) Import(ctx context.Context, userID string, r io.Reader, groupID string) (ImportResult, error) {
+	inputs, err := parseCSV(r)
+	if err != nil {
+		return ImportResult{Created: 0, Updated: 0}, err
+	}
+
+	if inputs, err = s.prepareInputs(userID, inputs); err != nil {
+		return ImportResult{Created: 0, Updated: 0}, err
+	}
+
+	models := lo.Map(inputs, func(input ContactInput, _ int) *contactModel {
+		return newContactModel(s.idgen(), userID, input)
+	})
+
+	return s.contacts.upsert(ctx, userID, models, groupID)
+}
+
+// Export writes all contacts of the user to a CSV file in the import format.
+func (s *Service) Export(ctx context.Context, userID string, w io.Writer) error {
+	fields := map[string]struct{}{}
+	if err := s.eachContact(ctx, userID, func(contact contactModel) error {
+		for key := range contact.Fields {
+			fields[key] = struct{}{}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	keys := lo.Keys(fields)
+	slices.Sort(keys)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{FieldPhoneNumber, FieldName, fieldTags}, keys...)); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	if err := s.eachContact(ctx, userID, func(contact contactModel) error {
+		row := make([]string, 0, len(keys)+3) //nolint:mnd // built-in columns
+		row = append(row, contact.PhoneNumber, contact.Name, strings.Join(contact.Tags, tagsSeparator))
+		for _, key := range keys {
+			row = append(row, contact.Fields[key])
+		}
+
+		if writeErr := writer.Write(row); writeErr != nil {
+			return fmt.Errorf("failed to write row: %w", writeErr)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush export: %w", err)
+	}
+
+	return nil
+}
+
+// eachContact calls fn for every contact of the user in ID order.
+func (s *Service) eachContact(ctx context.Context, userID string, fn func(contactModel) error) error {
+	afterID := ""
+	for {
+		contacts, err := s.contacts.selectAfter(ctx, userID, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, contact := range contacts {
+			if fnErr := fn(contact); fnErr != nil {
+				return fnErr
+			}
+		}
+
+		if len(contacts) < exportBatchSize {
+			return nil
+		}
+		afterID = contacts[len(contacts)-1].ID
+	}
+}
+
+// parseCSV reads contact inputs from a CSV file with a header row.
+func parseCSV(r io.Reader) ([]ContactInput, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ValidationError("CSV file is empty")
+	}
+	if err != nil {
+		return nil, ValidationError(fmt.Sprintf("failed to read CSV header: %s", err))
+	}
+
+	phoneColumn, nameColumn, tagsColumn := -1, -1, -1
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, utf8BOM))
+		header[i] = column
+
+		switch strings.ToLower(column) {
+		case FieldPhoneNumber:
+			phoneColumn = i
+		case FieldName:
+			nameColumn = i
+		case fieldTags:
+			tagsColumn = i
+		default:
+			if !fieldKeyRegexp.MatchString(column) {
+				return nil, ValidationError(fmt.Sprintf("invalid field name %q in CSV header", column))
+			}
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, ValidationError(fmt.Sprintf("CSV header must contain the %q column", FieldPhoneNumber))
+	}
+
+	inputs := []ContactInput{}
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, ValidationError(fmt.Sprintf("failed to read CSV: %s", readErr))
+		}
+		if len(inputs) == maxImportRows {
+			return nil, ValidationError(fmt.Sprintf("too many rows, max %d", maxImportRows))
+		}
+
+		input := ContactInput{
+			Name:        "",
+			PhoneNumber: record[phoneColumn],
+			Fields:      map[string]string{},
+			Tags:        nil,
+		}
+		for i, value := range record {
+			switch i {
+			case phoneColumn:
+			case nameColumn:
+				input.Name = value
+			case tagsColumn:
+				input.Tags = strings.Split(value, tagsSeparator)
+			default:
+				if value != "" {
+					input.Fields[header[i]] = value
+				}
+			}
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	if len(inputs) == 0 {
+		return nil, ValidationError("CSV file has no contacts")
+	}
+
+	return inputs, nil
+}
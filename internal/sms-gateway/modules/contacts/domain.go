@@ -0,0 +1,49 @@
+package contacts
+
+import "time"
+
+type ContactInput struct {
+	Name        string
+	PhoneNumber string
+	// Fields are custom values available as placeholders in personalized messages.
+	Fields map[string]string
+	Tags   []string
+}
+
+type Contact struct {
+	ID     string
+	UserID string
+
+	Name        string
+	PhoneNumber string
+	Fields      map[string]string
+	Tags        []string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Group struct {
+	ID     string
+	UserID string
+
+	Name    string
+	Members int64 // Number of contacts in the group
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ListFilter narrows the listed contacts. Empty fields are ignored.
+type ListFilter struct {
+	Tag     string
+	GroupID string
+}
+
+// ImportResult is the outcome of a CSV import.
+type ImportResult struct {
+	// Created is the number of new contacts.
+	Created int
+	// Updated is the number of existing contacts with the same phone number that were replaced.
+	Updated int
+}
@@ -0,0 +1,16 @@
+package contacts
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("contact not found")
+	ErrGroupNotFound = errors.New("contact group not found")
+	ErrContactExists = errors.New("contact with the same phone number already exists")
+	ErrGroupExists   = errors.New("contact group with the same name already exists")
+)
+
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,111 @@
+package contacts
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/users"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// contactModel is not referenced by messages: the phone number and the personalized
+// text are copied on enqueue, so deleting a contact keeps the message history intact.
+type contactModel struct {
+	models.TimedModel
+
+	ID     string `gorm:"primaryKey;type:char(21)"`
+	UserID string `gorm:"not null;type:varchar(32);uniqueIndex:unq_contacts_user_phone,priority:1"`
+
+	Name        string                      `gorm:"not null;type:varchar(128)"`
+	PhoneNumber string                      `gorm:"not null;type:varchar(128);uniqueIndex:unq_contacts_user_phone,priority:2"`
+	Fields      map[string]string           `gorm:"serializer:json;type:json"`
+	Tags        datatypes.JSONSlice[string] `gorm:"serializer:json;type:json"`
+
+	User users.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func newContactModel(id, userID string, input ContactInput) *contactModel {
+	//nolint:exhaustruct // partial constructor
+	return &contactModel{
+		ID:          id,
+		UserID:      userID,
+		Name:        input.Name,
+		PhoneNumber: input.PhoneNumber,
+		Fields:      input.Fields,
+		Tags:        datatypes.NewJSONSlice(input.Tags),
+	}
+}
+
+func (*contactModel) TableName() string {
+	return "contacts"
+}
+
+func (m *contactModel) toDomain() Contact {
+	fields := m.Fields
+	if fields == nil {
+		fields = map[string]string{}
+	}
+	tags := []string(m.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return Contact{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Name:        m.Name,
+		PhoneNumber: m.PhoneNumber,
+		Fields:      fields,
+		Tags:        tags,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+type groupModel struct {
+	models.TimedModel
+
+	ID     string `gorm:"primaryKey;type:char(21)"`
+	UserID string `gorm:"not null;type:varchar(32);uniqueIndex:unq_contact_groups_user_name,priority:1"`
+	Name   string `gorm:"not null;type:varchar(128);uniqueIndex:unq_contact_groups_user_name,priority:2"`
+
+	// Members is selected by the repository and is not stored.
+	Members int64 `gorm:"->;-:migration"`
+
+	User users.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (*groupModel) TableName() string {
+	return "contact_groups"
+}
+
+func (m *groupModel) toDomain() Group {
+	return Group{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		Members:   m.Members,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+type groupMemberModel struct {
+	GroupID   string `gorm:"primaryKey;type:char(21)"`
+	ContactID string `gorm:"primaryKey;type:char(21);index:idx_contact_group_members_contact"`
+
+	Group   groupModel   `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE"`
+	Contact contactModel `gorm:"foreignKey:ContactID;constraint:OnDelete:CASCADE"`
+}
+
+func (*groupMemberModel) TableName() string {
+	return "contact_group_members"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(new(contactModel), new(groupModel), new(groupMemberModel)); err != nil {
+		return fmt.Errorf("contacts migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package contacts
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// FieldName and FieldPhoneNumber are the placeholders of the built-in contact fields.
+	FieldName        = "name"
+	FieldPhoneNumber = "phone_number"
+
+	fieldTags = "tags"
+)
+
+//nolint:gochecknoglobals // compiled once
+var (
+	placeholderRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	fieldKeyRegexp    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+)
+
+// IsPersonalized reports whether the text contains `{{field}}` placeholders.
+func IsPersonalized(text string) bool {
+	return placeholderRegexp.MatchString(text)
+}
+
+// Personalize replaces the placeholders in the text with the values of the contact.
+// Besides custom fields, `{{name}}` and `{{phone_number}}` are available.
+// Placeholders of fields the contact doesn't have are replaced with an empty string.
+func Personalize(text string, contact Contact) string {
+	return placeholderRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		key := placeholderRegexp.FindStringSubmatch(match)[1]
+
+		switch strings.ToLower(key) {
+		case FieldName:
+			return contact.Name
+		case FieldPhoneNumber:
+			return contact.PhoneNumber
+		}
+
+		return contact.Fields[key]
+	})
+}
+
+// isReservedField reports whether the key clashes with a built-in field or a CSV column.
+func isReservedField(key string) bool {
+	switch strings.ToLower(key) {
+	case FieldName, FieldPhoneNumber, fieldTags:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,359 @@
+package contacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/pkg/mysql"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	insertBatchSize = 500
+
+	groupColumns = "contact_groups.*, " +
+		"(SELECT COUNT(*) FROM contact_group_members WHERE contact_group_members.group_id = contact_groups.id) AS members"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) insert(ctx context.Context, contact *contactModel) error {
+	err := r.db.WithContext(ctx).Omit("User").Create(contact).Error
+	if isDuplicateKey(err) {
+		return ErrContactExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert contact: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) get(ctx context.Context, userID, id string) (*contactModel, error) {
+	contact := new(contactModel)
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Take(contact).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+
+	return contact, nil
+}
+
+func (r *Repository) list(
+	ctx context.Context,
+	userID string,
+	filter ListFilter,
+	limit, offset int,
+) ([]contactModel, int64, error) {
+	query := r.db.WithContext(ctx).Model((*contactModel)(nil)).Where("user_id = ?", userID)
+	if filter.Tag != "" {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", filter.Tag)
+	}
+	if filter.GroupID != "" {
+		query = query.Where(
+			"id IN (SELECT contact_id FROM contact_group_members WHERE group_id = ?)",
+			filter.GroupID,
+		)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count contacts: %w", err)
+	}
+
+	contacts := []contactModel{}
+	if err := query.Order("name, id").Limit(limit).Offset(offset).Find(&contacts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to select contacts: %w", err)
+	}
+
+	return contacts, total, nil
+}
+
+// selectAfter returns the contacts with IDs greater than afterID ordered by ID.
+func (r *Repository) selectAfter(ctx context.Context, userID, afterID string, limit int) ([]contactModel, error) {
+	contacts := []contactModel{}
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to select contacts: %w", err)
+	}
+
+	return contacts, nil
+}
+
+func (r *Repository) selectByIDs(ctx context.Context, userID string, ids []string) ([]contactModel, error) {
+	contacts := []contactModel{}
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to select contacts: %w", err)
+	}
+
+	return contacts, nil
+}
+
+// selectByGroups returns the members of the groups ordered by name.
+func (r *Repository) selectByGroups(ctx context.Context, userID string, groupIDs []string) ([]contactModel, error) {
+	contacts := []contactModel{}
+	if err := r.db.WithContext(ctx).
+		Where(
+			"user_id = ? AND id IN (SELECT contact_id FROM contact_group_members WHERE group_id IN ?)",
+			userID,
+			groupIDs,
+		).
+		Order("name, id").
+		Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to select group members: %w", err)
+	}
+
+	return contacts, nil
+}
+
+func (r *Repository) update(ctx context.Context, contact *contactModel) error {
+	err := r.db.WithContext(ctx).
+		Model(contact).
+		Where("user_id = ?", contact.UserID).
+		Select("name", "phone_number", "fields", "tags").
+		Updates(contact).Error
+	if isDuplicateKey(err) {
+		return ErrContactExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) delete(ctx context.Context, userID, id string) error {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Delete((*contactModel)(nil))
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete contact: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// upsert inserts the contacts or replaces the existing ones with the same phone number
+// and adds all of them to the group if groupID is not empty.
+func (r *Repository) upsert(
+	ctx context.Context,
+	userID string,
+	contacts []*contactModel,
+	groupID string,
+) (ImportResult, error) {
+	result := ImportResult{Created: 0, Updated: 0}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if groupID != "" {
+			if err := tx.Where("user_id = ? AND id = ?", userID, groupID).
+				Take(new(groupModel)).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrGroupNotFound
+				}
+				return err
+			}
+		}
+
+		phones := lo.Map(contacts, func(c *contactModel, _ int) string { return c.PhoneNumber })
+		existing := []contactModel{}
+		for _, chunk := range lo.Chunk(phones, insertBatchSize) {
+			batch := []contactModel{}
+			if err := tx.Select("id", "phone_number").
+				Where("user_id = ? AND phone_number IN ?", userID, chunk).
+				Find(&batch).Error; err != nil {
+				return err
+			}
+			existing = append(existing, batch...)
+		}
+		ids := lo.SliceToMap(existing, func(c contactModel) (string, string) { return c.PhoneNumber, c.ID })
+
+		created := make([]*contactModel, 0, len(contacts))
+		for _, contact := range contacts {
+			id, ok := ids[contact.PhoneNumber]
+			if !ok {
+				created = append(created, contact)
+				continue
+			}
+
+			contact.ID = id
+			if err := tx.Model(contact).
+				Select("name", "fields", "tags").
+				Updates(contact).Error; err != nil {
+				return err
+			}
+			result.Updated++
+		}
+
+		if len(created) > 0 {
+			if err := tx.Omit("User").CreateInBatches(created, insertBatchSize).Error; err != nil {
+				return err
+			}
+			result.Created = len(created)
+		}
+
+		if groupID == "" {
+			return nil
+		}
+
+		members := lo.Map(contacts, func(c *contactModel, _ int) groupMemberModel {
+			//nolint:exhaustruct // associations are not inserted
+			return groupMemberModel{GroupID: groupID, ContactID: c.ID}
+		})
+		return tx.Omit(clause.Associations).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(members, insertBatchSize).Error
+	})
+	if errors.Is(err, ErrGroupNotFound) {
+		return result, err
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to import contacts: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *Repository) insertGroup(ctx context.Context, group *groupModel) error {
+	err := r.db.WithContext(ctx).Omit("User").Create(group).Error
+	if isDuplicateKey(err) {
+		return ErrGroupExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert contact group: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) getGroup(ctx context.Context, userID, id string) (*groupModel, error) {
+	group := new(groupModel)
+	err := r.db.WithContext(ctx).
+		Select(groupColumns).
+		Where("user_id = ? AND id = ?", userID, id).
+		Take(group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact group: %w", err)
+	}
+
+	return group, nil
+}
+
+func (r *Repository) listGroups(ctx context.Context, userID string, limit, offset int) ([]groupModel, int64, error) {
+	query := r.db.WithContext(ctx).Model((*groupModel)(nil)).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count contact groups: %w", err)
+	}
+
+	groups := []groupModel{}
+	if err := query.Select(groupColumns).
+		Order("name").
+		Limit(limit).
+		Offset(offset).
+		Find(&groups).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to select contact groups: %w", err)
+	}
+
+	return groups, total, nil
+}
+
+// selectGroupIDs returns the IDs of the user's groups among the given ones.
+func (r *Repository) selectGroupIDs(ctx context.Context, userID string, ids []string) ([]string, error) {
+	found := []string{}
+	if err := r.db.WithContext(ctx).
+		Model((*groupModel)(nil)).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Pluck("id", &found).Error; err != nil {
+		return nil, fmt.Errorf("failed to select contact groups: %w", err)
+	}
+
+	return found, nil
+}
+
+func (r *Repository) renameGroup(ctx context.Context, userID, id, name string) error {
+	err := r.db.WithContext(ctx).
+		Model((*groupModel)(nil)).
+		Where("user_id = ? AND id = ?", userID, id).
+		Update("name", name).Error
+	if isDuplicateKey(err) {
+		return ErrGroupExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rename contact group: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) deleteGroup(ctx context.Context, userID, id string) error {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Delete((*groupModel)(nil))
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete contact group: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// addMembers adds the user's contacts to the group. Contacts that are already members are skipped.
+func (r *Repository) addMembers(ctx context.Context, groupID string, contactIDs []string) error {
+	members := lo.Map(contactIDs, func(id string, _ int) groupMemberModel {
+		//nolint:exhaustruct // associations are not inserted
+		return groupMemberModel{GroupID: groupID, ContactID: id}
+	})
+
+	if err := r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(members, insertBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to add group members: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) removeMembers(ctx context.Context, groupID string, contactIDs []string) error {
+	if err := r.db.WithContext(ctx).
+		Where("group_id = ? AND contact_id IN ?", groupID, contactIDs).
+		Delete((*groupMemberModel)(nil)).Error; err != nil {
+		return fmt.Errorf("failed to remove group members: %w", err)
+	}
+
+	return nil
+}
+
+func isDuplicateKey(err error) bool {
+	return err != nil && (errors.Is(err, gorm.ErrDuplicatedKey) || mysql.IsDuplicateKeyViolation(err))
+}
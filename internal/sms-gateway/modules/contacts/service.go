@@ -0,0 +1,382 @@
+package contacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+const (
+	maxNameLength       = 128
+	maxFields           = 32
+	maxFieldValueLength = 1024
+	maxTags             = 32
+	maxTagLength        = 64
+	maxGroupMembers     = 1000 // per request
+
+	// tagsSeparator separates tags in a single CSV column.
+	tagsSeparator = ";"
+)
+
+type Service struct {
+	contacts *Repository
+
+	messagesSvc *messages.Service
+
+	idgen  db.IDGen
+	logger *zap.Logger
+}
+
+func NewService(
+	contacts *Repository,
+	messagesSvc *messages.Service,
+	idgen db.IDGen,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		contacts: contacts,
+
+		messagesSvc: messagesSvc,
+
+		idgen:  idgen,
+		logger: logger,
+	}
+}
+
+// Create validates the contact and stores it. The phone number is normalized
+// the same way as on enqueue and must be unique among the user's contacts.
+func (s *Service) Create(ctx context.Context, userID string, input ContactInput) (*Contact, error) {
+	inputs, err := s.prepareInputs(userID, []ContactInput{input})
+	if err != nil {
+		return nil, err
+	}
+
+	model := newContactModel(s.idgen(), userID, inputs[0])
+	if insErr := s.contacts.insert(ctx, model); insErr != nil {
+		return nil, insErr
+	}
+
+	return s.Get(ctx, userID, model.ID)
+}
+
+func (s *Service) Get(ctx context.Context, userID, id string) (*Contact, error) {
+	model, err := s.contacts.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	contact := model.toDomain()
+	return &contact, nil
+}
+
+func (s *Service) List(
+	ctx context.Context,
+	userID string,
+	filter ListFilter,
+	limit, offset int,
+) ([]Contact, int64, error) {
+	models, total, err := s.contacts.list(ctx, userID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return lo.Map(models, func(m contactModel, _ int) Contact { return m.toDomain() }), total, nil
+}
+
+// Update replaces the contact with the input.
+func (s *Service) Update(ctx context.Context, userID, id string, input ContactInput) (*Contact, error) {
+	model, err := s.contacts.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := s.prepareInputs(userID, []ContactInput{input})
+	if err != nil {
+		return nil, err
+	}
+
+	updated := newContactModel(model.ID, userID, inputs[0])
+	if updErr := s.contacts.update(ctx, updated); updErr != nil {
+		return nil, updErr
+	}
+
+	return s.Get(ctx, userID, id)
+}
+
+// Delete removes the contact and its group memberships. Messages sent to the contact
+// keep their recipients and text as they were on enqueue.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	return s.contacts.delete(ctx, userID, id)
+}
+
+// Resolve returns the contacts with the given IDs followed by the members of
+// the given groups. Every contact is returned once.
+func (s *Service) Resolve(ctx context.Context, userID string, contactIDs, groupIDs []string) ([]Contact, error) {
+	contactIDs = lo.Uniq(contactIDs)
+	groupIDs = lo.Uniq(groupIDs)
+
+	result := make([]Contact, 0, len(contactIDs))
+	seen := make(map[string]struct{}, len(contactIDs))
+
+	if len(contactIDs) > 0 {
+		models, err := s.selectContacts(ctx, userID, contactIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range models {
+			seen[m.ID] = struct{}{}
+			result = append(result, m.toDomain())
+		}
+	}
+
+	if len(groupIDs) > 0 {
+		found, err := s.contacts.selectGroupIDs(ctx, userID, groupIDs)
+		if err != nil {
+			return nil, err
+		}
+		if missing := lo.Without(groupIDs, found...); len(missing) > 0 {
+			return nil, ValidationError(fmt.Sprintf("contact group %q not found", missing[0]))
+		}
+
+		members, err := s.contacts.selectByGroups(ctx, userID, groupIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if _, ok := seen[m.ID]; ok {
+				continue
+			}
+			seen[m.ID] = struct{}{}
+			result = append(result, m.toDomain())
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) CreateGroup(ctx context.Context, userID, name string) (*Group, error) {
+	name, err := prepareGroupName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:exhaustruct // partial constructor
+	model := &groupModel{
+		ID:     s.idgen(),
+		UserID: userID,
+		Name:   name,
+	}
+	if insErr := s.contacts.insertGroup(ctx, model); insErr != nil {
+		return nil, insErr
+	}
+
+	return s.GetGroup(ctx, userID, model.ID)
+}
+
+func (s *Service) GetGroup(ctx context.Context, userID, id string) (*Group, error) {
+	model, err := s.contacts.getGroup(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group := model.toDomain()
+	return &group, nil
+}
+
+func (s *Service) ListGroups(ctx context.Context, userID string, limit, offset int) ([]Group, int64, error) {
+	models, total, err := s.contacts.listGroups(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return lo.Map(models, func(m groupModel, _ int) Group { return m.toDomain() }), total, nil
+}
+
+func (s *Service) RenameGroup(ctx context.Context, userID, id, name string) (*Group, error) {
+	name, err := prepareGroupName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, getErr := s.contacts.getGroup(ctx, userID, id); getErr != nil {
+		return nil, getErr
+	}
+
+	if renErr := s.contacts.renameGroup(ctx, userID, id, name); renErr != nil {
+		return nil, renErr
+	}
+
+	return s.GetGroup(ctx, userID, id)
+}
+
+// DeleteGroup removes the group. Its contacts are kept.
+func (s *Service) DeleteGroup(ctx context.Context, userID, id string) error {
+	return s.contacts.deleteGroup(ctx, userID, id)
+}
+
+// AddMembers adds the user's contacts to the group.
+func (s *Service) AddMembers(ctx context.Context, userID, groupID string, contactIDs []string) (*Group, error) {
+	contactIDs, err := s.prepareMembers(ctx, userID, groupID, contactIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if addErr := s.contacts.addMembers(ctx, groupID, contactIDs); addErr != nil {
+		return nil, addErr
+	}
+
+	return s.GetGroup(ctx, userID, groupID)
+}
+
+// RemoveMembers removes the contacts from the group. The contacts themselves are kept.
+func (s *Service) RemoveMembers(ctx context.Context, userID, groupID string, contactIDs []string) (*Group, error) {
+	contactIDs, err := s.prepareMembers(ctx, userID, groupID, contactIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if remErr := s.contacts.removeMembers(ctx, groupID, contactIDs); remErr != nil {
+		return nil, remErr
+	}
+
+	return s.GetGroup(ctx, userID, groupID)
+}
+
+// prepareMembers checks that the group and the contacts belong to the user.
+func (s *Service) prepareMembers(ctx context.Context, userID, groupID string, contactIDs []string) ([]string, error) {
+	contactIDs = lo.Uniq(contactIDs)
+	if len(contactIDs) == 0 {
+		return nil, ValidationError("at least one contact is required")
+	}
+	if len(contactIDs) > maxGroupMembers {
+		return nil, ValidationError(fmt.Sprintf("too many contacts, max %d", maxGroupMembers))
+	}
+
+	if _, err := s.contacts.getGroup(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.selectContacts(ctx, userID, contactIDs); err != nil {
+		return nil, err
+	}
+
+	return contactIDs, nil
+}
+
+// selectContacts returns the user's contacts in the order of IDs.
+func (s *Service) selectContacts(ctx context.Context, userID string, ids []string) ([]contactModel, error) {
+	models, err := s.contacts.selectByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := lo.KeyBy(models, func(m contactModel) string { return m.ID })
+	result := make([]contactModel, 0, len(ids))
+	for _, id := range ids {
+		m, ok := byID[id]
+		if !ok {
+			return nil, ValidationError(fmt.Sprintf("contact %q not found", id))
+		}
+		result = append(result, m)
+	}
+
+	return result, nil
+}
+
+// prepareInputs validates the inputs and normalizes their phone numbers.
+func (s *Service) prepareInputs(userID string, inputs []ContactInput) ([]ContactInput, error) {
+	phones := make([]string, len(inputs))
+	for i := range inputs {
+		if err := prepareInput(&inputs[i]); err != nil {
+			if len(inputs) > 1 {
+				return nil, ValidationError(fmt.Sprintf("row %d: %s", i+1, err))
+			}
+			return nil, err
+		}
+		phones[i] = inputs[i].PhoneNumber
+	}
+
+	if err := s.messagesSvc.ValidatePhoneNumbers(userID, phones); err != nil {
+		var validationErr messages.ValidationError
+		if errors.As(err, &validationErr) {
+			if len(inputs) > 1 {
+				return nil, ValidationError(err.Error())
+			}
+			return nil, ValidationError(validationErr.Error())
+		}
+		return nil, fmt.Errorf("failed to validate phone numbers: %w", err)
+	}
+
+	for i := range inputs {
+		inputs[i].PhoneNumber = phones[i]
+	}
+
+	return inputs, nil
+}
+
+func prepareInput(input *ContactInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if utf8.RuneCountInString(input.Name) > maxNameLength {
+		return ValidationError(fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+
+	input.PhoneNumber = strings.TrimSpace(input.PhoneNumber)
+	if input.PhoneNumber == "" {
+		return ValidationError("phone number is required")
+	}
+
+	if len(input.Fields) > maxFields {
+		return ValidationError(fmt.Sprintf("too many fields, max %d", maxFields))
+	}
+	for key, value := range input.Fields {
+		if !fieldKeyRegexp.MatchString(key) {
+			return ValidationError(fmt.Sprintf("invalid field name %q", key))
+		}
+		if isReservedField(key) {
+			return ValidationError(fmt.Sprintf("field name %q is reserved", key))
+		}
+		if utf8.RuneCountInString(value) > maxFieldValueLength {
+			return ValidationError(fmt.Sprintf("field %q must be at most %d characters", key, maxFieldValueLength))
+		}
+	}
+
+	tags := make([]string, 0, len(input.Tags))
+	for _, tag := range input.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return ValidationError(fmt.Sprintf("tag %q must be at most %d characters", tag, maxTagLength))
+		}
+		if strings.Contains(tag, tagsSeparator) {
+			return ValidationError(fmt.Sprintf("tag %q must not contain %q", tag, tagsSeparator))
+		}
+		tags = append(tags, tag)
+	}
+	input.Tags = lo.Uniq(tags)
+	if len(input.Tags) > maxTags {
+		return ValidationError(fmt.Sprintf("too many tags, max %d", maxTags))
+	}
+
+	return nil
+}
+
+func prepareGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ValidationError("group name is required")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", ValidationError(fmt.Sprintf("group name must be at most %d characters", maxNameLength))
+	}
+
+	return name, nil
+}
@@ -0,0 +1,51 @@
+package devices
+
+import (
+	"context"
+	"slices"
+)
+
+// SelectPending returns the devices of all users waiting for approval, oldest first.
+func (s *Service) SelectPending(ctx context.Context) ([]Device, error) {
+	devices, err := s.devices.Select(ctx, WithStatus(StatusPendingApproval))
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(devices, func(a, b Device) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	return devices, nil
+}
+
+// Approve activates the device pending approval, messages are routed to it from now on.
+func (s *Service) Approve(ctx context.Context, id string) (*Device, error) {
+	if err := s.devices.Approve(ctx, id); err != nil {
+		return nil, err
+	}
+
+	device, err := s.devices.Get(ctx, WithID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	// the device polls its state, so the cached pending state must go everywhere
+	s.invalidateTokens(device.ID, device.tokens()...)
+
+	return device, nil
+}
+
+// Reject removes the device pending approval, its token stops working immediately.
+func (s *Service) Reject(ctx context.Context, id string) error {
+	device, err := s.devices.Get(ctx, WithID(id), WithStatus(StatusPendingApproval))
+	if err != nil {
+		return err
+	}
+
+	if rmErr := s.devices.Remove(ctx, WithID(device.ID)); rmErr != nil {
+		return rmErr
+	}
+
+	s.invalidateTokens(device.ID, device.tokens()...)
+
+	return nil
+}
@@ -22,9 +22,15 @@ func newCache() *cache {
 	}
 }
 
+// Set caches the device by ID and by every token it may be authorized with,
+// the previous token has to be checked against its grace period on read.
 func (c *cache) Set(device Device) error {
-	err := errors.Join(c.byID.Set(device.ID, &device), c.byToken.Set(device.AuthToken, &device))
-	if err != nil {
+	errs := []error{c.byID.Set(device.ID, &device)}
+	for _, token := range device.tokens() {
+		errs = append(errs, c.byToken.Set(token, &device))
+	}
+
+	if err := errors.Join(errs...); err != nil {
 		return fmt.Errorf("failed to cache device: %w", err)
 	}
 
@@ -59,8 +65,35 @@ func (c *cache) DeleteByID(id string) error {
 		return fmt.Errorf("failed to get device by ID: %w", err)
 	}
 
-	err = errors.Join(c.byID.Delete(device.ID), c.byToken.Delete(device.AuthToken))
+	if delErr := c.delete(*device); delErr != nil {
+		return delErr
+	}
+
+	return nil
+}
+
+// DeleteByToken drops the device cached under the token, it may differ from
+// the current token of the device after rotation.
+func (c *cache) DeleteByToken(token string) error {
+	device, err := c.byToken.Get(token)
 	if err != nil {
+		if errors.Is(err, cacheImpl.ErrKeyNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get device by token: %w", err)
+	}
+
+	return errors.Join(c.delete(*device), c.byToken.Delete(token))
+}
+
+func (c *cache) delete(device Device) error {
+	errs := []error{c.byID.Delete(device.ID)}
+	for _, token := range device.tokens() {
+		errs = append(errs, c.byToken.Delete(token))
+	}
+
+	if err := errors.Join(errs...); err != nil {
 		return fmt.Errorf("failed to delete device: %w", err)
 	}
 
@@ -0,0 +1,50 @@
+//nolint:testpackage // the cache is unexported; in-package test required.
+package devices
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheDeleteByToken(t *testing.T) {
+	//nolint:exhaustruct // only the keys are used
+	device := Device{DeviceInput: DeviceInput{ID: "device", AuthToken: "current"}}
+
+	c := newCache()
+	require.NoError(t, c.Set(device))
+
+	// unknown tokens are ignored
+	require.NoError(t, c.DeleteByToken("previous"))
+	_, err := c.GetByID("device")
+	require.NoError(t, err)
+
+	require.NoError(t, c.DeleteByToken("current"))
+
+	_, err = c.GetByToken("current")
+	require.Error(t, err)
+	_, err = c.GetByID("device")
+	require.Error(t, err)
+}
+
+func TestCacheDeleteByIDPreviousToken(t *testing.T) {
+	//nolint:exhaustruct // only the keys are used
+	device := Device{
+		DeviceInput:   DeviceInput{ID: "device", AuthToken: "current"},
+		PrevAuthToken: lo.ToPtr("previous"),
+	}
+
+	c := newCache()
+	require.NoError(t, c.Set(device))
+
+	_, err := c.GetByToken("previous")
+	require.NoError(t, err)
+
+	require.NoError(t, c.DeleteByID("device"))
+
+	_, err = c.GetByToken("current")
+	require.Error(t, err)
+	_, err = c.GetByToken("previous")
+	require.Error(t, err)
+}
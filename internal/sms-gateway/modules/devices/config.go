@@ -1,4 +1,8 @@
 package devices
 
+import "time"
+
 type Config struct {
+	// TokenGracePeriod is how long a rotated auth token stays valid.
+	TokenGracePeriod time.Duration
 }
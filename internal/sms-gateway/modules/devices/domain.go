@@ -1,6 +1,9 @@
 package devices
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type DeviceInput struct {
 	DeviceInfo
@@ -20,11 +23,37 @@ type DeviceInfo struct {
 type DeviceUpdate struct {
 	PushToken *string
 	SimCards  []SimCard
+	Features  []Feature // Capabilities reported by the app, nil keeps the current ones
 }
 
+// Feature is an optional capability of the app.
+type Feature string
+
+const (
+	// FeatureMms devices send MMS messages, other devices receive neither text nor data of them.
+	FeatureMms Feature = "mms"
+)
+
+// Status is the approval state of a device.
+type Status string
+
+const (
+	StatusActive Status = "active"
+	// StatusPendingApproval devices wait for an admin, messages are not routed to them.
+	StatusPendingApproval Status = "pending_approval"
+)
+
 type Device struct {
 	DeviceInput
 
+	Status      Status
+	Tags        []string   // Groups the device belongs to
+	PausedAt    *time.Time // Time the device was paused, nil if the device is active
+	PauseReason *string    // Why the device is paused
+
+	PrevAuthToken          *string    `json:"-"` // Token replaced by rotation
+	PrevAuthTokenExpiresAt *time.Time // End of the grace period of PrevAuthToken
+
 	LastSeen  time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -35,6 +64,44 @@ func (d Device) IsEmpty() bool {
 	return d.ID == ""
 }
 
+// IsPaused reports whether messages must not be routed to the device.
+func (d Device) IsPaused() bool {
+	return d.PausedAt != nil
+}
+
+// Supports reports whether the app of the device has the feature.
+func (d Device) Supports(feature Feature) bool {
+	return slices.Contains(d.Features, feature)
+}
+
+// IsPendingApproval reports whether the device waits for an admin approval.
+func (d Device) IsPendingApproval() bool {
+	return d.Status == StatusPendingApproval
+}
+
+// isPrevToken reports whether token is the one replaced by rotation and its
+// grace period is not over.
+func (d Device) isPrevToken(token string, now time.Time) bool {
+	return d.PrevAuthToken != nil && *d.PrevAuthToken == token &&
+		d.PrevAuthTokenExpiresAt != nil && d.PrevAuthTokenExpiresAt.After(now)
+}
+
+// tokens returns the tokens the device may be cached by.
+func (d Device) tokens() []string {
+	if d.PrevAuthToken == nil {
+		return []string{d.AuthToken}
+	}
+
+	return []string{d.AuthToken, *d.PrevAuthToken}
+}
+
+// DevicePatch lists changes to a device made by its owner. Nil fields are left unchanged.
+type DevicePatch struct {
+	Name   *string
+	Paused *bool
+	Reason *string // Pause reason, set together with pausing; resuming clears it
+}
+
 type SimCard struct {
 	SlotIndex   int // Zero-based index of the physical SIM slot (0, 1, ...).
 	SimNumber   int // One-based number used by the application.
@@ -42,3 +109,9 @@ type SimCard struct {
 	CarrierName *string
 	ICCID       *string
 }
+
+// Group is a device tag together with the number of devices tagged with it.
+type Group struct {
+	Name    string
+	Devices int
+}
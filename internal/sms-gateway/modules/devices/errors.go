@@ -1,7 +1,27 @@
 package devices
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/samber/lo"
+)
 
 var (
-	ErrInvalidUser = errors.New("invalid user")
+	ErrInvalidUser  = errors.New("invalid user")
+	ErrDevicePaused = errors.New("device is paused")
+	ErrPending      = errors.New("device is pending approval")
+	ErrSimNotFound  = errors.New("no device holds the sim card")
+
+	ErrTokenNotCurrent = errors.New("only the current token can be rotated")
 )
+
+func pausedError(device Device) error {
+	return fmt.Errorf("%w: %s", ErrDevicePaused, lo.FromPtrOr(device.PauseReason, "no reason given"))
+}
+
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
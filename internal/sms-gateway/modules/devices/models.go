@@ -18,14 +18,25 @@ type DeviceModel struct {
 	AuthToken string  `gorm:"not null;uniqueIndex;type:char(21)"`
 	PushToken *string `gorm:"type:varchar(256)"`
 
+	// The token replaced by rotation stays valid until PrevAuthTokenExpiresAt.
+	PrevAuthToken          *string    `gorm:"uniqueIndex;type:char(21)"`
+	PrevAuthTokenExpiresAt *time.Time `gorm:"type:datetime(3)"`
+
 	LastSeen time.Time `gorm:"not null;autocreatetime:false;default:CURRENT_TIMESTAMP(3);index:idx_devices_last_seen"`
 
 	UserID string `gorm:"not null;type:varchar(32)"`
 
+	Status Status `gorm:"not null;type:enum('active','pending_approval');default:active;index:idx_devices_status"`
+
 	SimCards datatypes.JSONSlice[simCardModel] `gorm:"serializer:json;type:json"`
+	Tags     datatypes.JSONSlice[string]       `gorm:"serializer:json;type:json"`
+	Features datatypes.JSONSlice[Feature]      `gorm:"serializer:json;type:json"`
+
+	PausedAt    *time.Time `gorm:"type:datetime(3)"`
+	PauseReason *string    `gorm:"type:varchar(256)"`
 }
 
-func newDeviceModel(device DeviceInput) *DeviceModel {
+func newDeviceModel(device DeviceInput, status Status) *DeviceModel {
 	now := time.Now()
 	return &DeviceModel{
 		SoftDeletableModel: models.SoftDeletableModel{
@@ -40,12 +51,22 @@ func newDeviceModel(device DeviceInput) *DeviceModel {
 		Name:      device.Name,
 		AuthToken: device.AuthToken,
 		PushToken: device.PushToken,
-		LastSeen:  now,
-		UserID:    device.UserID,
+
+		PrevAuthToken:          nil,
+		PrevAuthTokenExpiresAt: nil,
+
+		LastSeen: now,
+		UserID:   device.UserID,
+		Status:   status,
 		SimCards: lo.Map(
 			device.SimCards,
 			func(simCard SimCard, _ int) simCardModel { return newSimCardModel(simCard) },
 		),
+		Tags:     datatypes.NewJSONSlice([]string{}),
+		Features: datatypes.NewJSONSlice(lo.Ternary(device.Features == nil, []Feature{}, device.Features)),
+
+		PausedAt:    nil,
+		PauseReason: nil,
 	}
 }
 
@@ -64,6 +85,7 @@ func (m *DeviceModel) toDomain() *Device {
 				DeviceUpdate: DeviceUpdate{
 					PushToken: m.PushToken,
 					SimCards:  lo.Map(m.SimCards, func(m simCardModel, _ int) SimCard { return m.toDomain() }),
+					Features:  lo.Ternary(m.Features == nil, []Feature{}, []Feature(m.Features)),
 				},
 
 				Name: m.Name,
@@ -75,6 +97,14 @@ func (m *DeviceModel) toDomain() *Device {
 			AuthToken: m.AuthToken,
 		},
 
+		Status:      m.Status,
+		Tags:        lo.Ternary(m.Tags == nil, []string{}, []string(m.Tags)),
+		PausedAt:    m.PausedAt,
+		PauseReason: m.PauseReason,
+
+		PrevAuthToken:          m.PrevAuthToken,
+		PrevAuthTokenExpiresAt: m.PrevAuthTokenExpiresAt,
+
 		LastSeen:  m.LastSeen,
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
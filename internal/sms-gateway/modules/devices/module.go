@@ -2,6 +2,7 @@ package devices
 
 import (
 	"github.com/capcom6/go-infra-fx/db"
+	"github.com/go-core-fx/fxutil"
 	"github.com/go-core-fx/logger"
 	"go.uber.org/fx"
 )
@@ -15,6 +16,9 @@ func Module() fx.Option {
 			fx.Private,
 		),
 		fx.Provide(NewService),
+		fx.Invoke(
+			fxutil.RegisterRunnable[*Service](),
+		),
 	)
 }
 
@@ -10,12 +10,14 @@ import (
 	"github.com/samber/lo"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	ErrNotFound      = errors.New("record not found")
 	ErrInvalidFilter = errors.New("invalid filter")
 	ErrMoreThanOne   = errors.New("more than one record")
+	ErrLimitReached  = errors.New("device limit reached")
 )
 
 type Repository struct {
@@ -79,11 +81,41 @@ func (r *Repository) Get(ctx context.Context, filter ...SelectFilter) (*Device,
 	return &devices[0], nil
 }
 
-func (r *Repository) Insert(ctx context.Context, device DeviceInput) (*Device, error) {
-	model := newDeviceModel(device)
+// Insert creates the device. With maxDevices above 0 the devices of the user
+// are counted under the lock of the user's row, so concurrent registrations
+// can't exceed the limit, and ErrLimitReached is returned once it is reached.
+func (r *Repository) Insert(ctx context.Context, device DeviceInput, status Status, maxDevices int) (*Device, error) {
+	model := newDeviceModel(device, status)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if maxDevices > 0 {
+			var locked []string
+			if err := tx.Table("users").
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", device.UserID).
+				Pluck("id", &locked).Error; err != nil {
+				return fmt.Errorf("failed to lock user: %w", err)
+			}
+
+			var count int64
+			if err := tx.Model((*DeviceModel)(nil)).
+				Where("user_id = ?", device.UserID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to count devices: %w", err)
+			}
+			if count >= int64(maxDevices) {
+				return ErrLimitReached
+			}
+		}
+
+		if err := tx.Create(model).Error; err != nil {
+			return fmt.Errorf("failed to insert device: %w", err)
+		}
 
-	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
-		return nil, fmt.Errorf("failed to insert device: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return model.toDomain(), nil
@@ -103,6 +135,10 @@ func (r *Repository) Update(ctx context.Context, id string, device DeviceUpdate)
 		))
 	}
 
+	if device.Features != nil {
+		updates["features"] = datatypes.NewJSONSlice(device.Features)
+	}
+
 	if len(updates) == 0 {
 		return nil
 	}
@@ -120,6 +156,113 @@ func (r *Repository) Update(ctx context.Context, id string, device DeviceUpdate)
 	return nil
 }
 
+func (r *Repository) Patch(ctx context.Context, id string, patch DevicePatch) error {
+	updates := map[string]any{}
+
+	if patch.Name != nil {
+		updates["name"] = lo.EmptyableToPtr(*patch.Name)
+	}
+
+	if patch.Paused != nil {
+		if *patch.Paused {
+			// keep the original pause time when only the reason changes
+			updates["paused_at"] = gorm.Expr("COALESCE(paused_at, ?)", time.Now())
+			updates["pause_reason"] = patch.Reason
+		} else {
+			updates["paused_at"] = nil
+			updates["pause_reason"] = nil
+		}
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	err := r.db.
+		WithContext(ctx).
+		Model((*DeviceModel)(nil)).
+		Where("id = ?", id).
+		Updates(updates).
+		Error
+	if err != nil {
+		return fmt.Errorf("failed to patch device: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) SetTags(ctx context.Context, id string, tags []string) error {
+	err := r.db.
+		WithContext(ctx).
+		Model((*DeviceModel)(nil)).
+		Where("id = ?", id).
+		Update("tags", datatypes.NewJSONSlice(tags)).
+		Error
+	if err != nil {
+		return fmt.Errorf("failed to set device tags: %w", err)
+	}
+
+	return nil
+}
+
+// Approve activates the device pending approval. It returns ErrNotFound if
+// there is no such device.
+func (r *Repository) Approve(ctx context.Context, id string) error {
+	res := r.db.
+		WithContext(ctx).
+		Model((*DeviceModel)(nil)).
+		Where("id = ? AND status = ?", id, StatusPendingApproval).
+		Update("status", StatusActive)
+	if res.Error != nil {
+		return fmt.Errorf("failed to approve device: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RotateToken replaces the current token of the device, keeping it valid until
+// graceUntil. It returns ErrNotFound if token is not the current one.
+func (r *Repository) RotateToken(ctx context.Context, id, token, newToken string, graceUntil time.Time) error {
+	// MySQL assigns left to right, the current token has to be saved first
+	res := r.db.
+		WithContext(ctx).
+		Exec(
+			"UPDATE devices SET prev_auth_token = auth_token, prev_auth_token_expires_at = ?, auth_token = ? "+
+				"WHERE id = ? AND auth_token = ?",
+			graceUntil, newToken, id, token,
+		)
+	if res.Error != nil {
+		return fmt.Errorf("failed to rotate device token: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeTokens replaces the tokens of the device with a token nobody knows.
+func (r *Repository) RevokeTokens(ctx context.Context, id, newToken string) error {
+	err := r.db.
+		WithContext(ctx).
+		Model((*DeviceModel)(nil)).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"auth_token":                 newToken,
+			"prev_auth_token":            nil,
+			"prev_auth_token_expires_at": nil,
+		}).
+		Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke device tokens: %w", err)
+	}
+
+	return nil
+}
+
 func (r *Repository) SetLastSeenBatch(ctx context.Context, batch map[string]time.Time) error {
 	if len(batch) == 0 {
 		return nil
@@ -14,6 +14,7 @@ func WithID(id string) SelectFilter {
 	}
 }
 
+// WithToken selects the device by its auth token or its previous token within the grace period.
 func WithToken(token string) SelectFilter {
 	return func(f *selectFilter) {
 		f.token = &token
@@ -26,6 +27,34 @@ func WithUserID(userID string) SelectFilter {
 	}
 }
 
+// WithTag selects the devices of a group.
+func WithTag(tag string) SelectFilter {
+	return func(f *selectFilter) {
+		f.tag = &tag
+	}
+}
+
+// WithFeature selects the devices whose app has the feature.
+func WithFeature(feature Feature) SelectFilter {
+	return func(f *selectFilter) {
+		f.feature = &feature
+	}
+}
+
+// WithPaused selects the paused or the not paused devices.
+func WithPaused(paused bool) SelectFilter {
+	return func(f *selectFilter) {
+		f.paused = &paused
+	}
+}
+
+// WithStatus selects the devices in the approval state.
+func WithStatus(status Status) SelectFilter {
+	return func(f *selectFilter) {
+		f.status = &status
+	}
+}
+
 func ActiveWithin(duration time.Duration) SelectFilter {
 	return func(f *selectFilter) {
 		f.activeWithin = duration
@@ -36,6 +65,10 @@ type selectFilter struct {
 	id           *string
 	userID       *string
 	token        *string
+	tag          *string
+	feature      *Feature
+	paused       *bool
+	status       *Status
 	activeWithin time.Duration
 }
 
@@ -56,11 +89,30 @@ func (f *selectFilter) apply(query *gorm.DB) *gorm.DB {
 		query = query.Where("id = ?", *f.id)
 	}
 	if f.token != nil {
-		query = query.Where("auth_token = ?", *f.token)
+		query = query.Where(
+			"(auth_token = ? OR (prev_auth_token = ? AND prev_auth_token_expires_at > ?))",
+			*f.token, *f.token, time.Now(),
+		)
 	}
 	if f.userID != nil {
 		query = query.Where("user_id = ?", *f.userID)
 	}
+	if f.tag != nil {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", *f.tag)
+	}
+	if f.feature != nil {
+		query = query.Where("JSON_CONTAINS(features, JSON_QUOTE(?))", string(*f.feature))
+	}
+	if f.paused != nil {
+		if *f.paused {
+			query = query.Where("paused_at IS NOT NULL")
+		} else {
+			query = query.Where("paused_at IS NULL")
+		}
+	}
+	if f.status != nil {
+		query = query.Where("status = ?", *f.status)
+	}
 	if f.activeWithin != 0 {
 		query = query.Where("last_seen > ?", time.Now().Add(-f.activeWithin))
 	}
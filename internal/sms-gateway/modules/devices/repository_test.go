@@ -0,0 +1,68 @@
+//nolint:testpackage // the repository db is unexported; in-package test required.
+package devices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB creates the columns of the devices and users tables. The MySQL
+// schema uses enums, so the models can't be migrated on SQLite.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	//nolint:exhaustruct // defaults
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// every connection opens its own in-memory database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	for _, query := range []string{
+		"CREATE TABLE users (id TEXT PRIMARY KEY)",
+		"INSERT INTO users (id) VALUES ('user')",
+		`CREATE TABLE devices (
+			id TEXT PRIMARY KEY, name TEXT, auth_token TEXT NOT NULL, push_token TEXT,
+			prev_auth_token TEXT, prev_auth_token_expires_at DATETIME, last_seen DATETIME,
+			user_id TEXT NOT NULL, status TEXT, sim_cards TEXT, tags TEXT, features TEXT,
+			paused_at DATETIME, pause_reason TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+		)`,
+	} {
+		require.NoError(t, db.Exec(query).Error)
+	}
+
+	return db
+}
+
+func TestRepositoryInsertLimit(t *testing.T) {
+	repo := NewRepository(newTestDB(t))
+
+	newInput := func(id string) DeviceInput {
+		//nolint:exhaustruct // the device info is optional
+		return DeviceInput{ID: id, UserID: "user", AuthToken: id + "-token"}
+	}
+
+	_, err := repo.Insert(t.Context(), newInput("first"), StatusActive, 2)
+	require.NoError(t, err)
+	_, err = repo.Insert(t.Context(), newInput("second"), StatusActive, 2)
+	require.NoError(t, err)
+
+	_, err = repo.Insert(t.Context(), newInput("third"), StatusActive, 2)
+	require.ErrorIs(t, err, ErrLimitReached)
+
+	// 0 is unlimited
+	_, err = repo.Insert(t.Context(), newInput("third"), StatusActive, 0)
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, repo.db.Model((*DeviceModel)(nil)).Where("user_id = ?", "user").Count(&count).Error)
+	require.Equal(t, int64(3), count)
+}
@@ -2,19 +2,31 @@ package devices
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand/v2"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/pubsub"
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 )
 
+const (
+	maxNameLength   = 128
+	maxReasonLength = 256
+)
+
 type Service struct {
 	config Config
 
 	devices *Repository
 	cache   *cache
+	pubsub  pubsub.PubSub
 
 	idGen db.IDGen
 
@@ -24,6 +36,7 @@ type Service struct {
 func NewService(
 	config Config,
 	devices *Repository,
+	pubsub pubsub.PubSub,
 	idGen db.IDGen,
 	logger *zap.Logger,
 ) *Service {
@@ -32,6 +45,7 @@ func NewService(
 
 		devices: devices,
 		cache:   newCache(),
+		pubsub:  pubsub,
 
 		idGen: idGen,
 
@@ -39,7 +53,15 @@ func NewService(
 	}
 }
 
-func (s *Service) Insert(ctx context.Context, userID string, device DeviceInfo) (*Device, error) {
+// Insert registers a new device of the user in the approval state.
+// maxDevices limits the devices of the user, 0 for unlimited.
+func (s *Service) Insert(
+	ctx context.Context,
+	userID string,
+	device DeviceInfo,
+	status Status,
+	maxDevices int,
+) (*Device, error) {
 	input := DeviceInput{
 		DeviceInfo: device,
 		ID:         s.idGen(),
@@ -47,7 +69,7 @@ func (s *Service) Insert(ctx context.Context, userID string, device DeviceInfo)
 		AuthToken:  s.idGen(),
 	}
 
-	return s.devices.Insert(ctx, input)
+	return s.devices.Insert(ctx, input, status, maxDevices)
 }
 
 // Select returns a list of devices for a specific user that match the provided filters.
@@ -78,10 +100,97 @@ func (s *Service) Get(ctx context.Context, userID string, filter ...SelectFilter
 	return s.devices.Get(ctx, filter...)
 }
 
-func (s *Service) GetAny(ctx context.Context, userID string, deviceID string, duration time.Duration) (*Device, error) {
-	filter := []SelectFilter{
-		WithUserID(userID),
+// GetByID returns the device regardless of its owner.
+func (s *Service) GetByID(ctx context.Context, id string) (*Device, error) {
+	return s.devices.Get(ctx, WithID(id))
+}
+
+// GetAny returns a random device of the user matching the provided filters.
+// If deviceID is set, only that device is considered; if duration is positive,
+// only devices active within the duration are considered. Paused devices are
+// skipped, and an explicitly requested paused device yields ErrDevicePaused.
+// Devices pending approval are never considered.
+func (s *Service) GetAny(
+	ctx context.Context,
+	userID string,
+	deviceID string,
+	duration time.Duration,
+	filter ...SelectFilter,
+) (*Device, error) {
+	devices, err := s.selectCandidates(ctx, userID, deviceID, duration, filter...)
+	if err != nil {
+		return nil, err
+	}
+
+	devices = lo.Reject(devices, func(d Device, _ int) bool { return d.IsPaused() })
+	if len(devices) == 0 {
+		return nil, ErrNotFound
+	}
+
+	if len(devices) == 1 {
+		return &devices[0], nil
+	}
+
+	idx := rand.IntN(len(devices)) //nolint:gosec //not critical
+
+	return &devices[idx], nil
+}
+
+// GetAnyWithSim is GetAny for a specific SIM card: it returns the device that
+// currently holds the SIM card together with the card. If several devices
+// report the card, the SIM has been moved and the most recently seen device wins.
+// An unknown SIM card yields ErrSimNotFound.
+func (s *Service) GetAnyWithSim(
+	ctx context.Context,
+	userID string,
+	deviceID string,
+	duration time.Duration,
+	sim SimSelector,
+	filter ...SelectFilter,
+) (*Device, *SimCard, error) {
+	devices, err := s.selectCandidates(ctx, userID, deviceID, duration, filter...)
+	if errors.Is(err, ErrNotFound) && deviceID == "" {
+		return nil, nil, fmt.Errorf("%w: %s", ErrSimNotFound, sim)
+	}
+	if err != nil {
+		return nil, nil, err
 	}
+
+	var (
+		device  *Device
+		simCard *SimCard
+	)
+	for i := range devices {
+		card, ok := sim.find(devices[i].SimCards)
+		if !ok {
+			continue
+		}
+		if device == nil || devices[i].LastSeen.After(device.LastSeen) {
+			device, simCard = &devices[i], &card
+		}
+	}
+
+	if device == nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrSimNotFound, sim)
+	}
+	if device.IsPaused() {
+		return nil, nil, pausedError(*device)
+	}
+
+	return device, simCard, nil
+}
+
+// selectCandidates returns the approved devices matching the filters. An
+// explicitly requested paused or pending device is refused rather than
+// silently replaced.
+func (s *Service) selectCandidates(
+	ctx context.Context,
+	userID string,
+	deviceID string,
+	duration time.Duration,
+	filter ...SelectFilter,
+) ([]Device, error) {
+	filter = append(filter, WithUserID(userID))
 	if deviceID != "" {
 		filter = append(filter, WithID(deviceID))
 	}
@@ -94,17 +203,21 @@ func (s *Service) GetAny(ctx context.Context, userID string, deviceID string, du
 		return nil, err
 	}
 
-	if len(devices) == 0 {
-		return nil, ErrNotFound
+	if deviceID != "" && len(devices) > 0 {
+		if devices[0].IsPendingApproval() {
+			return nil, ErrPending
+		}
+		if devices[0].IsPaused() {
+			return nil, pausedError(devices[0])
+		}
 	}
 
-	if len(devices) == 1 {
-		return &devices[0], nil
+	devices = lo.Reject(devices, func(d Device, _ int) bool { return d.IsPendingApproval() })
+	if len(devices) == 0 {
+		return nil, ErrNotFound
 	}
 
-	idx := rand.IntN(len(devices)) //nolint:gosec //not critical
-
-	return &devices[idx], nil
+	return devices, nil
 }
 
 // GetByToken returns a device by token.
@@ -113,9 +226,15 @@ func (s *Service) GetAny(ctx context.Context, userID string, deviceID string, du
 // does not exist, it returns ErrNotFound.
 func (s *Service) GetByToken(ctx context.Context, token string) (*Device, error) {
 	device, err := s.cache.GetByToken(token)
-	if err == nil {
+	if err == nil && (device.AuthToken == token || device.isPrevToken(token, time.Now())) {
 		return &device, nil
 	}
+	if err == nil {
+		// the grace period of the previous token is over
+		if cacheErr := s.cache.DeleteByToken(token); cacheErr != nil {
+			s.logger.Error("failed to invalidate cache", zap.String("device_id", device.ID), zap.Error(cacheErr))
+		}
+	}
 
 	devicePtr, err := s.devices.Get(ctx, WithToken(token))
 	if err != nil {
@@ -144,6 +263,86 @@ func (s *Service) Update(ctx context.Context, id string, device DeviceUpdate) er
 	return nil
 }
 
+// Patch applies the owner's changes to the device and returns the updated device.
+func (s *Service) Patch(ctx context.Context, userID, id string, patch DevicePatch) (*Device, error) {
+	if patch.Name != nil {
+		patch.Name = lo.ToPtr(strings.TrimSpace(*patch.Name))
+		if utf8.RuneCountInString(*patch.Name) > maxNameLength {
+			return nil, ValidationError(fmt.Sprintf("name must be at most %d characters", maxNameLength))
+		}
+	}
+	if patch.Reason != nil {
+		if patch.Paused == nil || !*patch.Paused {
+			return nil, ValidationError("reason can only be set when pausing the device")
+		}
+		patch.Reason = lo.EmptyableToPtr(strings.TrimSpace(*patch.Reason))
+		if utf8.RuneCountInString(lo.FromPtr(patch.Reason)) > maxReasonLength {
+			return nil, ValidationError(fmt.Sprintf("reason must be at most %d characters", maxReasonLength))
+		}
+	}
+
+	device, err := s.Get(ctx, userID, WithID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if patchErr := s.devices.Patch(ctx, id, patch); patchErr != nil {
+		return nil, patchErr
+	}
+
+	// the paused state is read from the device cached by token on every replica
+	s.Invalidate(*device)
+
+	return s.Get(ctx, userID, WithID(id))
+}
+
+// SetTags replaces the tags of the user's device. Tags are trimmed, deduplicated and sorted.
+func (s *Service) SetTags(ctx context.Context, userID, id string, tags []string) (*Device, error) {
+	prepared, err := prepareTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := s.Get(ctx, userID, WithID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if setErr := s.devices.SetTags(ctx, id, prepared); setErr != nil {
+		return nil, setErr
+	}
+
+	// the group settings and webhooks are resolved from the device cached by token on every replica
+	s.Invalidate(*device)
+
+	device.Tags = prepared
+
+	return device, nil
+}
+
+// Groups returns the groups of the user's devices ordered by name.
+func (s *Service) Groups(ctx context.Context, userID string) ([]Group, error) {
+	items, err := s.Select(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, device := range items {
+		for _, tag := range device.Tags {
+			counts[tag]++
+		}
+	}
+
+	groups := make([]Group, 0, len(counts))
+	for name, count := range counts {
+		groups = append(groups, Group{Name: name, Devices: count})
+	}
+	slices.SortFunc(groups, func(a, b Group) int { return strings.Compare(a.Name, b.Name) })
+
+	return groups, nil
+}
+
 func (s *Service) SetLastSeen(ctx context.Context, batch map[string]time.Time) error {
 	if err := s.devices.SetLastSeenBatch(ctx, batch); err != nil {
 		s.logger.Error("failed to set last seen batch", zap.Error(err))
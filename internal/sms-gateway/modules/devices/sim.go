@@ -0,0 +1,56 @@
+package devices
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SimSelector identifies a SIM card by its phone number or ICCID rather than
+// by its slot, which changes when SIM cards are moved.
+type SimSelector struct {
+	PhoneNumber string
+	ICCID       string
+}
+
+func (s SimSelector) IsEmpty() bool {
+	return s.PhoneNumber == "" && s.ICCID == ""
+}
+
+func (s SimSelector) String() string {
+	if s.ICCID != "" {
+		return fmt.Sprintf("iccid %s", s.ICCID)
+	}
+
+	return fmt.Sprintf("phone number %s", s.PhoneNumber)
+}
+
+// find returns the first SIM card matching the selector. Phone numbers are
+// compared by digits only, as devices report them in different formats.
+func (s SimSelector) find(cards []SimCard) (SimCard, bool) {
+	iccid := strings.TrimSpace(s.ICCID)
+	phone := digitsOnly(s.PhoneNumber)
+
+	for _, card := range cards {
+		if iccid != "" {
+			if card.ICCID != nil && strings.EqualFold(strings.TrimSpace(*card.ICCID), iccid) {
+				return card, true
+			}
+			continue
+		}
+
+		if phone != "" && card.PhoneNumber != nil && digitsOnly(*card.PhoneNumber) == phone {
+			return card, true
+		}
+	}
+
+	return SimCard{}, false
+}
+
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
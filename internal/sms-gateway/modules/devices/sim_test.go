@@ -0,0 +1,43 @@
+//nolint:testpackage // SimSelector.find is unexported; in-package test required.
+package devices
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimSelectorFind(t *testing.T) {
+	cards := []SimCard{
+		{SlotIndex: 0, SimNumber: 1, PhoneNumber: nil, CarrierName: nil, ICCID: lo.ToPtr("8970101000000000001")},
+		{
+			SlotIndex:   1,
+			SimNumber:   2,
+			PhoneNumber: lo.ToPtr("+7 (900) 123-45-67"),
+			CarrierName: nil,
+			ICCID:       lo.ToPtr("8970101000000000002F"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		selector  SimSelector
+		simNumber int
+		found     bool
+	}{
+		{name: "phone number", selector: SimSelector{PhoneNumber: "+79001234567", ICCID: ""}, simNumber: 2, found: true},
+		{name: "iccid", selector: SimSelector{PhoneNumber: "", ICCID: "8970101000000000001"}, simNumber: 1, found: true},
+		{name: "iccid case", selector: SimSelector{PhoneNumber: "", ICCID: "8970101000000000002f"}, simNumber: 2, found: true},
+		{name: "unknown phone number", selector: SimSelector{PhoneNumber: "+79001234568", ICCID: ""}, simNumber: 0, found: false},
+		{name: "unknown iccid", selector: SimSelector{PhoneNumber: "", ICCID: "8970101000000000003"}, simNumber: 0, found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, ok := tt.selector.find(cards)
+			require.Equal(t, tt.found, ok)
+			require.Equal(t, tt.simNumber, card.SimNumber)
+		})
+	}
+}
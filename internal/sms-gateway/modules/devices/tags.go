@@ -0,0 +1,52 @@
+package devices
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const (
+	maxTags      = 16
+	maxTagLength = 32
+)
+
+// tagRegexp limits tags to characters safe in URL paths, as groups are addressed by name.
+var tagRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidateTag checks that the tag can be used as a device group name.
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return ValidationError("tag must not be empty")
+	}
+	if len(tag) > maxTagLength {
+		return ValidationError(fmt.Sprintf("tag %q must be at most %d characters", tag, maxTagLength))
+	}
+	if !tagRegexp.MatchString(tag) {
+		return ValidationError(fmt.Sprintf("tag %q may contain only letters, digits, '_', '.' and '-'", tag))
+	}
+
+	return nil
+}
+
+func prepareTags(tags []string) ([]string, error) {
+	prepared := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if err := ValidateTag(tag); err != nil {
+			return nil, err
+		}
+		prepared = append(prepared, tag)
+	}
+
+	prepared = lo.Uniq(prepared)
+	if len(prepared) > maxTags {
+		return nil, ValidationError(fmt.Sprintf("too many tags, max %d", maxTags))
+	}
+	slices.Sort(prepared)
+
+	return prepared, nil
+}
@@ -0,0 +1,37 @@
+//nolint:testpackage // prepareTags is unexported; in-package test required.
+package devices
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareTags(t *testing.T) {
+	tags, err := prepareTags([]string{" marketing ", "eu-west", "marketing", "A_1.b"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A_1.b", "eu-west", "marketing"}, tags)
+
+	tags, err = prepareTags(nil)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+
+	var validationErr ValidationError
+	for _, invalid := range [][]string{
+		{""},
+		{"with space"},
+		{"slash/tag"},
+		{strings.Repeat("a", maxTagLength+1)},
+	} {
+		_, err = prepareTags(invalid)
+		require.ErrorAs(t, err, &validationErr, "tags %q", invalid)
+	}
+
+	tooMany := make([]string, 0, maxTags+1)
+	for i := range maxTags + 1 {
+		tooMany = append(tooMany, strings.Repeat("t", i+1))
+	}
+	_, err = prepareTags(tooMany)
+	require.ErrorAs(t, err, &validationErr)
+}
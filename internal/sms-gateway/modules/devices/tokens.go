@@ -0,0 +1,148 @@
+package devices
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// tokensTopic broadcasts the replaced tokens, every replica caches devices by token.
+	tokensTopic   = "devices:tokens"
+	pubsubTimeout = 5 * time.Second
+)
+
+type tokensMessage struct {
+	DeviceID string   `json:"deviceId"`
+	Tokens   []string `json:"tokens"`
+}
+
+// RotateToken issues a new auth token for the device authorized with token. The
+// replaced token stays valid for the grace period, and a rotation retried with
+// it during the period returns the current token, so the device doesn't lose
+// access if the response is lost. It returns the updated device and the end of
+// the grace period.
+func (s *Service) RotateToken(ctx context.Context, device Device, token string) (*Device, time.Time, error) {
+	if token != device.AuthToken {
+		return rotatedToken(device, token)
+	}
+
+	newToken := s.idGen()
+	graceUntil := time.Now().Add(s.config.TokenGracePeriod)
+
+	err := s.devices.RotateToken(ctx, device.ID, token, newToken, graceUntil)
+	if errors.Is(err, ErrNotFound) {
+		// rotated concurrently
+		current, getErr := s.devices.Get(ctx, WithID(device.ID))
+		if getErr != nil {
+			return nil, time.Time{}, getErr
+		}
+
+		return rotatedToken(*current, token)
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	s.invalidateTokens(device.ID, token)
+
+	device.PrevAuthToken = &token
+	device.PrevAuthTokenExpiresAt = &graceUntil
+	device.AuthToken = newToken
+
+	return &device, graceUntil, nil
+}
+
+// rotatedToken answers a rotation repeated with the replaced token during its
+// grace period with the current token.
+func rotatedToken(device Device, token string) (*Device, time.Time, error) {
+	if !device.isPrevToken(token, time.Now()) {
+		return nil, time.Time{}, ErrTokenNotCurrent
+	}
+
+	return &device, *device.PrevAuthTokenExpiresAt, nil
+}
+
+// RevokeTokens invalidates the current and the previous tokens of the user's
+// device immediately. The device has to be registered again.
+func (s *Service) RevokeTokens(ctx context.Context, userID, id string) error {
+	device, err := s.devices.Get(ctx, WithUserID(userID), WithID(id))
+	if err != nil {
+		return err
+	}
+
+	if revokeErr := s.devices.RevokeTokens(ctx, device.ID, s.idGen()); revokeErr != nil {
+		return revokeErr
+	}
+
+	s.invalidateTokens(device.ID, device.tokens()...)
+
+	return nil
+}
+
+// Run drops the devices cached by the replaced tokens on every replica.
+func (s *Service) Run(ctx context.Context) error {
+	sub, err := s.pubsub.Subscribe(ctx, tokensTopic)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to pubsub: %w", err)
+	}
+	defer sub.Close()
+
+	ch := sub.Receive()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			message := new(tokensMessage)
+			if jsonErr := json.Unmarshal(msg.Data, message); jsonErr != nil {
+				s.logger.Error("failed to unmarshal tokens message", zap.Error(jsonErr))
+				continue
+			}
+
+			s.dropCached(message.DeviceID, message.Tokens)
+		}
+	}
+}
+
+// Invalidate drops the device cached on every replica, e.g. after it has
+// been changed outside of the module.
+func (s *Service) Invalidate(device Device) {
+	s.invalidateTokens(device.ID, device.tokens()...)
+}
+
+func (s *Service) invalidateTokens(id string, tokens ...string) {
+	s.dropCached(id, tokens)
+
+	data, err := json.Marshal(tokensMessage{DeviceID: id, Tokens: tokens})
+	if err != nil {
+		s.logger.Error("failed to marshal tokens message", zap.String("device_id", id), zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pubsubTimeout)
+	defer cancel()
+
+	if pubErr := s.pubsub.Publish(ctx, tokensTopic, data); pubErr != nil {
+		s.logger.Error("failed to publish tokens message", zap.String("device_id", id), zap.Error(pubErr))
+	}
+}
+
+func (s *Service) dropCached(id string, tokens []string) {
+	errs := []error{s.cache.DeleteByID(id)}
+	for _, token := range tokens {
+		errs = append(errs, s.cache.DeleteByToken(token))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		s.logger.Error("failed to invalidate cache", zap.String("device_id", id), zap.Error(err))
+	}
+}
@@ -0,0 +1,67 @@
+//nolint:testpackage // rotatedToken and the cache are unexported; in-package test required.
+package devices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/server/pkg/pubsub"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRotatedToken(t *testing.T) {
+	graceUntil := time.Now().Add(time.Hour)
+
+	//nolint:exhaustruct // only the tokens are used
+	device := Device{
+		DeviceInput:            DeviceInput{ID: "device", AuthToken: "current"},
+		PrevAuthToken:          lo.ToPtr("previous"),
+		PrevAuthTokenExpiresAt: &graceUntil,
+	}
+
+	rotated, until, err := rotatedToken(device, "previous")
+	require.NoError(t, err)
+	require.Equal(t, "current", rotated.AuthToken)
+	require.Equal(t, graceUntil, until)
+
+	_, _, err = rotatedToken(device, "unknown")
+	require.ErrorIs(t, err, ErrTokenNotCurrent)
+
+	device.PrevAuthTokenExpiresAt = lo.ToPtr(time.Now().Add(-time.Minute))
+	_, _, err = rotatedToken(device, "previous")
+	require.ErrorIs(t, err, ErrTokenNotCurrent)
+}
+
+func TestInvalidateReachesOtherReplicas(t *testing.T) {
+	bus := pubsub.NewMemory()
+	t.Cleanup(func() { _ = bus.Close() })
+
+	//nolint:exhaustruct // the invalidation doesn't use the repository
+	config := Config{}
+	local := NewService(config, nil, bus, nil, zap.NewNop())
+	remote := NewService(config, nil, bus, nil, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+	go func() { _ = remote.Run(ctx) }()
+
+	//nolint:exhaustruct // only the ID and the tokens are cached
+	device := Device{
+		DeviceInput:   DeviceInput{ID: "device", AuthToken: "current"},
+		PrevAuthToken: lo.ToPtr("previous"),
+	}
+	require.NoError(t, remote.cache.Set(device))
+
+	// the remote replica may not have subscribed yet
+	require.Eventually(t, func() bool {
+		local.Invalidate(device)
+
+		_, currentErr := remote.cache.GetByToken("current")
+		_, previousErr := remote.cache.GetByToken("previous")
+		_, idErr := remote.cache.GetByID("device")
+		return currentErr != nil && previousErr != nil && idErr != nil
+	}, time.Second, 10*time.Millisecond)
+}
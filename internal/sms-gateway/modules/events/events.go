@@ -41,12 +41,44 @@ func NewMessagesExportRequestedEvent(
 	)
 }
 
+// PushMessageUpdated asks the device to fetch a pending message again after it has been edited.
+const PushMessageUpdated smsgateway.PushEventType = "MessageUpdated"
+
+func NewMessageUpdatedEvent(messageID string) Event {
+	return NewEvent(PushMessageUpdated, map[string]string{
+		"messageId": messageID,
+	})
+}
+
 func NewMessageCancelledEvent(messageID string) Event {
 	return NewEvent(smsgateway.PushMessageCancelled, map[string]string{
 		"messageId": messageID,
 	})
 }
 
+// PushMessagesCancelled tells the device that several pending messages have
+// been cancelled at once. It is separate from MessageCancelled, which carries a
+// single `messageId` the apps rely on.
+const PushMessagesCancelled smsgateway.PushEventType = "MessagesCancelled"
+
+// NewMessagesCancelledEvent notifies about several cancelled messages at once.
+func NewMessagesCancelledEvent(messageIDs []string) Event {
+	return NewEvent(PushMessagesCancelled, map[string]string{
+		"messageIds": strings.Join(messageIDs, ","),
+	})
+}
+
+// PushDeviceUpdated tells the device that its owner has paused, resumed or renamed it.
+const PushDeviceUpdated smsgateway.PushEventType = "DeviceUpdated"
+
+func NewDeviceUpdatedEvent(paused bool, reason, name string) Event {
+	return NewEvent(PushDeviceUpdated, map[string]string{
+		"paused": strconv.FormatBool(paused),
+		"reason": reason,
+		"name":   name,
+	})
+}
+
 func NewSettingsUpdatedEvent() Event {
 	return NewEvent(smsgateway.PushSettingsUpdated, nil)
 }
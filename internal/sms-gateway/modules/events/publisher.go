@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/pubsub"
+)
+
+// Publisher enqueues events for delivery without delivering them itself.
+// It is intended for processes without device connections, such as the worker;
+// events are delivered by the Service subscribed to the same pubsub.
+type Publisher struct {
+	pubsub pubsub.PubSub
+}
+
+func NewPublisher(pubsub pubsub.PubSub) *Publisher {
+	return &Publisher{
+		pubsub: pubsub,
+	}
+}
+
+func (p *Publisher) Notify(ctx context.Context, userID string, deviceID *string, event Event) error {
+	if event.EventType == "" {
+		return fmt.Errorf("%w: event type is empty", ErrValidationFailed)
+	}
+
+	wrapper := eventWrapper{
+		UserID:   userID,
+		DeviceID: deviceID,
+		Event:    event,
+	}
+
+	wrapperBytes, err := wrapper.serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize event wrapper: %w", err)
+	}
+
+	pubCtx, cancel := context.WithTimeout(ctx, pubsubTimeout)
+	defer cancel()
+
+	if pubErr := p.pubsub.Publish(pubCtx, pubsubTopic, wrapperBytes); pubErr != nil {
+		return fmt.Errorf("failed to publish event: %w", pubErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+const (
+	bulkBatchSize       = 500
+	bulkQueueSize       = 16
+	bulkMaxIDsPerEvent  = 50
+	bulkProgressTimeout = 5 * time.Second
+)
+
+type bulkTask struct {
+	userID  string
+	job     BulkJob
+	filter  SelectFilter
+	updates map[string]any
+	// events builds notifications for a device from the IDs of its updated messages
+	events func(ids []string) []events.Event
+}
+
+// bulkWorker executes bulk jobs one by one in batches.
+type bulkWorker struct {
+	messages  *Repository
+	jobs      *jobCache
+	cache     *stateCache
+	eventsSvc *events.Service
+
+	logger *zap.Logger
+
+	queue chan bulkTask
+}
+
+func newBulkWorker(
+	messages *Repository,
+	jobs *jobCache,
+	cache *stateCache,
+	eventsSvc *events.Service,
+	logger *zap.Logger,
+) *bulkWorker {
+	return &bulkWorker{
+		messages:  messages,
+		jobs:      jobs,
+		cache:     cache,
+		eventsSvc: eventsSvc,
+
+		logger: logger,
+
+		queue: make(chan bulkTask, bulkQueueSize),
+	}
+}
+
+func (w *bulkWorker) Run(ctx context.Context) {
+	w.logger.Info("Starting bulk worker...")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Stopping bulk worker...")
+			w.drain()
+			return
+		case task := <-w.queue:
+			w.process(ctx, task)
+		}
+	}
+}
+
+// Enqueue schedules the task for execution.
+func (w *bulkWorker) Enqueue(task bulkTask) error {
+	select {
+	case w.queue <- task:
+		return nil
+	default:
+		return ErrBulkQueueFull
+	}
+}
+
+func (w *bulkWorker) process(ctx context.Context, task bulkTask) {
+	job := task.job
+	job.Status = BulkJobStatusRunning
+	w.save(task.userID, &job)
+
+	filter := task.filter
+	filter.UserID = task.userID
+
+	total, err := w.messages.countPending(ctx, filter)
+	if err != nil {
+		w.fail(task.userID, &job, err)
+		return
+	}
+	job.Total = total
+	w.save(task.userID, &job)
+
+	updated := map[string][]string{}
+	afterID := uint64(0)
+	for {
+		rows, batchErr := w.messages.updatePendingBatch(ctx, filter, afterID, bulkBatchSize, task.updates)
+		if batchErr != nil {
+			w.fail(task.userID, &job, batchErr)
+			break
+		}
+
+		for _, row := range rows {
+			if cacheErr := w.cache.Delete(ctx, task.userID, row.ExtID); cacheErr != nil {
+				w.logger.Warn("failed to invalidate message cache", zap.String("id", row.ExtID), zap.Error(cacheErr))
+			}
+			updated[row.DeviceID] = append(updated[row.DeviceID], row.ExtID)
+		}
+
+		job.Processed += int64(len(rows))
+		if len(rows) < bulkBatchSize {
+			job.Status = BulkJobStatusCompleted
+			w.save(task.userID, &job)
+			break
+		}
+
+		afterID = rows[len(rows)-1].ID
+		w.save(task.userID, &job)
+	}
+
+	// devices are notified about already updated messages even if the job has failed
+	for deviceID, ids := range updated {
+		for _, event := range task.events(ids) {
+			if ntfErr := w.eventsSvc.Notify(task.userID, &deviceID, event); ntfErr != nil {
+				w.logger.Error(
+					"failed to notify device",
+					zap.String("user_id", task.userID),
+					zap.String("device_id", deviceID),
+					zap.String("job_id", job.ID),
+					zap.Error(ntfErr),
+				)
+			}
+		}
+	}
+}
+
+// drain marks the tasks that haven't been started as failed, so they don't stay queued
+// after the process stops.
+func (w *bulkWorker) drain() {
+	for {
+		select {
+		case task := <-w.queue:
+			job := task.job
+			w.fail(task.userID, &job, ErrBulkJobInterrupted)
+		default:
+			return
+		}
+	}
+}
+
+func (w *bulkWorker) fail(userID string, job *BulkJob, err error) {
+	w.logger.Error("bulk job failed", zap.String("job_id", job.ID), zap.Error(err))
+
+	job.Status = BulkJobStatusFailed
+	job.Error = err.Error()
+	w.save(userID, job)
+}
+
+func (w *bulkWorker) save(userID string, job *BulkJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), bulkProgressTimeout)
+	defer cancel()
+
+	job.UpdatedAt = time.Now()
+	if err := w.jobs.Set(ctx, userID, *job); err != nil {
+		w.logger.Warn("failed to save job progress", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+func cancelledEvents(ids []string) []events.Event {
+	return lo.Map(
+		lo.Chunk(ids, bulkMaxIDsPerEvent),
+		func(chunk []string, _ int) events.Event { return events.NewMessagesCancelledEvent(chunk) },
+	)
+}
+
+func enqueuedEvents(_ []string) []events.Event {
+	return []events.Event{events.NewMessageEnqueuedEvent()}
+}
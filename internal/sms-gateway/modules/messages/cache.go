@@ -80,3 +80,103 @@ func (c *stateCache) Delete(ctx context.Context, userID, id string) error {
 
 	return fmt.Errorf("failed to delete message from cache: %w", err)
 }
+
+const bulkJobTTL = 24 * time.Hour
+
+// jobCache keeps bulk job progress so it can be read from any instance.
+type jobCache struct {
+	storage cacheImpl.Cache
+}
+
+func newJobCache(storage cacheImpl.Cache) *jobCache {
+	return &jobCache{
+		storage: storage,
+	}
+}
+
+func (c *jobCache) Set(ctx context.Context, userID string, job BulkJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+
+	if setErr := c.storage.Set(ctx, "jobs:"+userID+":"+job.ID, data, cacheImpl.WithTTL(bulkJobTTL)); setErr != nil {
+		return fmt.Errorf("failed to set job in cache: %w", setErr)
+	}
+
+	return nil
+}
+
+func (c *jobCache) Get(ctx context.Context, userID, id string) (*BulkJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+
+	data, err := c.storage.Get(ctx, "jobs:"+userID+":"+id)
+	if errors.Is(err, cacheImpl.ErrKeyNotFound) {
+		return nil, ErrBulkJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job from cache: %w", err)
+	}
+
+	job := new(BulkJob)
+	if jsonErr := json.Unmarshal(data, job); jsonErr != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", jsonErr)
+	}
+
+	return job, nil
+}
+
+// dupCache keeps the recipients of recently enqueued messages for duplicate-send suppression.
+type dupCache struct {
+	storage cacheImpl.Cache
+}
+
+func newDupCache(storage cacheImpl.Cache) *dupCache {
+	return &dupCache{
+		storage: storage,
+	}
+}
+
+// Claim stores the message ID under the key for the ttl.
+// If the key is already taken, the stored message ID is returned instead.
+func (c *dupCache) Claim(ctx context.Context, userID, key, id string, ttl time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+
+	key = "dups:" + userID + ":" + key
+
+	// the stored key may expire between the calls, so the claim is retried once
+	for range 2 {
+		err := c.storage.SetOrFail(ctx, key, []byte(id), cacheImpl.WithTTL(ttl))
+		if err == nil {
+			return "", nil
+		}
+		if !errors.Is(err, cacheImpl.ErrKeyExists) {
+			return "", fmt.Errorf("failed to claim duplicate key: %w", err)
+		}
+
+		data, err := c.storage.Get(ctx, key)
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, cacheImpl.ErrKeyNotFound) && !errors.Is(err, cacheImpl.ErrKeyExpired) {
+			return "", fmt.Errorf("failed to get duplicate key: %w", err)
+		}
+	}
+
+	return "", nil
+}
+
+// Release frees the keys claimed for a message that hasn't been stored.
+func (c *dupCache) Release(ctx context.Context, userID string, keys []string) {
+	ctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+
+	for _, key := range keys {
+		_ = c.storage.Delete(ctx, "dups:"+userID+":"+key)
+	}
+}
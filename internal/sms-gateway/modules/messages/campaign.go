@@ -0,0 +1,161 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CampaignMessage is a text message generated for a campaign recipient.
+type CampaignMessage struct {
+	Device      devices.Device
+	PhoneNumber string
+	Text        string
+	ScheduleAt  *time.Time // Delivery slot; the message is paced when set
+}
+
+// ValidateCampaign normalizes the phone numbers in place and checks the campaign
+// text against the content policies as a single message to all the recipients.
+// A campaign can't be quarantined as a whole, so it is rejected unless the action
+// of the violated policies is flag.
+func (s *Service) ValidateCampaign(userID, text string, phoneNumbers []string) error {
+	if err := s.ValidatePhoneNumbers(userID, phoneNumbers); err != nil {
+		return err
+	}
+
+	content, err := s.contentPolicies(userID)
+	if err != nil {
+		return err
+	}
+
+	verdict := evaluateContent(content, ContentSubject{Text: text, PhoneNumbers: phoneNumbers, Encrypted: false})
+	if verdict != nil && verdict.Action != ContentActionFlag {
+		return &ContentRejectedError{Reasons: verdict.Reasons}
+	}
+
+	return nil
+}
+
+// CheckQueue returns ErrQueueLimitExceeded if the device doesn't accept new messages.
+func (s *Service) CheckQueue(ctx context.Context, deviceID string) error {
+	if err := s.limiter.Refresh(ctx, deviceID); err != nil {
+		s.logger.Error("failed to refresh queue stats", zap.String("device_id", deviceID), zap.Error(err))
+	}
+
+	return s.limiter.Check(ctx, deviceID)
+}
+
+// EnqueueCampaign stores the campaign messages within the caller's transaction.
+// The messages pass the same checks as the enqueued ones: queue limits, phone number,
+// normalization, content and duplicate policies of the user. A message suppressed as
+// a duplicate is not stored, the ID of the original message is returned in its place.
+// The IDs are returned in input order. Devices are not notified, the caller is expected
+// to do it once the transaction is committed. The recipients stored are claimed against
+// duplicates before the commit, the caller must call release if the transaction fails.
+func (s *Service) EnqueueCampaign(
+	ctx context.Context,
+	tx *gorm.DB,
+	userID, campaignID string,
+	items []CampaignMessage,
+) ([]string, func(), error) {
+	if len(items) == 0 {
+		return nil, func() {}, nil
+	}
+
+	for _, deviceID := range lo.Uniq(lo.Map(items, func(item CampaignMessage, _ int) string { return item.Device.ID })) {
+		if err := s.CheckQueue(ctx, deviceID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	policy, err := s.phonePolicy(userID, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	normalize, err := s.normalizeOptions(userID, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.contentPolicies(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dupPolicy, err := s.duplicatePolicy(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	//nolint:exhaustruct // campaign messages use the user's defaults
+	opts := EnqueueOptions{Normalize: normalize}
+
+	ids := make([]string, len(items))
+	msgs := make([]*messageModel, 0, len(items))
+	for i, item := range items {
+		//nolint:exhaustruct // campaign messages are plain texts
+		input := MessageInput{
+			MessageContent: MessageContent{TextContent: &TextMessageContent{Text: item.Text}},
+			PhoneNumbers:   []string{item.PhoneNumber},
+			ScheduleAt:     item.ScheduleAt,
+		}
+
+		msg, _, prepErr := s.prepareMessage(item.Device, input, policy, content, opts)
+		if prepErr != nil {
+			return nil, nil, prepErr
+		}
+		msg.CampaignID = &campaignID
+		msg.IsPaced = item.ScheduleAt != nil
+
+		ids[i] = msg.ExtID
+		msgs = append(msgs, msg)
+	}
+
+	// recipients are claimed right before storing so that the claims are released if storing fails
+	var claimed []string
+	if dupPolicy != nil {
+		kept := make([]*messageModel, 0, len(msgs))
+		for i, msg := range msgs {
+			originalID, dupClaimed, dupErr := s.suppressDuplicates(ctx, userID, msg, *dupPolicy)
+			var dupMsgErr *DuplicateMessageError
+			switch {
+			case errors.As(dupErr, &dupMsgErr):
+				// the recipient has already got the text
+				ids[i] = dupMsgErr.OriginalID
+				continue
+			case dupErr != nil:
+				s.dups.Release(ctx, userID, claimed)
+				return nil, nil, dupErr
+			case originalID != "":
+				ids[i] = originalID
+				continue
+			}
+
+			claimed = append(claimed, dupClaimed...)
+			kept = append(kept, msg)
+		}
+		msgs = kept
+	}
+
+	if len(msgs) > 0 {
+		if insErr := NewRepository(tx).insertBatch(ctx, msgs); insErr != nil {
+			s.dups.Release(ctx, userID, claimed)
+			return nil, nil, insErr
+		}
+	}
+
+	for _, msg := range msgs {
+		s.metrics.IncTotal(string(msg.State))
+	}
+
+	return ids, func() {
+		// the transaction may fail because the context is done
+		s.dups.Release(context.WithoutCancel(ctx), userID, claimed)
+	}, nil
+}
@@ -7,6 +7,15 @@ type Config struct {
 	CacheTTL        time.Duration
 
 	Queue QueueConfig
+	Phone PhonePolicy
+
+	// Content is the server-wide content policy applied to all users.
+	Content ContentPolicy
+	// LinksBaseURL is the public URL the short links are served from, link tracking is unavailable when empty.
+	LinksBaseURL string
+
+	// ContentReviewers lists the users allowed to review messages quarantined by the server policy.
+	ContentReviewers []string
 }
 
 type QueueConfig struct {
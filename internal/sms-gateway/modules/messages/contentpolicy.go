@@ -0,0 +1,339 @@
+package messages
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ContentAction is applied to a message violating a content policy.
+type ContentAction string
+
+const (
+	// ContentActionFlag sends the message and records the violations.
+	ContentActionFlag ContentAction = "flag"
+	// ContentActionQuarantine holds the message in the Quarantined state until it is reviewed.
+	ContentActionQuarantine ContentAction = "quarantine"
+	// ContentActionReject fails the enqueue request.
+	ContentActionReject ContentAction = "reject"
+)
+
+// severity orders the actions, the most severe one wins when several policies are violated.
+func (a ContentAction) severity() int {
+	switch a {
+	case ContentActionFlag:
+		return 1
+	case ContentActionQuarantine:
+		return 2 //nolint:mnd // order
+	case ContentActionReject:
+		return 3 //nolint:mnd // order
+	}
+	return 0
+}
+
+// ContentPolicySource tells who configured the policy.
+type ContentPolicySource string
+
+const (
+	// ContentPolicySourceServer is the server-wide policy, its quarantine is reviewed by the server reviewers.
+	ContentPolicySourceServer ContentPolicySource = "server"
+	// ContentPolicySourceUser is the user's own policy, its quarantine is reviewed by the user.
+	ContentPolicySourceUser ContentPolicySource = "user"
+)
+
+// ContentSubject is the part of a message checked by content rules.
+type ContentSubject struct {
+	// Text is the message text, empty for data and encrypted messages.
+	Text string
+	// PhoneNumbers lists the recipients.
+	PhoneNumbers []string
+	// Encrypted is set for an encrypted text, it can't be checked by the text rules.
+	Encrypted bool
+}
+
+// ContentRule checks a message against a single restriction.
+type ContentRule interface {
+	// Check returns the violation or an empty string if the message complies.
+	Check(subject ContentSubject) string
+}
+
+// ContentRules configures a content policy. Empty fields disable the corresponding rules.
+type ContentRules struct {
+	// Keywords are matched as case-insensitive substrings of the text.
+	Keywords []string
+	// Patterns are regular expressions matched against the text.
+	Patterns []string
+	// AllowedDomains limits links to the listed domains and their subdomains.
+	AllowedDomains []string
+	// DeniedDomains rejects links to the listed domains and their subdomains.
+	DeniedDomains []string
+	// MaxLinks limits the number of links in the text.
+	MaxLinks int
+	// MaxRecipients limits the number of recipients.
+	MaxRecipients int
+	// Action is applied when any rule is violated, reject by default.
+	// Encrypted texts can't be checked, so they violate the text rules as a whole.
+	Action ContentAction
+}
+
+// ContentPolicy is a set of rules with the action applied when any of them is violated.
+type ContentPolicy struct {
+	Source ContentPolicySource
+	Action ContentAction
+	Rules  []ContentRule
+
+	// checksText is set when any rule checks the text.
+	checksText bool
+}
+
+// NewContentPolicy validates the rules and builds the policy.
+func NewContentPolicy(source ContentPolicySource, rules ContentRules) (ContentPolicy, error) {
+	policy := ContentPolicy{
+		Source: source,
+		Action: rules.Action,
+		Rules:  []ContentRule{},
+
+		checksText: false,
+	}
+
+	switch policy.Action {
+	case "":
+		policy.Action = ContentActionReject
+	case ContentActionFlag, ContentActionQuarantine, ContentActionReject:
+	default:
+		return policy, ValidationError(fmt.Sprintf("unknown content action %q", rules.Action))
+	}
+
+	if rules.MaxLinks < 0 || rules.MaxRecipients < 0 {
+		return policy, ValidationError("content limits must not be negative")
+	}
+
+	if keywords := cleanContentValues(rules.Keywords); len(keywords) > 0 {
+		policy.Rules = append(policy.Rules, keywordsRule(keywords))
+	}
+
+	if len(rules.Patterns) > 0 {
+		patterns := make(patternsRule, 0, len(rules.Patterns))
+		for _, p := range rules.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return policy, ValidationError(fmt.Sprintf("invalid content pattern %q: %s", p, err))
+			}
+			patterns = append(patterns, re)
+		}
+		policy.Rules = append(policy.Rules, patterns)
+	}
+
+	if domains := cleanDomains(rules.AllowedDomains); len(domains) > 0 {
+		policy.Rules = append(policy.Rules, allowedDomainsRule(domains))
+	}
+	if domains := cleanDomains(rules.DeniedDomains); len(domains) > 0 {
+		policy.Rules = append(policy.Rules, deniedDomainsRule(domains))
+	}
+
+	if rules.MaxLinks > 0 {
+		policy.Rules = append(policy.Rules, maxLinksRule(rules.MaxLinks))
+	}
+	policy.checksText = len(policy.Rules) > 0
+
+	if rules.MaxRecipients > 0 {
+		policy.Rules = append(policy.Rules, maxRecipientsRule(rules.MaxRecipients))
+	}
+
+	return policy, nil
+}
+
+// Enabled returns true if the policy has at least one rule.
+func (p ContentPolicy) Enabled() bool {
+	return len(p.Rules) > 0
+}
+
+// Evaluate returns the violations of the policy rules.
+func (p ContentPolicy) Evaluate(subject ContentSubject) []string {
+	violations := []string{}
+	if subject.Encrypted && p.checksText {
+		violations = append(violations, "encrypted content can't be checked by the text rules")
+	}
+
+	for _, rule := range p.Rules {
+		if reason := rule.Check(subject); reason != "" {
+			violations = append(violations, reason)
+		}
+	}
+
+	return violations
+}
+
+// ContentVerdict is the result of the content policy checks stored with the message.
+type ContentVerdict struct {
+	// Action is the most severe action of the violated policies.
+	Action ContentAction `json:"action"`
+	// Source is the origin of the policy that determined the action.
+	Source ContentPolicySource `json:"source"`
+	// Reasons lists the violations of all policies.
+	Reasons []string `json:"reasons"`
+}
+
+// evaluateContent checks the subject against the policies and returns nil if no rule is violated.
+func evaluateContent(policies []ContentPolicy, subject ContentSubject) *ContentVerdict {
+	var verdict *ContentVerdict
+	for _, policy := range policies {
+		reasons := policy.Evaluate(subject)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		if verdict == nil {
+			verdict = &ContentVerdict{Action: policy.Action, Source: policy.Source, Reasons: reasons}
+			continue
+		}
+
+		verdict.Reasons = append(verdict.Reasons, reasons...)
+		if policy.Action.severity() > verdict.Action.severity() {
+			verdict.Action = policy.Action
+			verdict.Source = policy.Source
+		}
+	}
+
+	return verdict
+}
+
+// ContentRejectedError is returned when a message violates a content policy with the reject action.
+type ContentRejectedError struct {
+	Reasons []string
+}
+
+func (e *ContentRejectedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrContentRejected, strings.Join(e.Reasons, "; "))
+}
+
+func (e *ContentRejectedError) Unwrap() error {
+	return ErrContentRejected
+}
+
+type keywordsRule []string
+
+func (r keywordsRule) Check(subject ContentSubject) string {
+	text := strings.ToLower(subject.Text)
+	for _, keyword := range r {
+		if strings.Contains(text, keyword) {
+			return fmt.Sprintf("contains blocked keyword %q", keyword)
+		}
+	}
+
+	return ""
+}
+
+type patternsRule []*regexp.Regexp
+
+func (r patternsRule) Check(subject ContentSubject) string {
+	for _, re := range r {
+		if re.MatchString(subject.Text) {
+			return fmt.Sprintf("matches blocked pattern %q", re.String())
+		}
+	}
+
+	return ""
+}
+
+type allowedDomainsRule []string
+
+func (r allowedDomainsRule) Check(subject ContentSubject) string {
+	for _, host := range extractLinkHosts(subject.Text) {
+		if !matchesDomain(host, r) {
+			return fmt.Sprintf("links to domain %q that is not allowed", host)
+		}
+	}
+
+	return ""
+}
+
+type deniedDomainsRule []string
+
+func (r deniedDomainsRule) Check(subject ContentSubject) string {
+	for _, host := range extractLinkHosts(subject.Text) {
+		if matchesDomain(host, r) {
+			return fmt.Sprintf("links to denied domain %q", host)
+		}
+	}
+
+	return ""
+}
+
+type maxLinksRule int
+
+func (r maxLinksRule) Check(subject ContentSubject) string {
+	if count := len(linkRegexp.FindAllString(subject.Text, -1)); count > int(r) {
+		return fmt.Sprintf("contains %d links, at most %d allowed", count, r)
+	}
+
+	return ""
+}
+
+type maxRecipientsRule int
+
+func (r maxRecipientsRule) Check(subject ContentSubject) string {
+	if count := len(subject.PhoneNumbers); count > int(r) {
+		return fmt.Sprintf("has %d recipients, at most %d allowed", count, r)
+	}
+
+	return ""
+}
+
+// linkRegexp matches URLs with a scheme, addresses starting with www. and bare
+// domains followed by a path.
+var linkRegexp = regexp.MustCompile( //nolint:gochecknoglobals // compiled once
+	`(?i)\b[a-z][a-z0-9+.-]*://[^\s<>"']+|\bwww\.[^\s<>"']+|\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}/[^\s<>"']*`,
+)
+
+// extractLinkHosts returns the lowercase host names of the links in the text.
+func extractLinkHosts(text string) []string {
+	links := linkRegexp.FindAllString(text, -1)
+	hosts := make([]string, 0, len(links))
+	for _, link := range links {
+		if i := strings.Index(link, "://"); i >= 0 {
+			link = link[i+len("://"):]
+		}
+		if i := strings.IndexAny(link, "/?#"); i >= 0 {
+			link = link[:i]
+		}
+		if i := strings.LastIndex(link, "@"); i >= 0 {
+			link = link[i+1:]
+		}
+		if i := strings.LastIndex(link, ":"); i >= 0 {
+			link = link[:i]
+		}
+
+		if host := strings.TrimSuffix(strings.ToLower(link), "."); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// matchesDomain returns true if the host is one of the domains or their subdomain.
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func cleanContentValues(values []string) []string {
+	return lo.Uniq(lo.FilterMap(values, func(item string, _ int) (string, bool) {
+		item = strings.ToLower(strings.TrimSpace(item))
+		return item, item != ""
+	}))
+}
+
+func cleanDomains(domains []string) []string {
+	return cleanContentValues(lo.Map(domains, func(item string, _ int) string {
+		return strings.Trim(strings.TrimSpace(item), ".")
+	}))
+}
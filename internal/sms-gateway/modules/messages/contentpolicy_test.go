@@ -0,0 +1,190 @@
+//nolint:testpackage // content policy helpers are unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentPolicyEvaluate(t *testing.T) {
+	policy, err := NewContentPolicy(ContentPolicySourceServer, ContentRules{
+		Keywords:       []string{" Casino ", ""},
+		Patterns:       []string{`\b\d{4} \d{4} \d{4} \d{4}\b`},
+		AllowedDomains: nil,
+		DeniedDomains:  []string{".bad.example."},
+		MaxLinks:       2,
+		MaxRecipients:  2,
+		Action:         ContentActionQuarantine,
+	})
+	require.NoError(t, err)
+	require.True(t, policy.Enabled())
+
+	tests := []struct {
+		name    string
+		subject ContentSubject
+		want    []string
+	}{
+		{
+			name:    "clean",
+			subject: ContentSubject{Text: "Your code is 1234", PhoneNumbers: []string{"+79000000001"}, Encrypted: false},
+			want:    []string{},
+		},
+		{
+			name:    "keyword",
+			subject: ContentSubject{Text: "Best CASINO in town", PhoneNumbers: nil, Encrypted: false},
+			want:    []string{`contains blocked keyword "casino"`},
+		},
+		{
+			name:    "pattern",
+			subject: ContentSubject{Text: "Card 1234 5678 9012 3456", PhoneNumbers: nil, Encrypted: false},
+			want:    []string{`matches blocked pattern "\\b\\d{4} \\d{4} \\d{4} \\d{4}\\b"`},
+		},
+		{
+			name:    "denied subdomain",
+			subject: ContentSubject{Text: "Visit https://www.bad.example/login", PhoneNumbers: nil, Encrypted: false},
+			want:    []string{`links to denied domain "www.bad.example"`},
+		},
+		{
+			name:    "similar domain is not denied",
+			subject: ContentSubject{Text: "Visit https://notbad.example/login", PhoneNumbers: nil, Encrypted: false},
+			want:    []string{},
+		},
+		{
+			name:    "links and recipients",
+			subject: ContentSubject{Text: "a.example/1 www.b.example http://c.example", PhoneNumbers: []string{"1", "2", "3"}, Encrypted: false},
+			want:    []string{"contains 3 links, at most 2 allowed", "has 3 recipients, at most 2 allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, policy.Evaluate(tt.subject))
+		})
+	}
+}
+
+func TestContentPolicyAllowedDomains(t *testing.T) {
+	policy, err := NewContentPolicy(ContentPolicySourceUser, ContentRules{
+		Keywords:       nil,
+		Patterns:       nil,
+		AllowedDomains: []string{"Example.com"},
+		DeniedDomains:  nil,
+		MaxLinks:       0,
+		MaxRecipients:  0,
+		Action:         "",
+	})
+	require.NoError(t, err)
+	require.Equal(t, ContentActionReject, policy.Action)
+
+	require.Empty(t, policy.Evaluate(ContentSubject{Text: "See https://user@shop.example.com:8080/a?b", PhoneNumbers: nil, Encrypted: false}))
+	require.Equal(t,
+		[]string{`links to domain "evil.com" that is not allowed`},
+		policy.Evaluate(ContentSubject{Text: "See example.com/a and HTTPS://Evil.com.", PhoneNumbers: nil, Encrypted: false}),
+	)
+}
+
+func TestNewContentPolicyInvalid(t *testing.T) {
+	_, err := NewContentPolicy(ContentPolicySourceUser, ContentRules{
+		Keywords:       nil,
+		Patterns:       []string{"("},
+		AllowedDomains: nil,
+		DeniedDomains:  nil,
+		MaxLinks:       0,
+		MaxRecipients:  0,
+		Action:         "",
+	})
+	require.ErrorAs(t, err, new(ValidationError))
+
+	//nolint:exhaustruct // only the action is relevant
+	_, err = NewContentPolicy(ContentPolicySourceUser, ContentRules{Action: "block"})
+	require.ErrorAs(t, err, new(ValidationError))
+
+	//nolint:exhaustruct // only the limits are relevant
+	_, err = NewContentPolicy(ContentPolicySourceUser, ContentRules{MaxLinks: -1})
+	require.ErrorAs(t, err, new(ValidationError))
+}
+
+func TestEvaluateContent(t *testing.T) {
+	//nolint:exhaustruct // only keywords are relevant
+	server, err := NewContentPolicy(ContentPolicySourceServer, ContentRules{
+		Keywords: []string{"loan"},
+		Action:   ContentActionQuarantine,
+	})
+	require.NoError(t, err)
+	//nolint:exhaustruct // only keywords are relevant
+	user, err := NewContentPolicy(ContentPolicySourceUser, ContentRules{
+		Keywords: []string{"loan", "promo"},
+		Action:   ContentActionFlag,
+	})
+	require.NoError(t, err)
+
+	policies := []ContentPolicy{server, user}
+
+	require.Nil(t, evaluateContent(policies, ContentSubject{Text: "hello", PhoneNumbers: nil, Encrypted: false}))
+
+	require.Equal(t,
+		&ContentVerdict{
+			Action:  ContentActionFlag,
+			Source:  ContentPolicySourceUser,
+			Reasons: []string{`contains blocked keyword "promo"`},
+		},
+		evaluateContent(policies, ContentSubject{Text: "promo", PhoneNumbers: nil, Encrypted: false}),
+	)
+
+	verdict := evaluateContent(policies, ContentSubject{Text: "cheap loan", PhoneNumbers: nil, Encrypted: false})
+	require.Equal(t, ContentActionQuarantine, verdict.Action)
+	require.Equal(t, ContentPolicySourceServer, verdict.Source)
+	require.Len(t, verdict.Reasons, 2)
+}
+
+func TestSetContentVerdictQuarantine(t *testing.T) {
+	msg := newMessageModel("id", "device", []string{"+79000000001"}, 0, nil, nil, nil, true, false)
+
+	require.NoError(t, msg.SetContentVerdict(&ContentVerdict{
+		Action:  ContentActionQuarantine,
+		Source:  ContentPolicySourceUser,
+		Reasons: []string{"reason"},
+	}))
+	require.Equal(t, ProcessingStateQuarantined, msg.State)
+	require.Equal(t, ProcessingStateQuarantined, msg.States[0].State)
+	require.Equal(t, ProcessingStateQuarantined, msg.Recipients[0].State)
+	require.Equal(t, ProcessingStateQuarantined, msg.Recipients[0].States[0].State)
+
+	verdict, err := msg.GetContentVerdict()
+	require.NoError(t, err)
+	require.Equal(t, []string{"reason"}, verdict.Reasons)
+
+	require.Equal(t, StateUpdateApplied, checkTransition(ProcessingStateQuarantined, ProcessingStatePending))
+	require.Equal(t, StateUpdateRejected, checkTransition(ProcessingStateQuarantined, ProcessingStateSent))
+}
+
+func TestContentPolicyEncrypted(t *testing.T) {
+	//nolint:exhaustruct // only keywords are relevant
+	text, err := NewContentPolicy(ContentPolicySourceServer, ContentRules{
+		Keywords: []string{"loan"},
+		Action:   ContentActionQuarantine,
+	})
+	require.NoError(t, err)
+	//nolint:exhaustruct // only the recipients limit is relevant
+	recipients, err := NewContentPolicy(ContentPolicySourceUser, ContentRules{
+		MaxRecipients: 1,
+	})
+	require.NoError(t, err)
+
+	encrypted := ContentSubject{Text: "", PhoneNumbers: []string{"+79000000001"}, Encrypted: true}
+
+	require.Equal(t,
+		&ContentVerdict{
+			Action:  ContentActionQuarantine,
+			Source:  ContentPolicySourceServer,
+			Reasons: []string{"encrypted content can't be checked by the text rules"},
+		},
+		evaluateContent([]ContentPolicy{text, recipients}, encrypted),
+	)
+
+	// the recipients are checked as usual
+	require.Nil(t, evaluateContent([]ContentPolicy{recipients}, encrypted))
+	encrypted.PhoneNumbers = append(encrypted.PhoneNumbers, "+79000000002")
+	require.Len(t, recipients.Evaluate(encrypted), 1)
+}
@@ -10,9 +10,25 @@ type TextMessageContent = smsgateway.TextMessage
 type DataMessageContent = smsgateway.DataMessage
 type HashedMessageContent = smsgateway.HashedMessage
 
+// MmsAttachment describes an uploaded file sent with an MMS message.
+type MmsAttachment struct {
+	ID          string `json:"id"`          // Attachment ID
+	Name        string `json:"name"`        // File name
+	ContentType string `json:"contentType"` // MIME type
+	Size        int64  `json:"size"`        // Size in bytes
+}
+
+// MmsMessageContent is the content of an MMS message. The text is always
+// stored, even when empty, so that the message can be hashed.
+type MmsMessageContent struct {
+	Text        string          `json:"text"`
+	Attachments []MmsAttachment `json:"attachments"`
+}
+
 type MessageContent struct {
 	TextContent *TextMessageContent `json:"textContent,omitempty"`
 	DataContent *DataMessageContent `json:"dataContent,omitempty"`
+	MmsContent  *MmsMessageContent  `json:"mmsContent,omitempty"`
 }
 
 type MessageStateContent struct {
@@ -37,6 +53,24 @@ type MessageInput struct {
 	Priority           smsgateway.MessagePriority
 }
 
+// MessageUpdate lists changes to a pending message. Nil fields are left unchanged.
+type MessageUpdate struct {
+	TextContent *TextMessageContent
+
+	PhoneNumbers []string
+
+	SimNumber  *uint8
+	ValidUntil *time.Time
+	ScheduleAt *time.Time
+	Priority   *smsgateway.MessagePriority
+}
+
+// MessageEdit records the fields changed by an edit of a pending message.
+type MessageEdit struct {
+	Fields   []string  `json:"fields"`   // Names of the changed fields as in the API
+	EditedAt time.Time `json:"editedAt"` // Time of the edit
+}
+
 type Message struct {
 	MessageInput
 
@@ -58,4 +92,54 @@ type MessageState struct {
 	DeviceID    string `json:"deviceId"`    // Device ID
 	IsHashed    bool   `json:"isHashed"`    // Hashed
 	IsEncrypted bool   `json:"isEncrypted"` // Encrypted
+
+	RecipientsStates map[string]map[string]time.Time `json:"recipientsStates"` // History of states by recipient phone number
+	Edits            []MessageEdit                   `json:"edits,omitempty"`  // Edits made while the message was pending
+
+	ContentVerdict *ContentVerdict `json:"contentVerdict,omitempty"` // Content policy violations
+
+	Normalization *NormalizationReport `json:"-"` // Text changes made on enqueue, not stored
+}
+
+// DeviceRef identifies a device together with its owner.
+type DeviceRef struct {
+	DeviceID string // Device ID
+	UserID   string // Owner of the device
+}
+
+// ExpiredMessage identifies a message failed by the server after its ValidUntil has passed.
+type ExpiredMessage struct {
+	ID       string // Message ID
+	DeviceID string // Device ID
+	UserID   string // Owner of the device
+}
+
+// BulkAction is an operation applied to all pending messages matching a filter.
+type BulkAction string
+
+const (
+	BulkActionCancel     BulkAction = "cancel"
+	BulkActionReschedule BulkAction = "reschedule"
+)
+
+// BulkJobStatus is the execution status of a bulk operation.
+type BulkJobStatus string
+
+const (
+	BulkJobStatusQueued    BulkJobStatus = "queued"
+	BulkJobStatusRunning   BulkJobStatus = "running"
+	BulkJobStatusCompleted BulkJobStatus = "completed"
+	BulkJobStatusFailed    BulkJobStatus = "failed"
+)
+
+// BulkJob describes the progress of a bulk operation.
+type BulkJob struct {
+	ID        string        `json:"id"`              // Job ID
+	Action    BulkAction    `json:"action"`          // Operation
+	Status    BulkJobStatus `json:"status"`          // Execution status
+	Total     int64         `json:"total"`           // Number of matching pending messages at start
+	Processed int64         `json:"processed"`       // Number of updated messages
+	Error     string        `json:"error,omitempty"` // Failure reason
+	CreatedAt time.Time     `json:"createdAt"`       // Creation time
+	UpdatedAt time.Time     `json:"updatedAt"`       // Last progress update time
 }
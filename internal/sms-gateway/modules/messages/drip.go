@@ -0,0 +1,129 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
+)
+
+// DripOptions spreads messages over time instead of handing them to devices at once.
+// The server assigns each message a delivery slot and SelectPending returns it only
+// after the slot has arrived.
+type DripOptions struct {
+	// Window spreads the messages of a request evenly over the duration.
+	Window time.Duration
+	// Interval is the minimal gap between paced messages of the same device or SIM.
+	Interval time.Duration
+	// PerSIM applies Interval to each SIM separately instead of the whole device.
+	PerSIM bool
+	// Location is the timezone of the device work hours. UTC is used when nil.
+	Location *time.Location
+}
+
+func (o DripOptions) Enabled() bool {
+	return o.Window > 0 || o.Interval > 0
+}
+
+type dripKey struct {
+	deviceID  string
+	simNumber uint8
+}
+
+// assignSlots sets ScheduleAt of the messages to their delivery slots and marks them as paced.
+// The previous slots are read from repo, which holds the locks of the devices. The
+// work hours of every device are resolved with the settings of its groups.
+func (s *Service) assignSlots(
+	ctx context.Context,
+	repo *Repository,
+	userID string,
+	deviceTags map[string][]string,
+	msgs []*messageModel,
+	opts DripOptions,
+) error {
+	groups := map[dripKey][]*messageModel{}
+	keys := []dripKey{}
+	for _, msg := range msgs {
+		key := dripKey{deviceID: msg.DeviceID, simNumber: 0}
+		if opts.PerSIM && msg.SimNumber != nil {
+			key.simNumber = *msg.SimNumber
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], msg)
+	}
+
+	workHours := map[string]*settings.WorkHours{}
+	now := time.Now()
+	for _, key := range keys {
+		hours, ok := workHours[key.deviceID]
+		if !ok {
+			var err error
+			if hours, err = s.settingsSvc.GetWorkHours(userID, deviceTags[key.deviceID]); err != nil {
+				return fmt.Errorf("failed to get work hours: %w", err)
+			}
+			workHours[key.deviceID] = hours
+		}
+
+		var simNumber *uint8
+		if opts.PerSIM {
+			simNumber = &key.simNumber
+		}
+
+		last, lastErr := repo.lastPacedSlot(ctx, key.deviceID, simNumber)
+		if lastErr != nil {
+			return lastErr
+		}
+
+		planSlots(groups[key], last, now, opts, hours)
+	}
+
+	return nil
+}
+
+// planSlots assigns delivery slots to a group of messages sharing a device or SIM.
+// Slots start at the later of now and the previous slot of the group plus the interval,
+// are spread evenly over the window, keep at least the interval between each other,
+// never precede the requested ScheduleAt and are moved into the work hours.
+func planSlots(
+	msgs []*messageModel,
+	last *time.Time,
+	now time.Time,
+	opts DripOptions,
+	workHours *settings.WorkHours,
+) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	start := now
+	if last != nil && last.Add(opts.Interval).After(start) {
+		start = last.Add(opts.Interval)
+	}
+
+	var prev *time.Time
+	for i, msg := range msgs {
+		slot := start.Add(time.Duration(int64(opts.Window) * int64(i) / int64(len(msgs))))
+		if msg.ScheduleAt != nil && msg.ScheduleAt.After(slot) {
+			slot = *msg.ScheduleAt
+		}
+		if prev != nil && slot.Before(prev.Add(opts.Interval)) {
+			slot = prev.Add(opts.Interval)
+		}
+		if workHours != nil {
+			slot = workHours.Next(slot, loc)
+		}
+
+		// schedule_at is stored with second precision
+		if truncated := slot.Truncate(time.Second); !truncated.Equal(slot) {
+			slot = truncated.Add(time.Second)
+		}
+
+		msg.ScheduleAt = &slot
+		msg.IsPaced = true
+		prev = &slot
+	}
+}
@@ -0,0 +1,74 @@
+//nolint:testpackage // slot planning is unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSlots(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	newMessages := func(n int) []*messageModel {
+		msgs := make([]*messageModel, n)
+		for i := range msgs {
+			msgs[i] = new(messageModel)
+		}
+		return msgs
+	}
+	slots := func(msgs []*messageModel) []time.Time {
+		result := make([]time.Time, len(msgs))
+		for i, msg := range msgs {
+			require.True(t, msg.IsPaced)
+			result[i] = *msg.ScheduleAt
+		}
+		return result
+	}
+
+	t.Run("window", func(t *testing.T) {
+		msgs := newMessages(4)
+		planSlots(msgs, nil, now, DripOptions{Window: time.Hour}, nil)
+		require.Equal(t, []time.Time{
+			now,
+			now.Add(15 * time.Minute),
+			now.Add(30 * time.Minute),
+			now.Add(45 * time.Minute),
+		}, slots(msgs))
+	})
+
+	t.Run("interval after previous slot", func(t *testing.T) {
+		msgs := newMessages(2)
+		last := now.Add(5 * time.Minute)
+		planSlots(msgs, &last, now, DripOptions{Interval: time.Minute}, nil)
+		require.Equal(t, []time.Time{
+			now.Add(6 * time.Minute),
+			now.Add(7 * time.Minute),
+		}, slots(msgs))
+	})
+
+	t.Run("requested schedule is kept", func(t *testing.T) {
+		msgs := newMessages(1)
+		scheduleAt := now.Add(2 * time.Hour)
+		msgs[0].ScheduleAt = &scheduleAt
+		planSlots(msgs, nil, now, DripOptions{Interval: time.Minute}, nil)
+		require.Equal(t, []time.Time{scheduleAt}, slots(msgs))
+	})
+
+	t.Run("work hours", func(t *testing.T) {
+		msgs := newMessages(2)
+		hours := &settings.WorkHours{Start: 9 * time.Hour, End: 10*time.Hour + 30*time.Minute}
+		planSlots(msgs, nil, now, DripOptions{Window: 2 * time.Hour}, hours)
+		require.Equal(t, []time.Time{
+			now,
+			now.Add(23 * time.Hour),
+		}, slots(msgs))
+	})
+
+	t.Run("sub-second slots are rounded up", func(t *testing.T) {
+		msgs := newMessages(1)
+		planSlots(msgs, nil, now.Add(time.Millisecond), DripOptions{Interval: time.Second}, nil)
+		require.Equal(t, []time.Time{now.Add(time.Second)}, slots(msgs))
+	})
+}
@@ -0,0 +1,175 @@
+package messages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// DuplicateAction is applied to a message sent again within the suppression window.
+type DuplicateAction string
+
+const (
+	// DuplicateActionReject fails the enqueue request.
+	DuplicateActionReject DuplicateAction = "reject"
+	// DuplicateActionMerge drops the repeated recipients and returns the original
+	// message if none are left.
+	DuplicateActionMerge DuplicateAction = "merge"
+)
+
+// maxDuplicatesWindow limits the time the recipient hashes are kept in the cache.
+const maxDuplicatesWindow = 7 * 24 * time.Hour
+
+// DuplicatePolicy configures duplicate-send suppression.
+type DuplicatePolicy struct {
+	Window time.Duration
+	Action DuplicateAction
+}
+
+// DuplicateMessageError is returned when a message repeats the one sent within the suppression window.
+type DuplicateMessageError struct {
+	OriginalID string
+}
+
+func (e *DuplicateMessageError) Error() string {
+	return fmt.Sprintf("%s: original message %s", ErrDuplicateMessage, e.OriginalID)
+}
+
+func (e *DuplicateMessageError) Unwrap() error {
+	return ErrDuplicateMessage
+}
+
+// duplicatePolicy returns the user's duplicate-send suppression policy or nil if it is disabled.
+func (s *Service) duplicatePolicy(userID string) (*DuplicatePolicy, error) {
+	cfg, err := s.settingsSvc.GetDuplicates(userID)
+	if err != nil {
+		if errors.Is(err, settings.ErrInvalidField) {
+			return nil, ValidationError(fmt.Sprintf("invalid duplicates settings: %s", err))
+		}
+		return nil, fmt.Errorf("failed to get duplicates settings: %w", err)
+	}
+	if cfg == nil {
+		return nil, nil //nolint:nilnil // disabled
+	}
+
+	policy := &DuplicatePolicy{
+		Window: min(cfg.Window, maxDuplicatesWindow),
+		Action: DuplicateAction(cfg.Action),
+	}
+	switch policy.Action {
+	case "":
+		policy.Action = DuplicateActionReject
+	case DuplicateActionReject, DuplicateActionMerge:
+	default:
+		return nil, ValidationError(fmt.Sprintf("invalid duplicates settings: unknown action %q", cfg.Action))
+	}
+
+	return policy, nil
+}
+
+// suppressDuplicates claims the recipients of the message for the suppression window
+// and returns the claimed keys. When no recipients are left after merging, the ID of
+// the original message is returned and the message must not be stored.
+func (s *Service) suppressDuplicates(
+	ctx context.Context,
+	userID string,
+	msg *messageModel,
+	policy DuplicatePolicy,
+) (string, []string, error) {
+	claimed := make([]string, 0, len(msg.Recipients))
+	duplicates := map[string]string{}
+
+	for _, recipient := range msg.Recipients {
+		key := duplicateKey(msg, recipient.PhoneNumber)
+
+		originalID, err := s.dups.Claim(ctx, userID, key, msg.ExtID, policy.Window)
+		if err != nil {
+			// suppression is best-effort and must not block sending
+			s.logger.Warn("failed to check duplicates", zap.String("id", msg.ExtID), zap.Error(err))
+			continue
+		}
+
+		if originalID == "" {
+			claimed = append(claimed, key)
+			continue
+		}
+		if originalID == msg.ExtID {
+			// the same message is enqueued again, insert reports the conflict
+			continue
+		}
+		duplicates[recipient.PhoneNumber] = originalID
+	}
+
+	if len(duplicates) == 0 {
+		return "", claimed, nil
+	}
+
+	// report the original of the first repeated recipient
+	var originalID string
+	for _, recipient := range msg.Recipients {
+		if id, ok := duplicates[recipient.PhoneNumber]; ok {
+			originalID = id
+			break
+		}
+	}
+
+	if policy.Action == DuplicateActionReject {
+		s.dups.Release(ctx, userID, claimed)
+		return "", nil, &DuplicateMessageError{OriginalID: originalID}
+	}
+
+	msg.Recipients = lo.Filter(msg.Recipients, func(item messageRecipientModel, _ int) bool {
+		_, ok := duplicates[item.PhoneNumber]
+		return !ok
+	})
+	if len(msg.Recipients) == 0 {
+		return originalID, nil, nil
+	}
+
+	return "", claimed, nil
+}
+
+// duplicateKey identifies the content of the message sent to the phone number.
+func duplicateKey(msg *messageModel, phoneNumber string) string {
+	hash := sha256.New()
+	hash.Write([]byte(msg.Type))
+	hash.Write([]byte{0})
+	hash.Write([]byte(lo.CoalesceOrEmpty(msg.untrackedContent, msg.Content)))
+	hash.Write([]byte{0})
+	hash.Write([]byte(phoneNumber))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// suppressBatchDuplicates applies suppressDuplicates to each message of the batch in
+// order, so a message may also repeat an earlier one of the same batch. It returns
+// the IDs of the originals of the merged messages, empty for the messages to store,
+// and the claimed keys. All the claims are released if any message is rejected.
+func (s *Service) suppressBatchDuplicates(
+	ctx context.Context,
+	userID string,
+	msgs []*messageModel,
+	policy DuplicatePolicy,
+) ([]string, []string, error) {
+	originals := make([]string, len(msgs))
+	var claimed []string
+	for i, msg := range msgs {
+		originalID, msgClaimed, err := s.suppressDuplicates(ctx, userID, msg, policy)
+		if err != nil {
+			s.dups.Release(ctx, userID, claimed)
+			return nil, nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+
+		originals[i] = originalID
+		claimed = append(claimed, msgClaimed...)
+	}
+
+	return originals, claimed, nil
+}
@@ -0,0 +1,124 @@
+//nolint:testpackage // duplicate suppression helpers are unexported; in-package test required.
+package messages
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/cachefx/cache"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newDuplicatesTestService() *Service {
+	//nolint:exhaustruct // only the duplicate suppression dependencies are needed
+	return &Service{
+		dups:   newDupCache(cache.NewMemory(time.Hour)),
+		logger: zap.NewNop(),
+	}
+}
+
+func newDuplicatesTestMessage(t *testing.T, id, text string, phoneNumbers ...string) *messageModel {
+	t.Helper()
+
+	msg := newMessageModel(id, "device", phoneNumbers, 0, nil, nil, nil, true, false)
+	require.NoError(t, msg.SetTextContent(TextMessageContent{Text: text}))
+
+	return msg
+}
+
+func TestSuppressDuplicatesReject(t *testing.T) {
+	ctx := context.Background()
+	svc := newDuplicatesTestService()
+	policy := DuplicatePolicy{Window: time.Minute, Action: DuplicateActionReject}
+
+	first := newDuplicatesTestMessage(t, "first", "hello", "+79000000001", "+79000000002")
+	originalID, claimed, err := svc.suppressDuplicates(ctx, "user", first, policy)
+	require.NoError(t, err)
+	require.Empty(t, originalID)
+	require.Len(t, claimed, 2)
+
+	second := newDuplicatesTestMessage(t, "second", "hello", "+79000000003", "+79000000002")
+	_, _, err = svc.suppressDuplicates(ctx, "user", second, policy)
+
+	var dupErr *DuplicateMessageError
+	require.ErrorAs(t, err, &dupErr)
+	require.ErrorIs(t, err, ErrDuplicateMessage)
+	require.Equal(t, "first", dupErr.OriginalID)
+
+	// the claim of the rejected message is released
+	third := newDuplicatesTestMessage(t, "third", "hello", "+79000000003")
+	_, _, err = svc.suppressDuplicates(ctx, "user", third, policy)
+	require.NoError(t, err)
+
+	// different text, user or the same message ID are not duplicates
+	_, _, err = svc.suppressDuplicates(ctx, "user", newDuplicatesTestMessage(t, "fourth", "bye", "+79000000001"), policy)
+	require.NoError(t, err)
+	_, _, err = svc.suppressDuplicates(ctx, "other", newDuplicatesTestMessage(t, "fifth", "hello", "+79000000001"), policy)
+	require.NoError(t, err)
+	_, _, err = svc.suppressDuplicates(ctx, "user", newDuplicatesTestMessage(t, "first", "hello", "+79000000001"), policy)
+	require.NoError(t, err)
+}
+
+func TestSuppressDuplicatesMerge(t *testing.T) {
+	ctx := context.Background()
+	svc := newDuplicatesTestService()
+	policy := DuplicatePolicy{Window: time.Minute, Action: DuplicateActionMerge}
+
+	first := newDuplicatesTestMessage(t, "first", "hello", "+79000000001")
+	_, _, err := svc.suppressDuplicates(ctx, "user", first, policy)
+	require.NoError(t, err)
+
+	partial := newDuplicatesTestMessage(t, "second", "hello", "+79000000001", "+79000000002")
+	originalID, claimed, err := svc.suppressDuplicates(ctx, "user", partial, policy)
+	require.NoError(t, err)
+	require.Empty(t, originalID)
+	require.Len(t, claimed, 1)
+	require.Len(t, partial.Recipients, 1)
+	require.Equal(t, "+79000000002", partial.Recipients[0].PhoneNumber)
+
+	full := newDuplicatesTestMessage(t, "third", "hello", "+79000000001")
+	originalID, claimed, err = svc.suppressDuplicates(ctx, "user", full, policy)
+	require.NoError(t, err)
+	require.Equal(t, "first", originalID)
+	require.Empty(t, claimed)
+}
+
+func TestSuppressBatchDuplicates(t *testing.T) {
+	ctx := context.Background()
+	svc := newDuplicatesTestService()
+
+	merge := DuplicatePolicy{Window: time.Minute, Action: DuplicateActionMerge}
+	batch := []*messageModel{
+		newDuplicatesTestMessage(t, "first", "hello", "+79000000001"),
+		newDuplicatesTestMessage(t, "second", "hello", "+79000000002"),
+		// repeats a message of the same batch
+		newDuplicatesTestMessage(t, "third", "hello", "+79000000001"),
+	}
+	originals, claimed, err := svc.suppressBatchDuplicates(ctx, "user", batch, merge)
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "", "first"}, originals)
+	require.Len(t, claimed, 2)
+
+	// a rejected message fails the batch and releases the claims of the preceding ones
+	reject := DuplicatePolicy{Window: time.Minute, Action: DuplicateActionReject}
+	batch = []*messageModel{
+		newDuplicatesTestMessage(t, "fourth", "hello", "+79000000003"),
+		newDuplicatesTestMessage(t, "fifth", "hello", "+79000000002"),
+	}
+	_, _, err = svc.suppressBatchDuplicates(ctx, "user", batch, reject)
+
+	var dupErr *DuplicateMessageError
+	require.ErrorAs(t, err, &dupErr)
+	require.Equal(t, "second", dupErr.OriginalID)
+
+	originalID, _, err := svc.suppressDuplicates(
+		ctx,
+		"user",
+		newDuplicatesTestMessage(t, "sixth", "hello", "+79000000003"),
+		reject,
+	)
+	require.NoError(t, err)
+	require.Empty(t, originalID)
+}
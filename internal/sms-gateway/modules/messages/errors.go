@@ -8,8 +8,19 @@ var (
 	ErrMultipleMessagesFound = errors.New("multiple messages found")
 	ErrNoContent             = errors.New("no text or data content")
 	ErrMessageNotPending     = errors.New("message is not pending")
+	ErrStateConflict         = errors.New("message state was changed concurrently")
+	ErrDuplicateMessage      = errors.New("duplicate message")
+	ErrContentRejected       = errors.New("message content rejected by policy")
+	ErrMessageNotQuarantined = errors.New("message is not quarantined")
+	ErrReviewForbidden       = errors.New("message can only be reviewed by server reviewers")
+
+	ErrLinkNotFound = errors.New("link not found")
 
 	ErrQueueLimitExceeded = errors.New("queue limits exceeded")
+
+	ErrBulkJobNotFound    = errors.New("bulk job not found")
+	ErrBulkQueueFull      = errors.New("too many bulk jobs in progress")
+	ErrBulkJobInterrupted = errors.New("bulk job interrupted by server shutdown")
 )
 
 type ValidationError string
@@ -0,0 +1,106 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/pubsub"
+	"go.uber.org/zap"
+)
+
+const (
+	// statesTopic broadcasts the messages changed by processes without access
+	// to the states cache, such as the worker.
+	statesTopic   = "messages:states"
+	pubsubTimeout = 5 * time.Second
+)
+
+type statesMessage struct {
+	UserID string   `json:"userId"`
+	IDs    []string `json:"ids"`
+}
+
+// CacheInvalidator asks the API replicas to drop the cached states of messages.
+// It is intended for processes changing messages directly in the database.
+type CacheInvalidator struct {
+	pubsub pubsub.PubSub
+}
+
+func NewCacheInvalidator(pubsub pubsub.PubSub) *CacheInvalidator {
+	return &CacheInvalidator{
+		pubsub: pubsub,
+	}
+}
+
+// Invalidate drops the cached states of the user's messages with the given IDs.
+func (i *CacheInvalidator) Invalidate(ctx context.Context, userID string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(statesMessage{UserID: userID, IDs: ids})
+	if err != nil {
+		return fmt.Errorf("failed to marshal states message: %w", err)
+	}
+
+	pubCtx, cancel := context.WithTimeout(ctx, pubsubTimeout)
+	defer cancel()
+
+	if pubErr := i.pubsub.Publish(pubCtx, statesTopic, data); pubErr != nil {
+		return fmt.Errorf("failed to publish states message: %w", pubErr)
+	}
+
+	return nil
+}
+
+// statesListener drops the states cached by the replica on messages published by CacheInvalidator.
+type statesListener struct {
+	cache  *stateCache
+	pubsub pubsub.PubSub
+
+	logger *zap.Logger
+}
+
+func newStatesListener(cache *stateCache, pubsub pubsub.PubSub, logger *zap.Logger) *statesListener {
+	return &statesListener{
+		cache:  cache,
+		pubsub: pubsub,
+
+		logger: logger,
+	}
+}
+
+func (l *statesListener) Run(ctx context.Context) {
+	sub, err := l.pubsub.Subscribe(ctx, statesTopic)
+	if err != nil {
+		l.logger.Error("failed to subscribe to pubsub", zap.Error(err))
+		return
+	}
+	defer sub.Close()
+
+	ch := sub.Receive()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			message := new(statesMessage)
+			if jsonErr := json.Unmarshal(msg.Data, message); jsonErr != nil {
+				l.logger.Error("failed to unmarshal states message", zap.Error(jsonErr))
+				continue
+			}
+
+			for _, id := range message.IDs {
+				if cacheErr := l.cache.Delete(ctx, message.UserID, id); cacheErr != nil {
+					l.logger.Warn("failed to invalidate message cache", zap.String("id", id), zap.Error(cacheErr))
+				}
+			}
+		}
+	}
+}
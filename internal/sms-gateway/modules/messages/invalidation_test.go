@@ -0,0 +1,53 @@
+//nolint:testpackage // states listener is unexported; in-package test required.
+package messages
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/server/pkg/pubsub"
+	"github.com/go-core-fx/cachefx/cache"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStatesListenerDropsInvalidatedStates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ps := pubsub.NewMemory()
+	defer ps.Close()
+
+	//nolint:exhaustruct // only the cache TTL is needed
+	states := newCache(Config{CacheTTL: time.Hour}, cache.NewMemory(time.Hour))
+	for _, id := range []string{"expired", "kept"} {
+		state := new(MessageState)
+		state.ID = id
+		require.NoError(t, states.Set(ctx, "user", id, state))
+	}
+
+	listener := newStatesListener(states, ps, zap.NewNop())
+	done := make(chan struct{})
+	go func() {
+		listener.Run(ctx)
+		close(done)
+	}()
+
+	invalidator := NewCacheInvalidator(ps)
+	// the listener subscribes asynchronously, the invalidation is repeated until it is received
+	require.Eventually(t, func() bool {
+		require.NoError(t, invalidator.Invalidate(ctx, "user", "expired"))
+
+		_, err := states.Get(ctx, "user", "expired")
+		return errors.Is(err, cache.ErrKeyNotFound)
+	}, time.Second, 10*time.Millisecond)
+
+	kept, err := states.Get(ctx, "user", "kept")
+	require.NoError(t, err)
+	require.Equal(t, "kept", kept.ID)
+
+	cancel()
+	<-done
+}
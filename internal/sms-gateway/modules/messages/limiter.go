@@ -111,6 +111,29 @@ func (l *Limiter) Check(ctx context.Context, deviceID string) error {
 	return fmt.Errorf("%w: %s", ErrQueueLimitExceeded, item.Reason)
 }
 
+// Verdict returns the reason why new messages for the device are refused, or
+// an empty string if they are accepted. Unlike Check it has no side effects
+// and doesn't rely on the cached verdict of the latest refresh: the pending
+// limits are evaluated on the stats of the queue.
+func (l *Limiter) Verdict(ctx context.Context, deviceID string, stats queueStats) string {
+	if l.config.IsEmpty() {
+		return ""
+	}
+
+	item := l.oldestPendingItem(stats.OldestDueAt)
+	if item == nil {
+		item = l.maxPendingItem(stats.Due)
+	}
+	if item == nil {
+		item = l.checkLastStates(ctx, deviceID)
+	}
+	if item == nil {
+		return ""
+	}
+
+	return item.Reason
+}
+
 func (l *Limiter) Refresh(_ context.Context, deviceID string) error {
 	l.mux.Lock()
 	defer l.mux.Unlock()
@@ -179,11 +202,15 @@ func (l *Limiter) checkMaxPending(ctx context.Context, deviceID string) *limitIt
 		return nil
 	}
 
-	if pendingCount >= l.config.MaxPending {
-		return &limitItem{Reason: fmt.Sprintf("too many pending messages: %d / %d", pendingCount, l.config.MaxPending)}
+	return l.maxPendingItem(pendingCount)
+}
+
+func (l *Limiter) maxPendingItem(pendingCount int64) *limitItem {
+	if l.config.MaxPending <= 0 || pendingCount < l.config.MaxPending {
+		return nil
 	}
 
-	return nil
+	return &limitItem{Reason: fmt.Sprintf("too many pending messages: %d / %d", pendingCount, l.config.MaxPending)}
 }
 
 func (l *Limiter) checkOldestPending(ctx context.Context, deviceID string) *limitItem {
@@ -198,11 +225,15 @@ func (l *Limiter) checkOldestPending(ctx context.Context, deviceID string) *limi
 		return nil
 	}
 
-	if oldestPending != nil && time.Since(*oldestPending) > l.config.MaxPendingAge {
-		return &limitItem{Reason: fmt.Sprintf("too old pending message: %s", oldestPending.Format(time.RFC3339))}
+	return l.oldestPendingItem(oldestPending)
+}
+
+func (l *Limiter) oldestPendingItem(oldestPending *time.Time) *limitItem {
+	if l.config.MaxPendingAge <= 0 || oldestPending == nil || time.Since(*oldestPending) <= l.config.MaxPendingAge {
+		return nil
 	}
 
-	return nil
+	return &limitItem{Reason: fmt.Sprintf("too old pending message: %s", oldestPending.Format(time.RFC3339))}
 }
 
 func (l *Limiter) checkLastStates(ctx context.Context, deviceID string) *limitItem {
@@ -0,0 +1,197 @@
+package messages
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// linkCodeLength keeps short links short while making them hard to guess.
+	linkCodeLength = 8
+	linkCodeChars  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// maxUserAgentLength matches the size of the column.
+	maxUserAgentLength = 512
+)
+
+// ClicksPeriod is the size of the click statistics buckets.
+type ClicksPeriod string
+
+const (
+	ClicksPeriodHour ClicksPeriod = "hour"
+	ClicksPeriodDay  ClicksPeriod = "day"
+)
+
+// LinkClicks is the click statistics of a tracked link.
+type LinkClicks struct {
+	Code string
+	URL  string
+	// Clicks is the total number of clicks.
+	Clicks int64
+	// Recipients maps recipient phone numbers to the number of their clicks.
+	// Clicks on links of messages stored before tracking was limited to a
+	// single recipient can't be attributed.
+	Recipients map[string]int64
+	FirstClick *time.Time
+	LastClick  *time.Time
+}
+
+// ClicksBucket is the number of clicks on the user's links within a period.
+type ClicksBucket struct {
+	Start  time.Time
+	Clicks int64
+	// Links is the number of distinct links clicked.
+	Links int64
+}
+
+// rewriteLinks replaces the links in the text with short links and returns the new text
+// together with the links to store. The same URL gets the same short link.
+func rewriteLinks(text, baseURL string, newCode func() (string, error)) (string, []messageLinkModel, error) {
+	links := []messageLinkModel{}
+	codes := map[string]string{}
+
+	var rewriteErr error
+	result := linkRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		if rewriteErr != nil {
+			return match
+		}
+
+		link, suffix := trimLinkSuffix(match)
+		target := link
+		if !strings.Contains(target, "://") {
+			target = "http://" + target
+		}
+
+		code, ok := codes[target]
+		if !ok {
+			if code, rewriteErr = newCode(); rewriteErr != nil {
+				return match
+			}
+			codes[target] = code
+			links = append(links, newMessageLinkModel(code, target))
+		}
+
+		return baseURL + "/s/" + code + suffix
+	})
+	if rewriteErr != nil {
+		return "", nil, rewriteErr
+	}
+
+	return result, links, nil
+}
+
+// trimLinkSuffix splits the trailing punctuation that most likely belongs to the sentence.
+func trimLinkSuffix(link string) (string, string) {
+	trimmed := strings.TrimRight(link, ".,:;!?)]}")
+	return trimmed, link[len(trimmed):]
+}
+
+// newLinkCode generates a random short link code.
+func newLinkCode() (string, error) {
+	var sb strings.Builder
+	sb.Grow(linkCodeLength)
+
+	limit := big.NewInt(int64(len(linkCodeChars)))
+	for range linkCodeLength {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate link code: %w", err)
+		}
+		sb.WriteByte(linkCodeChars[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// ResolveLink returns the target URL of the short link and records the click.
+// Recording is best-effort and does not prevent the redirect.
+func (s *Service) ResolveLink(ctx context.Context, code, userAgent string) (string, error) {
+	link, err := s.messages.getLink(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	if clickErr := s.messages.insertClick(ctx, link, userAgent, time.Now()); clickErr != nil {
+		s.logger.Warn("failed to record link click", zap.String("code", code), zap.Error(clickErr))
+	}
+
+	return link.URL, nil
+}
+
+// GetMessageClicks returns the click statistics of the links of the user's message.
+func (s *Service) GetMessageClicks(ctx context.Context, userID, id string) ([]LinkClicks, error) {
+	message, err := s.messages.get(
+		*new(SelectFilter).WithExtID(id).WithUserID(userID),
+		*new(SelectOptions),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.messages.linkClicks(ctx, message.ID)
+}
+
+// GetClicks returns the number of clicks on the user's links in the time range grouped by period.
+func (s *Service) GetClicks(
+	ctx context.Context,
+	userID string,
+	from, to time.Time,
+	period ClicksPeriod,
+) ([]ClicksBucket, error) {
+	switch period {
+	case ClicksPeriodHour, ClicksPeriodDay:
+	default:
+		return nil, ValidationError(fmt.Sprintf("unknown clicks period %q", period))
+	}
+	if !from.Before(to) {
+		return nil, ValidationError("`from` must be before `to`")
+	}
+
+	return s.messages.countClicks(ctx, userID, from, to, period)
+}
+
+// trackLinks rewrites the links of the message text if link tracking is requested.
+// The short links are shared by the recipients of the message, so tracking is
+// refused for messages with several recipients: their clicks can't be attributed.
+func (s *Service) trackLinks(msg *messageModel, content *TextMessageContent, encrypted bool) error {
+	if s.config.LinksBaseURL == "" {
+		return ValidationError("link tracking is not available: public API host is not configured")
+	}
+	if encrypted {
+		return ValidationError("links of encrypted messages can't be tracked")
+	}
+	if len(msg.Recipients) > 1 {
+		return ValidationError("links can be tracked only for messages with a single recipient, send a message per recipient")
+	}
+
+	text, links, err := rewriteLinks(content.Text, s.config.LinksBaseURL, newLinkCode)
+	if err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	// short link codes are random, duplicates are detected by the original content
+	untracked, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+	msg.untrackedContent = string(untracked)
+
+	content.Text = text
+	msg.Links = links
+
+	return nil
+}
@@ -0,0 +1,78 @@
+//nolint:testpackage // link rewriting helpers are unexported; in-package test required.
+package messages
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteLinks(t *testing.T) {
+	counter := 0
+	newCode := func() (string, error) {
+		counter++
+		return "c" + strconv.Itoa(counter), nil
+	}
+
+	text, links, err := rewriteLinks(
+		"Sale at https://shop.example.com/a?b=1. See www.example.org, example.net/x and https://shop.example.com/a?b=1!",
+		"https://gw.example/api",
+		newCode,
+	)
+	require.NoError(t, err)
+	require.Equal(t,
+		"Sale at https://gw.example/api/s/c1. See https://gw.example/api/s/c2, https://gw.example/api/s/c3 and https://gw.example/api/s/c1!",
+		text,
+	)
+	require.Len(t, links, 3)
+	require.Equal(t, "https://shop.example.com/a?b=1", links[0].URL)
+	require.Equal(t, "http://www.example.org", links[1].URL)
+	require.Equal(t, "http://example.net/x", links[2].URL)
+
+	text, links, err = rewriteLinks("No links here, e.g. 3.14", "https://gw.example", newCode)
+	require.NoError(t, err)
+	require.Equal(t, "No links here, e.g. 3.14", text)
+	require.Empty(t, links)
+}
+
+func TestNewLinkCode(t *testing.T) {
+	code, err := newLinkCode()
+	require.NoError(t, err)
+	require.Len(t, code, linkCodeLength)
+
+	other, err := newLinkCode()
+	require.NoError(t, err)
+	require.NotEqual(t, code, other)
+}
+
+func TestTrackLinksKeepsDuplicateKey(t *testing.T) {
+	//nolint:exhaustruct // only the config is needed
+	svc := &Service{config: Config{LinksBaseURL: "https://gw.example"}}
+
+	plain := newDuplicatesTestMessage(t, "plain", "Go to https://example.com", "+79000000001")
+
+	tracked := newMessageModel("tracked", "device", []string{"+79000000001"}, 0, nil, nil, nil, true, false)
+	content := TextMessageContent{Text: "Go to https://example.com"}
+	require.NoError(t, svc.trackLinks(tracked, &content, false))
+	require.NoError(t, tracked.SetTextContent(content))
+
+	require.NotEqual(t, plain.Content, tracked.Content)
+	require.Equal(t, duplicateKey(plain, "+79000000001"), duplicateKey(tracked, "+79000000001"))
+
+	require.ErrorAs(t, svc.trackLinks(tracked, &content, true), new(ValidationError))
+}
+
+func TestTrackLinksSingleRecipient(t *testing.T) {
+	//nolint:exhaustruct // only the config is needed
+	svc := &Service{config: Config{LinksBaseURL: "https://gw.example"}}
+
+	msg := newMessageModel(
+		"tracked", "device", []string{"+79000000001", "+79000000002"}, 0, nil, nil, nil, true, false,
+	)
+	content := TextMessageContent{Text: "Go to https://example.com"}
+
+	require.ErrorAs(t, svc.trackLinks(msg, &content, false), new(ValidationError))
+	require.Equal(t, "Go to https://example.com", content.Text)
+	require.Empty(t, msg.Links)
+}
@@ -0,0 +1,92 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/phonenumbers"
+	"github.com/samber/lo"
+)
+
+// carrierLanguage is the language of the carrier names in lookup results.
+const carrierLanguage = "en"
+
+// PhoneNumberInfo is the result of a phone number lookup.
+type PhoneNumberInfo struct {
+	// Input is the phone number as provided.
+	Input string
+	// PhoneNumber is the normalized number as it would be enqueued, empty if the input can't be parsed.
+	PhoneNumber string
+	// Country is the ISO 3166-1 region code of the number.
+	Country string
+	// Type is the type of the number.
+	Type PhoneNumberType
+	// Carrier is the original carrier from the offline data; numbers may have been ported since.
+	Carrier string
+	// Timezones lists the timezones the number may belong to.
+	Timezones []string
+	// Valid is true when the number would be accepted by enqueue.
+	Valid bool
+	// Reason explains the verdict.
+	Reason PhoneNumberReason
+	// Error describes why the number is rejected.
+	Error string
+}
+
+// LookupPhoneNumbers parses the phone numbers the same way as enqueue does and
+// returns the details of each of them.
+func (s *Service) LookupPhoneNumbers(userID string, phoneNumbers []string, region string) ([]PhoneNumberInfo, error) {
+	policy, err := s.phonePolicy(userID, region)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PhoneNumberInfo, 0, len(phoneNumbers))
+	for _, input := range phoneNumbers {
+		info, lookupErr := lookupPhoneNumber(input, policy)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+func lookupPhoneNumber(input string, policy PhonePolicy) (PhoneNumberInfo, error) {
+	parsed, parseErr := parsePhoneNumber(input, policy)
+
+	info := PhoneNumberInfo{
+		Input:       input,
+		PhoneNumber: parsed.Display,
+		Country:     parsed.Region,
+		Type:        parsed.Type,
+		Carrier:     "",
+		Timezones:   nil,
+		Valid:       parseErr == nil,
+		Reason:      parsed.Reason,
+		Error:       "",
+	}
+	if parseErr != nil {
+		info.Error = parseErr.Error()
+	}
+
+	// carrier and timezone data is only meaningful for valid regular numbers
+	if parsed.Number == nil || parsed.Type == "" || parsed.Type == PhoneNumberTypeShortCode {
+		return info, nil
+	}
+
+	carrier, err := phonenumbers.GetCarrierForNumber(parsed.Number, carrierLanguage)
+	if err != nil {
+		return info, fmt.Errorf("failed to get carrier: %w", err)
+	}
+	info.Carrier = carrier
+
+	timezones, err := phonenumbers.GetTimezonesForNumber(parsed.Number)
+	if err != nil {
+		return info, fmt.Errorf("failed to get timezones: %w", err)
+	}
+	// the slice belongs to the library cache, so it is filtered into a new one
+	info.Timezones = lo.Without(timezones, phonenumbers.UNKNOWN_TIMEZONE)
+
+	return info, nil
+}
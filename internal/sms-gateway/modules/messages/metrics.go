@@ -16,10 +16,12 @@ const (
 	metricLimiterRefreshesTotal   = "limiter_refreshes_total"
 	metricLimiterBatchSize        = "limiter_batch_size"
 	metricLimiterQueryErrorsTotal = "limiter_query_errors_total"
+	metricStateTransitionsTotal   = "state_transitions_total"
 
 	labelState  = "state"
 	labelResult = "result"
 	labelCheck  = "check"
+	labelLevel  = "level"
 
 	resultAllowed = "allowed"
 	resultLimited = "limited"
@@ -27,6 +29,9 @@ const (
 	checkMaxPending    = "max_pending"
 	checkMaxPendingAge = "max_pending_age"
 	checkMaxFailed     = "max_failed"
+
+	transitionLevelMessage   = "message"
+	transitionLevelRecipient = "recipient"
 )
 
 type metrics struct {
@@ -39,6 +44,8 @@ type metrics struct {
 	limiterRefreshesTotal prometheus.Counter
 	limiterBatchSize      prometheus.Gauge
 	limiterQueryErrors    *prometheus.CounterVec
+
+	stateTransitionsTotal *prometheus.CounterVec
 }
 
 func newMetrics() *metrics {
@@ -90,6 +97,13 @@ func newMetrics() *metrics {
 			Name:      metricLimiterQueryErrorsTotal,
 			Help:      "Total number of limiter query errors by check type",
 		}, []string{labelCheck}),
+
+		stateTransitionsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      metricStateTransitionsTotal,
+			Help:      "Total number of state updates by level and result",
+		}, []string{labelLevel, labelResult}),
 	}
 }
 
@@ -124,3 +138,7 @@ func (m *metrics) SetLimiterBatchSize(size int) {
 func (m *metrics) IncLimiterQueryError(check string) {
 	m.limiterQueryErrors.WithLabelValues(check).Inc()
 }
+
+func (m *metrics) IncStateTransition(level string, result StateUpdateResult) {
+	m.stateTransitionsTotal.WithLabelValues(level, string(result)).Inc()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/samber/lo"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -16,16 +17,18 @@ type ProcessingState string
 type MessageType string
 
 const (
-	ProcessingStatePending    ProcessingState = "Pending"
-	ProcessingStateCancelling ProcessingState = "Cancelling"
-	ProcessingStateCancelled  ProcessingState = "Cancelled"
-	ProcessingStateProcessed  ProcessingState = "Processed"
-	ProcessingStateSent       ProcessingState = "Sent"
-	ProcessingStateDelivered  ProcessingState = "Delivered"
-	ProcessingStateFailed     ProcessingState = "Failed"
+	ProcessingStatePending     ProcessingState = "Pending"
+	ProcessingStateQuarantined ProcessingState = "Quarantined"
+	ProcessingStateCancelling  ProcessingState = "Cancelling"
+	ProcessingStateCancelled   ProcessingState = "Cancelled"
+	ProcessingStateProcessed   ProcessingState = "Processed"
+	ProcessingStateSent        ProcessingState = "Sent"
+	ProcessingStateDelivered   ProcessingState = "Delivered"
+	ProcessingStateFailed      ProcessingState = "Failed"
 
 	MessageTypeText MessageType = "Text"
 	MessageTypeData MessageType = "Data"
+	MessageTypeMms  MessageType = "MMS"
 )
 
 type messageModel struct {
@@ -34,11 +37,11 @@ type messageModel struct {
 	ID                 uint64          `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
 	DeviceID           string          `gorm:"not null;type:char(21);uniqueIndex:unq_messages_id_device,priority:2;index:idx_messages_device_state"`
 	ExtID              string          `gorm:"not null;type:varchar(36);uniqueIndex:unq_messages_id_device,priority:1"`
-	Type               MessageType     `gorm:"not null;type:enum('Text','Data');default:Text"`
+	Type               MessageType     `gorm:"not null;type:enum('Text','Data','MMS');default:Text"`
 	Content            string          `gorm:"not null;type:text"`
-	State              ProcessingState `gorm:"not null;type:enum('Pending','Cancelling','Cancelled','Processed','Sent','Delivered','Failed');default:Pending;index:idx_messages_device_state"`
+	State              ProcessingState `gorm:"not null;type:enum('Pending','Quarantined','Cancelling','Cancelled','Processed','Sent','Delivered','Failed');default:Pending;index:idx_messages_device_state"`
 	ValidUntil         *time.Time      `gorm:"type:datetime"`
-	ScheduleAt         *time.Time      `gorm:"type:datetime"`
+	ScheduleAt         *time.Time      `gorm:"type:datetime;index:idx_messages_paced,priority:2"`
 	SimNumber          *uint8          `gorm:"type:tinyint(1) unsigned"`
 	WithDeliveryReport bool            `gorm:"not null;type:tinyint(1) unsigned"`
 	Priority           int8            `gorm:"not null;type:tinyint;default:0"`
@@ -46,9 +49,22 @@ type messageModel struct {
 	IsHashed    bool `gorm:"not null;type:tinyint(1) unsigned;default:0"`
 	IsEncrypted bool `gorm:"not null;type:tinyint(1) unsigned;default:0"`
 
+	CampaignID *string `gorm:"type:char(21);index:idx_messages_campaign"`
+	// IsPaced marks messages whose ScheduleAt is a server-assigned delivery slot.
+	IsPaced bool `gorm:"not null;type:tinyint(1) unsigned;default:0;index:idx_messages_paced,priority:1"`
+	// ContentVerdict is the JSON encoded result of the content policy checks, nil if no rule was violated.
+	ContentVerdict *string `gorm:"type:text"`
+
 	Device     devices.DeviceModel     `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
 	Recipients []messageRecipientModel `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
 	States     []messageStateModel     `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
+	Edits      []messageEditModel      `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
+	Links      []messageLinkModel      `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
+	// Attachments reference the uploaded files of an MMS message.
+	Attachments []messageAttachmentModel `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
+
+	// untrackedContent is the content before the links were replaced with short links.
+	untrackedContent string
 }
 
 func newMessageModel(
@@ -62,17 +78,26 @@ func newMessageModel(
 	withDeliveryReport bool,
 	isEncrypted bool,
 ) *messageModel {
+	now := time.Now()
+
 	//nolint:exhaustruct // partial constructor
 	return &messageModel{
 		ExtID:    extID,
 		DeviceID: deviceID,
 		Recipients: lo.Map(phoneNumbers, func(item string, _ int) messageRecipientModel {
-			return newMessageRecipient(item, ProcessingStatePending, nil)
+			recipient := newMessageRecipient(item, ProcessingStatePending, nil)
+			recipient.States = []messageRecipientStateModel{
+				{
+					State:     ProcessingStatePending,
+					UpdatedAt: now,
+				},
+			}
+			return recipient
 		}),
 		States: []messageStateModel{
 			{
 				State:     ProcessingStatePending,
-				UpdatedAt: time.Now(),
+				UpdatedAt: now,
 			},
 		},
 		Priority:           priority,
@@ -144,6 +169,112 @@ func (m *messageModel) GetDataContent() (*DataMessageContent, error) {
 	return content, nil
 }
 
+// SetMmsContent stores the content and references the attachments, so they are
+// kept until the message is hashed or deleted.
+func (m *messageModel) SetMmsContent(content MmsMessageContent) error {
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	m.Type = MessageTypeMms
+	m.Content = string(contentJSON)
+	m.Attachments = lo.Map(content.Attachments, func(item MmsAttachment, _ int) messageAttachmentModel {
+		return messageAttachmentModel{
+			MessageID:    0,
+			AttachmentID: item.ID,
+		}
+	})
+
+	return nil
+}
+
+func (m *messageModel) GetMmsContent() (*MmsMessageContent, error) {
+	if m.Type != MessageTypeMms || m.Content == "" || m.IsHashed {
+		return nil, nil //nolint:nilnil // special meaning
+	}
+
+	content := new(MmsMessageContent)
+
+	err := json.Unmarshal([]byte(m.Content), content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mms content: %w", err)
+	}
+
+	return content, nil
+}
+
+// subjectText returns the text checked by the content policies.
+func (m *messageModel) subjectText() (string, error) {
+	if m.IsEncrypted {
+		return "", nil
+	}
+
+	text, err := m.GetTextContent()
+	if err != nil {
+		return "", fmt.Errorf("failed to get text content: %w", err)
+	}
+	if text != nil {
+		return text.Text, nil
+	}
+
+	mms, err := m.GetMmsContent()
+	if err != nil {
+		return "", fmt.Errorf("failed to get mms content: %w", err)
+	}
+	if mms != nil {
+		return mms.Text, nil
+	}
+
+	return "", nil
+}
+
+// SetContentVerdict stores the verdict and moves a quarantined message and its recipients to the Quarantined state.
+func (m *messageModel) SetContentVerdict(verdict *ContentVerdict) error {
+	if verdict == nil {
+		m.ContentVerdict = nil
+		return nil
+	}
+
+	verdictJSON, err := json.Marshal(verdict)
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+	m.ContentVerdict = lo.ToPtr(string(verdictJSON))
+
+	if verdict.Action != ContentActionQuarantine {
+		return nil
+	}
+
+	m.State = ProcessingStateQuarantined
+	for i := range m.States {
+		m.States[i].State = ProcessingStateQuarantined
+	}
+	for i := range m.Recipients {
+		m.Recipients[i].State = ProcessingStateQuarantined
+		for j := range m.Recipients[i].States {
+			m.Recipients[i].States[j].State = ProcessingStateQuarantined
+		}
+	}
+
+	return nil
+}
+
+func (m *messageModel) GetContentVerdict() (*ContentVerdict, error) {
+	if m.ContentVerdict == nil {
+		return nil, nil //nolint:nilnil // special meaning
+	}
+
+	verdict := new(ContentVerdict)
+
+	err := json.Unmarshal([]byte(*m.ContentVerdict), verdict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal content verdict: %w", err)
+	}
+
+	return verdict, nil
+}
+
 func (m *messageModel) GetHashedContent() (*HashedMessageContent, error) {
 	if !m.IsHashed || m.Content == "" {
 		return nil, nil //nolint:nilnil // special meaning
@@ -165,15 +296,26 @@ func (m *messageModel) toStateDomain() (*MessageState, error) {
 		return nil, fmt.Errorf("failed to decode data content: %w", err)
 	}
 
+	mmsContent, err := m.GetMmsContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode mms content: %w", err)
+	}
+
 	hashedContent, err := m.GetHashedContent()
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode hashed content: %w", err)
 	}
 
+	verdict, err := m.GetContentVerdict()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode content verdict: %w", err)
+	}
+
 	content := MessageStateContent{
 		MessageContent: MessageContent{
 			TextContent: textContent,
 			DataContent: dataContent,
+			MmsContent:  mmsContent,
 		},
 		HashedContent: hashedContent,
 	}
@@ -193,11 +335,30 @@ func (m *messageModel) toStateDomain() (*MessageState, error) {
 				func(item messageStateModel) (string, time.Time) { return string(item.State), item.UpdatedAt },
 			),
 		},
+		Edits: lo.Map(m.Edits, func(item messageEditModel, _ int) MessageEdit {
+			return MessageEdit{Fields: item.Fields, EditedAt: item.EditedAt}
+		}),
 		MessageStateContent: content,
 
 		DeviceID:    m.DeviceID,
 		IsHashed:    m.IsHashed,
 		IsEncrypted: m.IsEncrypted,
+
+		RecipientsStates: lo.SliceToMap(
+			m.Recipients,
+			func(item messageRecipientModel) (string, map[string]time.Time) {
+				return item.PhoneNumber, lo.SliceToMap(
+					item.States,
+					func(state messageRecipientStateModel) (string, time.Time) {
+						return string(state.State), state.UpdatedAt
+					},
+				)
+			},
+		),
+
+		ContentVerdict: verdict,
+
+		Normalization: nil,
 	}, nil
 }
 
@@ -205,8 +366,10 @@ type messageRecipientModel struct {
 	ID          uint64          `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
 	MessageID   uint64          `gorm:"uniqueIndex:unq_message_recipients_message_id_phone_number,priority:1;type:BIGINT UNSIGNED"`
 	PhoneNumber string          `gorm:"uniqueIndex:unq_message_recipients_message_id_phone_number,priority:2;type:varchar(128)"`
-	State       ProcessingState `gorm:"not null;type:enum('Pending','Cancelling','Cancelled','Processed','Sent','Delivered','Failed');default:Pending"`
+	State       ProcessingState `gorm:"not null;type:enum('Pending','Quarantined','Cancelling','Cancelled','Processed','Sent','Delivered','Failed');default:Pending"`
 	Error       *string         `gorm:"type:varchar(256)"`
+
+	States []messageRecipientStateModel `gorm:"foreignKey:RecipientID;constraint:OnDelete:CASCADE"`
 }
 
 func newMessageRecipient(phoneNumber string, state ProcessingState, err *string) messageRecipientModel {
@@ -216,6 +379,7 @@ func newMessageRecipient(phoneNumber string, state ProcessingState, err *string)
 		PhoneNumber: phoneNumber,
 		State:       state,
 		Error:       err,
+		States:      nil,
 	}
 }
 
@@ -234,7 +398,7 @@ func (m *messageRecipientModel) toDomain() smsgateway.RecipientState {
 type messageStateModel struct {
 	ID        uint64          `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
 	MessageID uint64          `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:unq_message_states_message_id_state,priority:1"`
-	State     ProcessingState `gorm:"not null;type:enum('Pending','Cancelling','Cancelled','Processed','Sent','Delivered','Failed');uniqueIndex:unq_message_states_message_id_state,priority:2"`
+	State     ProcessingState `gorm:"not null;type:enum('Pending','Quarantined','Cancelling','Cancelled','Processed','Sent','Delivered','Failed');uniqueIndex:unq_message_states_message_id_state,priority:2"`
 	UpdatedAt time.Time       `gorm:"<-:create;not null;autoupdatetime:false"`
 }
 
@@ -242,8 +406,95 @@ func (m *messageStateModel) TableName() string {
 	return "message_states"
 }
 
+// messageEditModel records an edit of a pending message. The state history keeps
+// a single entry per state, so the edits are stored separately.
+type messageEditModel struct {
+	ID        uint64                      `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	MessageID uint64                      `gorm:"not null;type:BIGINT UNSIGNED;index:idx_message_edits_message_id"`
+	Fields    datatypes.JSONSlice[string] `gorm:"not null;serializer:json;type:json"`
+	EditedAt  time.Time                   `gorm:"not null;type:datetime(3)"`
+}
+
+func (m *messageEditModel) TableName() string {
+	return "message_edits"
+}
+
+type messageRecipientStateModel struct {
+	ID          uint64          `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	RecipientID uint64          `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:unq_message_recipient_states_recipient_id_state,priority:1"`
+	State       ProcessingState `gorm:"not null;type:enum('Pending','Quarantined','Cancelling','Cancelled','Processed','Sent','Delivered','Failed');uniqueIndex:unq_message_recipient_states_recipient_id_state,priority:2"`
+	UpdatedAt   time.Time       `gorm:"<-:create;not null;autoupdatetime:false"`
+}
+
+func (m *messageRecipientStateModel) TableName() string {
+	return "message_recipient_states"
+}
+
+// messageLinkModel is a short link replacing a URL in the message text.
+type messageLinkModel struct {
+	ID        uint64    `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	MessageID uint64    `gorm:"not null;type:BIGINT UNSIGNED;index:idx_message_links_message_id"`
+	Code      string    `gorm:"not null;type:varchar(16);uniqueIndex:unq_message_links_code"`
+	URL       string    `gorm:"not null;type:text"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime:false"`
+
+	Clicks []messageLinkClickModel `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE"`
+}
+
+func newMessageLinkModel(code, url string) messageLinkModel {
+	return messageLinkModel{
+		ID:        0,
+		MessageID: 0,
+		Code:      code,
+		URL:       url,
+		CreatedAt: time.Now(),
+		Clicks:    nil,
+	}
+}
+
+func (m *messageLinkModel) TableName() string {
+	return "message_links"
+}
+
+// messageLinkClickModel records a click on a short link. The recipient is only known
+// for messages with a single recipient, others share the link between recipients.
+type messageLinkClickModel struct {
+	ID          uint64    `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	LinkID      uint64    `gorm:"not null;type:BIGINT UNSIGNED;index:idx_message_link_clicks_link_id"`
+	RecipientID *uint64   `gorm:"type:BIGINT UNSIGNED"`
+	UserAgent   string    `gorm:"not null;type:varchar(512)"`
+	ClickedAt   time.Time `gorm:"not null;index:idx_message_link_clicks_clicked_at"`
+
+	Recipient *messageRecipientModel `gorm:"foreignKey:RecipientID;constraint:OnDelete:SET NULL"`
+}
+
+func (m *messageLinkClickModel) TableName() string {
+	return "message_link_clicks"
+}
+
+// messageAttachmentModel links an MMS message to an uploaded attachment.
+// An attachment may be shared by several messages and is purged by the worker
+// when none of them needs the content anymore.
+type messageAttachmentModel struct {
+	MessageID    uint64 `gorm:"primaryKey;type:BIGINT UNSIGNED"`
+	AttachmentID string `gorm:"primaryKey;type:char(21);index:idx_message_attachments_attachment_id"`
+}
+
+func (m *messageAttachmentModel) TableName() string {
+	return "message_attachments"
+}
+
 func Migrate(db *gorm.DB) error {
-	if err := db.AutoMigrate(new(messageModel), new(messageRecipientModel), new(messageStateModel)); err != nil {
+	if err := db.AutoMigrate(
+		new(messageModel),
+		new(messageRecipientModel),
+		new(messageStateModel),
+		new(messageRecipientStateModel),
+		new(messageEditModel),
+		new(messageLinkModel),
+		new(messageLinkClickModel),
+		new(messageAttachmentModel),
+	); err != nil {
 		return fmt.Errorf("messages migration failed: %w", err)
 	}
 	return nil
@@ -25,6 +25,10 @@ func Module() fx.Option {
 		fx.Provide(newMetrics, fx.Private),
 		fx.Provide(newHashingWorker, fx.Private),
 		fx.Provide(newCache, fx.Private),
+		fx.Provide(newJobCache, fx.Private),
+		fx.Provide(newDupCache, fx.Private),
+		fx.Provide(newBulkWorker, fx.Private),
+		fx.Provide(newStatesListener, fx.Private),
 
 		fx.Provide(
 			NewRepository,
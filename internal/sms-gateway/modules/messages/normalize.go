@@ -0,0 +1,256 @@
+package messages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizationRule enables a group of text replacements applied before a message is stored.
+type NormalizationRule string
+
+const (
+	// NormalizationPunctuation replaces smart quotes, dashes, ellipsis and special spaces.
+	NormalizationPunctuation NormalizationRule = "punctuation"
+	// NormalizationCyrillic transliterates Cyrillic letters to Latin.
+	NormalizationCyrillic NormalizationRule = "cyrillic"
+	// NormalizationGreek replaces Greek letters with their GSM-7 capitals or Latin look-alikes.
+	NormalizationGreek NormalizationRule = "greek"
+	// NormalizationLatin removes diacritics from Latin letters missing in GSM-7.
+	NormalizationLatin NormalizationRule = "latin"
+	// NormalizationStrip removes the characters that are still not in GSM-7.
+	NormalizationStrip NormalizationRule = "strip"
+)
+
+// NormalizationNone disables normalization, including the user default.
+const NormalizationNone = "none"
+
+// MessageEncoding is the SMS alphabet required to send a text.
+type MessageEncoding string
+
+const (
+	MessageEncodingGSM7 MessageEncoding = "gsm7"
+	MessageEncodingUCS2 MessageEncoding = "ucs2"
+)
+
+// NormalizeOptions selects the normalization rules. Normalization is disabled when no rules are set.
+type NormalizeOptions struct {
+	Rules []NormalizationRule
+}
+
+// ParseNormalizeOptions parses a list of rule names.
+func ParseNormalizeOptions(rules []string) (NormalizeOptions, error) {
+	opts := NormalizeOptions{Rules: make([]NormalizationRule, 0, len(rules))}
+	for _, rule := range rules {
+		switch r := NormalizationRule(strings.TrimSpace(rule)); r {
+		case NormalizationPunctuation,
+			NormalizationCyrillic,
+			NormalizationGreek,
+			NormalizationLatin,
+			NormalizationStrip:
+			opts.Rules = append(opts.Rules, r)
+		case NormalizationNone:
+			return NormalizeOptions{Rules: nil}, nil
+		default:
+			return opts, ValidationError(fmt.Sprintf("unknown normalization rule %q", rule))
+		}
+	}
+
+	return opts, nil
+}
+
+// Enabled returns true if at least one rule is set.
+func (o NormalizeOptions) Enabled() bool {
+	return len(o.Rules) > 0
+}
+
+func (o NormalizeOptions) has(rule NormalizationRule) bool {
+	return slices.Contains(o.Rules, rule)
+}
+
+// TextChange is a replacement made by normalization. An empty To means the character was removed.
+type TextChange struct {
+	From  string
+	To    string
+	Count int
+}
+
+// NormalizationReport describes the changes made to a message text.
+type NormalizationReport struct {
+	Changes  []TextChange    // changes in the order of their first occurrence
+	Encoding MessageEncoding // encoding required by the resulting text
+}
+
+// normalizeText applies the rules to the text and reports what has been changed.
+func normalizeText(text string, opts NormalizeOptions) (string, *NormalizationReport) {
+	report := &NormalizationReport{
+		Changes:  []TextChange{},
+		Encoding: MessageEncodingGSM7,
+	}
+	index := map[[2]string]int{}
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	for _, r := range text {
+		replacement, ok := normalizeRune(r, opts)
+		if !ok {
+			sb.WriteRune(r)
+			if !isGSM7(r) {
+				report.Encoding = MessageEncodingUCS2
+			}
+			continue
+		}
+
+		sb.WriteString(replacement)
+
+		key := [2]string{string(r), replacement}
+		if i, exists := index[key]; exists {
+			report.Changes[i].Count++
+			continue
+		}
+		index[key] = len(report.Changes)
+		report.Changes = append(report.Changes, TextChange{From: key[0], To: key[1], Count: 1})
+	}
+
+	return sb.String(), report
+}
+
+// normalizeRune returns the replacement of a character that is not in GSM-7.
+func normalizeRune(r rune, opts NormalizeOptions) (string, bool) {
+	if isGSM7(r) {
+		return "", false
+	}
+
+	if opts.has(NormalizationPunctuation) {
+		if s, ok := punctuation[r]; ok {
+			return s, true
+		}
+	}
+	if opts.has(NormalizationCyrillic) && unicode.Is(unicode.Cyrillic, r) {
+		if s, ok := transliterateCyrillic(r); ok {
+			return s, true
+		}
+	}
+	if opts.has(NormalizationGreek) && unicode.Is(unicode.Greek, r) {
+		if s, ok := transliterateGreek(r); ok {
+			return s, true
+		}
+	}
+	if opts.has(NormalizationLatin) && unicode.Is(unicode.Latin, r) {
+		if s, ok := stripDiacritics(r); ok {
+			return s, true
+		}
+	}
+	if opts.has(NormalizationStrip) {
+		return "", true
+	}
+
+	return "", false
+}
+
+func transliterateCyrillic(r rune) (string, bool) {
+	s, ok := cyrillic[unicode.ToLower(r)]
+	if !ok {
+		return "", false
+	}
+	if unicode.IsUpper(r) && s != "" {
+		s = strings.ToUpper(s[:1]) + s[1:]
+	}
+	return s, true
+}
+
+func transliterateGreek(r rune) (string, bool) {
+	// GSM-7 has no lowercase Greek, the capitals without accents are used instead
+	upper := []rune(stripMarks(string(unicode.ToUpper(r))))
+	if len(upper) != 1 {
+		return "", false
+	}
+	if r == 'ς' {
+		upper[0] = 'Σ'
+	}
+	if isGSM7(upper[0]) {
+		return string(upper[0]), true
+	}
+	s, ok := greek[upper[0]]
+	return s, ok
+}
+
+func stripDiacritics(r rune) (string, bool) {
+	if s, ok := latin[r]; ok {
+		return s, true
+	}
+
+	s := stripMarks(string(r))
+	if s == string(r) || !isGSM7String(s) {
+		return "", false
+	}
+	return s, true
+}
+
+// stripMarks removes combining marks from the decomposed string.
+func stripMarks(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(s))
+}
+
+func isGSM7(r rune) bool {
+	return strings.ContainsRune(gsm7Basic, r) || strings.ContainsRune(gsm7Extension, r)
+}
+
+func isGSM7String(s string) bool {
+	for _, r := range s {
+		if !isGSM7(r) {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	// gsm7Basic is the GSM 03.38 default alphabet without the escape character.
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	// gsm7Extension lists the characters sent with the escape prefix.
+	gsm7Extension = "\f^{}\\[~]|€"
+)
+
+//nolint:gochecknoglobals // lookup tables
+var (
+	punctuation = map[rune]string{
+		'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '`': "'",
+		'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '«': "\"", '»': "\"",
+		'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+		'…': "...", '•': "*",
+		// tabs and special spaces
+		'\t': " ", '\u00a0': " ", '\u2002': " ", '\u2003': " ", '\u2009': " ", '\u202f': " ", '\u3000': " ",
+		// zero-width characters
+		'\u200b': "", '\u200c': "", '\u200d': "", '\u2060': "", '\ufeff': "",
+	}
+
+	cyrillic = map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+		'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+		'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+		'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+		'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+	}
+
+	// greek maps the capitals missing in GSM-7 to their Latin look-alikes.
+	greek = map[rune]string{
+		'Α': "A", 'Β': "B", 'Ε': "E", 'Ζ': "Z", 'Η': "H", 'Ι': "I", 'Κ': "K",
+		'Μ': "M", 'Ν': "N", 'Ο': "O", 'Ρ': "P", 'Τ': "T", 'Υ': "Y", 'Χ': "X",
+	}
+
+	// latin lists the letters that don't decompose to a GSM-7 letter.
+	latin = map[rune]string{
+		'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'œ': "oe", 'Œ': "OE", 'ı': "i", 'þ': "th", 'Þ': "Th", 'ð': "d", 'Ð': "D",
+	}
+)
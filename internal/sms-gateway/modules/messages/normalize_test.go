@@ -0,0 +1,133 @@
+//nolint:testpackage // normalization helpers are unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeText(t *testing.T) {
+	all := NormalizeOptions{Rules: []NormalizationRule{
+		NormalizationPunctuation,
+		NormalizationCyrillic,
+		NormalizationGreek,
+		NormalizationLatin,
+		NormalizationStrip,
+	}}
+
+	tests := []struct {
+		name     string
+		text     string
+		opts     NormalizeOptions
+		want     string
+		changes  []TextChange
+		encoding MessageEncoding
+	}{
+		{
+			name:     "gsm7 text is untouched",
+			text:     "Price: 10€ [sale] {now} – é à ñ",
+			opts:     NormalizeOptions{Rules: []NormalizationRule{NormalizationLatin}},
+			want:     "Price: 10€ [sale] {now} – é à ñ",
+			changes:  []TextChange{},
+			encoding: MessageEncodingUCS2,
+		},
+		{
+			name: "punctuation",
+			text: "“Hello” – it’s… ‘fine’",
+			opts: NormalizeOptions{Rules: []NormalizationRule{NormalizationPunctuation}},
+			want: "\"Hello\" - it's... 'fine'",
+			changes: []TextChange{
+				{From: "“", To: "\"", Count: 1},
+				{From: "”", To: "\"", Count: 1},
+				{From: "–", To: "-", Count: 1},
+				{From: "’", To: "'", Count: 2},
+				{From: "…", To: "...", Count: 1},
+				{From: "‘", To: "'", Count: 1},
+			},
+			encoding: MessageEncodingGSM7,
+		},
+		{
+			name: "cyrillic",
+			text: "Щука Жук",
+			opts: NormalizeOptions{Rules: []NormalizationRule{NormalizationCyrillic}},
+			want: "Shchuka Zhuk",
+			changes: []TextChange{
+				{From: "Щ", To: "Shch", Count: 1},
+				{From: "у", To: "u", Count: 2},
+				{From: "к", To: "k", Count: 2},
+				{From: "а", To: "a", Count: 1},
+				{From: "Ж", To: "Zh", Count: 1},
+			},
+			encoding: MessageEncodingGSM7,
+		},
+		{
+			name: "greek",
+			text: "Καλή",
+			opts: NormalizeOptions{Rules: []NormalizationRule{NormalizationGreek}},
+			want: "KAΛH",
+			changes: []TextChange{
+				{From: "Κ", To: "K", Count: 1},
+				{From: "α", To: "A", Count: 1},
+				{From: "λ", To: "Λ", Count: 1},
+				{From: "ή", To: "H", Count: 1},
+			},
+			encoding: MessageEncodingGSM7,
+		},
+		{
+			name: "latin",
+			text: "Łódź çà",
+			opts: NormalizeOptions{Rules: []NormalizationRule{NormalizationLatin}},
+			want: "Lodz cà",
+			changes: []TextChange{
+				{From: "Ł", To: "L", Count: 1},
+				{From: "ó", To: "o", Count: 1},
+				{From: "ź", To: "z", Count: 1},
+				{From: "ç", To: "c", Count: 1},
+			},
+			encoding: MessageEncodingGSM7,
+		},
+		{
+			name: "strip",
+			text: "Hi 👋 世界",
+			opts: all,
+			want: "Hi  ",
+			changes: []TextChange{
+				{From: "👋", To: "", Count: 1},
+				{From: "世", To: "", Count: 1},
+				{From: "界", To: "", Count: 1},
+			},
+			encoding: MessageEncodingGSM7,
+		},
+		{
+			name:     "no strip keeps unsupported characters",
+			text:     "Привет 👋",
+			opts:     NormalizeOptions{Rules: []NormalizationRule{NormalizationPunctuation}},
+			want:     "Привет 👋",
+			changes:  []TextChange{},
+			encoding: MessageEncodingUCS2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report := normalizeText(tt.text, tt.opts)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.changes, report.Changes)
+			require.Equal(t, tt.encoding, report.Encoding)
+		})
+	}
+}
+
+func TestParseNormalizeOptions(t *testing.T) {
+	opts, err := ParseNormalizeOptions([]string{"punctuation", " strip"})
+	require.NoError(t, err)
+	require.Equal(t, []NormalizationRule{NormalizationPunctuation, NormalizationStrip}, opts.Rules)
+
+	opts, err = ParseNormalizeOptions([]string{"none"})
+	require.NoError(t, err)
+	require.False(t, opts.Enabled())
+
+	_, err = ParseNormalizeOptions([]string{"emoji"})
+	require.ErrorAs(t, err, new(ValidationError))
+}
@@ -0,0 +1,231 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
+	"github.com/nyaruka/phonenumbers"
+)
+
+type PhoneNumberType string
+
+const (
+	PhoneNumberTypeMobile    PhoneNumberType = "mobile"
+	PhoneNumberTypeFixedLine PhoneNumberType = "fixed_line"
+	PhoneNumberTypeVoIP      PhoneNumberType = "voip"
+	PhoneNumberTypeShortCode PhoneNumberType = "short_code"
+	// PhoneNumberTypeOther is reported by lookups for numbers of other types, e.g. toll free or pager.
+	// It cannot be allowed by a policy.
+	PhoneNumberTypeOther PhoneNumberType = "other"
+)
+
+// PhoneNumberReason explains why a phone number is accepted or rejected.
+type PhoneNumberReason string
+
+const (
+	PhoneNumberReasonValid              PhoneNumberReason = "valid"
+	PhoneNumberReasonNotANumber         PhoneNumberReason = "not_a_number"
+	PhoneNumberReasonInvalidCountryCode PhoneNumberReason = "invalid_country_code"
+	PhoneNumberReasonTooShort           PhoneNumberReason = "too_short"
+	PhoneNumberReasonTooLong            PhoneNumberReason = "too_long"
+	PhoneNumberReasonInvalidLength      PhoneNumberReason = "invalid_length"
+	PhoneNumberReasonInvalidNumber      PhoneNumberReason = "invalid_number"
+	PhoneNumberReasonTypeNotAllowed     PhoneNumberReason = "type_not_allowed"
+	PhoneNumberReasonCountryNotAllowed  PhoneNumberReason = "country_not_allowed"
+	PhoneNumberReasonCountryDenied      PhoneNumberReason = "country_denied"
+)
+
+// PhonePolicy controls how phone numbers are parsed and which of them are accepted.
+type PhonePolicy struct {
+	// DefaultRegion is used for numbers without a country code.
+	DefaultRegion string
+	// AllowedTypes lists accepted number types.
+	AllowedTypes []PhoneNumberType
+	// AllowedCountries limits recipients to the listed regions. All regions are allowed when empty.
+	AllowedCountries []string
+	// DeniedCountries rejects recipients in the listed regions.
+	DeniedCountries []string
+}
+
+// DefaultPhonePolicy matches the behavior before the policy became configurable.
+func DefaultPhonePolicy() PhonePolicy {
+	return PhonePolicy{
+		DefaultRegion:    "RU",
+		AllowedTypes:     []PhoneNumberType{PhoneNumberTypeMobile},
+		AllowedCountries: nil,
+		DeniedCountries:  nil,
+	}
+}
+
+// Validate checks that the policy uses known regions and number types.
+func (p PhonePolicy) Validate() error {
+	if err := validateRegion(p.DefaultRegion); err != nil {
+		return fmt.Errorf("default region: %w", err)
+	}
+
+	if len(p.AllowedTypes) == 0 {
+		return ValidationError("at least one number type must be allowed")
+	}
+	for _, t := range p.AllowedTypes {
+		switch t {
+		case PhoneNumberTypeMobile, PhoneNumberTypeFixedLine, PhoneNumberTypeVoIP, PhoneNumberTypeShortCode:
+		default:
+			return ValidationError(fmt.Sprintf("unknown number type %q", t))
+		}
+	}
+
+	for _, region := range slices.Concat(p.AllowedCountries, p.DeniedCountries) {
+		if err := validateRegion(region); err != nil {
+			return fmt.Errorf("country list: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// withOverrides returns the policy with the non-empty user settings applied.
+func (p PhonePolicy) withOverrides(o settings.PhoneNumbers) PhonePolicy {
+	if o.DefaultRegion != "" {
+		p.DefaultRegion = strings.ToUpper(o.DefaultRegion)
+	}
+	if len(o.AllowedTypes) > 0 {
+		p.AllowedTypes = make([]PhoneNumberType, 0, len(o.AllowedTypes))
+		for _, t := range o.AllowedTypes {
+			p.AllowedTypes = append(p.AllowedTypes, PhoneNumberType(t))
+		}
+	}
+	if len(o.AllowedCountries) > 0 {
+		p.AllowedCountries = upper(o.AllowedCountries)
+	}
+	if len(o.DeniedCountries) > 0 {
+		p.DeniedCountries = upper(o.DeniedCountries)
+	}
+
+	return p
+}
+
+func validateRegion(region string) error {
+	if !phonenumbers.GetSupportedRegions()[region] {
+		return ValidationError(fmt.Sprintf("unknown region %q", region))
+	}
+
+	return nil
+}
+
+func upper(items []string) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = strings.ToUpper(item)
+	}
+	return result
+}
+
+// phoneNumberTypes maps libphonenumber types to the policy types they satisfy.
+//
+//nolint:gochecknoglobals // lookup table
+var phoneNumberTypes = map[phonenumbers.PhoneNumberType][]PhoneNumberType{
+	phonenumbers.MOBILE:               {PhoneNumberTypeMobile},
+	phonenumbers.FIXED_LINE:           {PhoneNumberTypeFixedLine},
+	phonenumbers.FIXED_LINE_OR_MOBILE: {PhoneNumberTypeMobile, PhoneNumberTypeFixedLine},
+	phonenumbers.VOIP:                 {PhoneNumberTypeVoIP},
+}
+
+// possibleNumberReasons maps libphonenumber length checks to rejection reasons.
+//
+//nolint:gochecknoglobals // lookup table
+var possibleNumberReasons = map[phonenumbers.ValidationResult]PhoneNumberReason{
+	phonenumbers.INVALID_COUNTRY_CODE: PhoneNumberReasonInvalidCountryCode,
+	phonenumbers.TOO_SHORT:            PhoneNumberReasonTooShort,
+	phonenumbers.TOO_LONG:             PhoneNumberReasonTooLong,
+	phonenumbers.INVALID_LENGTH:       PhoneNumberReasonInvalidLength,
+}
+
+// parsedPhoneNumber is a phone number accepted or rejected by a policy.
+type parsedPhoneNumber struct {
+	Number  *phonenumbers.PhoneNumber // nil when the input can't be parsed
+	Type    PhoneNumberType
+	Region  string
+	Display string // E.164 form or digits of a short code
+	Reason  PhoneNumberReason
+}
+
+// parsePhoneNumber parses the input using the policy and reports why it is not accepted.
+// The result is always returned and carries the reason even if the number is rejected.
+func parsePhoneNumber(input string, policy PhonePolicy) (*parsedPhoneNumber, error) {
+	parsed := &parsedPhoneNumber{
+		Number:  nil,
+		Type:    "",
+		Region:  "",
+		Display: "",
+		Reason:  PhoneNumberReasonValid,
+	}
+
+	phone, err := phonenumbers.Parse(input, policy.DefaultRegion)
+	if err != nil {
+		parsed.Reason = PhoneNumberReasonNotANumber
+		switch {
+		case errors.Is(err, phonenumbers.ErrInvalidCountryCode):
+			parsed.Reason = PhoneNumberReasonInvalidCountryCode
+		case errors.Is(err, phonenumbers.ErrTooShortNSN), errors.Is(err, phonenumbers.ErrTooShortAfterIDD):
+			parsed.Reason = PhoneNumberReasonTooShort
+		case errors.Is(err, phonenumbers.ErrNumTooLong):
+			parsed.Reason = PhoneNumberReasonTooLong
+		}
+		return parsed, ValidationError(fmt.Sprintf("failed to parse phone number: %s", err.Error()))
+	}
+
+	parsed.Number = phone
+	parsed.Region = phonenumbers.GetRegionCodeForNumber(phone)
+	parsed.Display = phonenumbers.Format(phone, phonenumbers.E164)
+
+	var types []PhoneNumberType
+	switch {
+	case phonenumbers.IsValidNumber(phone):
+		types = phoneNumberTypes[phonenumbers.GetNumberType(phone)]
+	case phonenumbers.IsValidShortNumberForRegion(phone, policy.DefaultRegion):
+		types = []PhoneNumberType{PhoneNumberTypeShortCode}
+		parsed.Region = policy.DefaultRegion
+		parsed.Display = phonenumbers.GetNationalSignificantNumber(phone)
+	default:
+		parsed.Reason = PhoneNumberReasonInvalidNumber
+		if reason, ok := possibleNumberReasons[phonenumbers.IsPossibleNumberWithReason(phone)]; ok {
+			parsed.Reason = reason
+		}
+		return parsed, ValidationError("invalid phone number")
+	}
+
+	allowed := slices.IndexFunc(types, func(t PhoneNumberType) bool { return slices.Contains(policy.AllowedTypes, t) })
+	if allowed < 0 {
+		parsed.Reason = PhoneNumberReasonTypeNotAllowed
+		if len(types) == 0 {
+			parsed.Type = PhoneNumberTypeOther
+			return parsed, ValidationError("phone number type is not allowed")
+		}
+		parsed.Type = types[0]
+		return parsed, ValidationError(fmt.Sprintf("phone number type %q is not allowed", types[0]))
+	}
+	parsed.Type = types[allowed]
+
+	if len(policy.AllowedCountries) > 0 && !slices.Contains(policy.AllowedCountries, parsed.Region) {
+		parsed.Reason = PhoneNumberReasonCountryNotAllowed
+		return parsed, ValidationError(fmt.Sprintf("country %q is not allowed", parsed.Region))
+	}
+	if slices.Contains(policy.DeniedCountries, parsed.Region) {
+		parsed.Reason = PhoneNumberReasonCountryDenied
+		return parsed, ValidationError(fmt.Sprintf("country %q is denied", parsed.Region))
+	}
+
+	return parsed, nil
+}
+
+func cleanPhoneNumber(input string, policy PhonePolicy) (string, error) {
+	parsed, err := parsePhoneNumber(input, policy)
+	if err != nil {
+		return input, err
+	}
+
+	return parsed.Display, nil
+}
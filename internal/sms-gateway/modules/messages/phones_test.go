@@ -0,0 +1,155 @@
+//nolint:testpackage // phone parsing helpers are unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		policy  PhonePolicy
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "default policy",
+			input:  "89123456789",
+			policy: DefaultPhonePolicy(),
+			want:   "+79123456789",
+		},
+		{
+			name:   "international format ignores region",
+			input:  "+4915123456789",
+			policy: DefaultPhonePolicy(),
+			want:   "+4915123456789",
+		},
+		{
+			name:   "national format with custom region",
+			input:  "015123456789",
+			policy: PhonePolicy{DefaultRegion: "DE", AllowedTypes: []PhoneNumberType{PhoneNumberTypeMobile}},
+			want:   "+4915123456789",
+		},
+		{
+			name:    "fixed line rejected",
+			input:   "+74951234567",
+			policy:  DefaultPhonePolicy(),
+			wantErr: true,
+		},
+		{
+			name:  "fixed line allowed",
+			input: "+74951234567",
+			policy: PhonePolicy{
+				DefaultRegion: "RU",
+				AllowedTypes:  []PhoneNumberType{PhoneNumberTypeMobile, PhoneNumberTypeFixedLine},
+			},
+			want: "+74951234567",
+		},
+		{
+			name:    "short code rejected",
+			input:   "33669",
+			policy:  PhonePolicy{DefaultRegion: "US", AllowedTypes: []PhoneNumberType{PhoneNumberTypeMobile}},
+			wantErr: true,
+		},
+		{
+			name:  "short code allowed",
+			input: "33669",
+			policy: PhonePolicy{
+				DefaultRegion: "US",
+				AllowedTypes:  []PhoneNumberType{PhoneNumberTypeShortCode},
+			},
+			want: "33669",
+		},
+		{
+			name:  "country not in allow list",
+			input: "+4915123456789",
+			policy: PhonePolicy{
+				DefaultRegion:    "RU",
+				AllowedTypes:     []PhoneNumberType{PhoneNumberTypeMobile},
+				AllowedCountries: []string{"RU"},
+			},
+			wantErr: true,
+		},
+		{
+			name:  "denied country",
+			input: "+79123456789",
+			policy: PhonePolicy{
+				DefaultRegion:   "RU",
+				AllowedTypes:    []PhoneNumberType{PhoneNumberTypeMobile},
+				DeniedCountries: []string{"RU"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid number",
+			input:   "+7123",
+			policy:  DefaultPhonePolicy(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cleanPhoneNumber(tt.input, tt.policy)
+			if tt.wantErr {
+				require.ErrorAs(t, err, new(ValidationError))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPhonePolicyValidate(t *testing.T) {
+	require.NoError(t, DefaultPhonePolicy().Validate())
+
+	require.Error(t, PhonePolicy{DefaultRegion: "XX", AllowedTypes: []PhoneNumberType{PhoneNumberTypeMobile}}.Validate())
+	require.Error(t, PhonePolicy{DefaultRegion: "RU"}.Validate())
+	require.Error(t, PhonePolicy{DefaultRegion: "RU", AllowedTypes: []PhoneNumberType{"pager"}}.Validate())
+}
+
+func TestLookupPhoneNumber(t *testing.T) {
+	policy := DefaultPhonePolicy()
+
+	tests := []struct {
+		name      string
+		input     string
+		number    string
+		numType   PhoneNumberType
+		reason    PhoneNumberReason
+		timezones bool
+	}{
+		{"mobile", "+79123456789", "+79123456789", PhoneNumberTypeMobile, PhoneNumberReasonValid, true},
+		{"fixed line", "+74951234567", "+74951234567", PhoneNumberTypeFixedLine, PhoneNumberReasonTypeNotAllowed, true},
+		{"too short", "+791234", "+791234", "", PhoneNumberReasonTooShort, false},
+		{"not a number", "hello", "", "", PhoneNumberReasonNotANumber, false},
+		{"invalid country code", "+999123456789", "", "", PhoneNumberReasonInvalidCountryCode, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := lookupPhoneNumber(tt.input, policy)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.input, info.Input)
+			require.Equal(t, tt.number, info.PhoneNumber)
+			require.Equal(t, tt.numType, info.Type)
+			require.Equal(t, tt.reason, info.Reason)
+			require.Equal(t, tt.reason == PhoneNumberReasonValid, info.Valid)
+			require.Equal(t, tt.timezones, len(info.Timezones) > 0)
+			require.NotContains(t, info.Timezones, "Etc/Unknown")
+
+			// enqueue accepts exactly the numbers the lookup reports as valid
+			cleaned, cleanErr := cleanPhoneNumber(tt.input, policy)
+			require.Equal(t, info.Valid, cleanErr == nil)
+			if info.Valid {
+				require.Equal(t, info.PhoneNumber, cleaned)
+			}
+		})
+	}
+}
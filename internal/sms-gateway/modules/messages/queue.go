@@ -0,0 +1,90 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// QueuedMessage is a pending or cancelling message waiting for the device.
+type QueuedMessage struct {
+	ID           string
+	Type         MessageType
+	State        ProcessingState
+	PhoneNumbers []string
+	SimNumber    *uint8
+	Priority     int8
+	ScheduleAt   *time.Time
+	Held         bool // A paced message whose delivery slot hasn't arrived yet
+	CampaignID   *string
+	CreatedAt    time.Time
+}
+
+// Queue describes the messages waiting for a device.
+type Queue struct {
+	Pending    int64
+	Cancelling int64
+	Held       int64      // Paced messages whose delivery slot hasn't arrived yet
+	OldestAt   *time.Time // Creation time of the oldest waiting message, nil if the queue is empty
+
+	LimitEnabled bool
+	LimitReason  *string // Why new messages are refused, nil if they are accepted
+
+	Messages []QueuedMessage // The page of messages in delivery order
+}
+
+// Total is the number of messages waiting for the device.
+func (q Queue) Total() int64 {
+	return q.Pending + q.Cancelling
+}
+
+// Queue returns the messages waiting for the device in the order SelectPending
+// delivers them, together with the queue stats and the limiter verdict. The
+// paced messages are listed at their slots and marked as held until the slot arrives.
+func (s *Service) Queue(ctx context.Context, deviceID string, order Order, limit, offset int) (*Queue, error) {
+	if order == "" {
+		order = MessagesOrderLIFO
+	}
+
+	now := time.Now()
+
+	stats, err := s.messages.queueStats(ctx, deviceID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.messages.listQueue(deviceID, order, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Queue{
+		Pending:    stats.Pending,
+		Cancelling: stats.Cancelling,
+		Held:       stats.Held,
+		OldestAt:   stats.OldestAt,
+
+		LimitEnabled: !s.limiter.config.IsEmpty(),
+		LimitReason:  lo.EmptyableToPtr(s.limiter.Verdict(ctx, deviceID, stats)),
+
+		Messages: lo.Map(items, func(m messageModel, _ int) QueuedMessage { return m.toQueued(now) }),
+	}, nil
+}
+
+func (m *messageModel) toQueued(now time.Time) QueuedMessage {
+	return QueuedMessage{
+		ID:    m.ExtID,
+		Type:  m.Type,
+		State: m.State,
+		PhoneNumbers: lo.Map(m.Recipients, func(r messageRecipientModel, _ int) string {
+			return r.PhoneNumber
+		}),
+		SimNumber:  m.SimNumber,
+		Priority:   m.Priority,
+		ScheduleAt: m.ScheduleAt,
+		Held:       m.IsPaced && m.ScheduleAt != nil && m.ScheduleAt.After(now),
+		CampaignID: m.CampaignID,
+		CreatedAt:  m.CreatedAt,
+	}
+}
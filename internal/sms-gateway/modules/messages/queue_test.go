@@ -0,0 +1,80 @@
+//nolint:testpackage // the models are unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageModelToQueued(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		isPaced    bool
+		scheduleAt *time.Time
+		held       bool
+	}{
+		{name: "not scheduled", isPaced: false, scheduleAt: nil, held: false},
+		{name: "scheduled by the user", isPaced: false, scheduleAt: lo.ToPtr(now.Add(time.Hour)), held: false},
+		{name: "slot arrived", isPaced: true, scheduleAt: lo.ToPtr(now.Add(-time.Minute)), held: false},
+		{name: "slot not arrived", isPaced: true, scheduleAt: lo.ToPtr(now.Add(time.Minute)), held: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//nolint:exhaustruct // only the queue fields are used
+			model := messageModel{
+				ExtID:      "message",
+				Type:       MessageTypeText,
+				State:      ProcessingStatePending,
+				Priority:   10,
+				ScheduleAt: tt.scheduleAt,
+				IsPaced:    tt.isPaced,
+				Recipients: []messageRecipientModel{
+					{PhoneNumber: "+79990001122"}, //nolint:exhaustruct // only the number is used
+					{PhoneNumber: "+79990003344"}, //nolint:exhaustruct // only the number is used
+				},
+			}
+
+			queued := model.toQueued(now)
+
+			require.Equal(t, "message", queued.ID)
+			require.Equal(t, int8(10), queued.Priority)
+			require.Equal(t, []string{"+79990001122", "+79990003344"}, queued.PhoneNumbers)
+			require.Equal(t, tt.held, queued.Held)
+		})
+	}
+}
+
+func TestLimiterVerdictFromStats(t *testing.T) {
+	//nolint:exhaustruct // the failed messages limit is disabled, so the repository isn't used
+	limiter := &Limiter{config: QueueConfig{MaxPending: 2, MaxPendingAge: time.Hour}}
+
+	tests := []struct {
+		name   string
+		stats  queueStats
+		reason string
+	}{
+		{name: "empty queue", stats: queueStats{}, reason: ""},
+		//nolint:exhaustruct // only the due messages matter
+		{name: "scheduled messages only", stats: queueStats{Pending: 5}, reason: ""},
+		//nolint:exhaustruct // only the due messages matter
+		{name: "too many due", stats: queueStats{Pending: 5, Due: 2}, reason: "too many pending messages: 2 / 2"},
+		{
+			name: "too old",
+			//nolint:exhaustruct // only the due messages matter
+			stats:  queueStats{Pending: 1, Due: 1, OldestDueAt: lo.ToPtr(time.Unix(0, 0))},
+			reason: "too old pending message: " + time.Unix(0, 0).Format(time.RFC3339),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.reason, limiter.Verdict(t.Context(), "device", tt.stats))
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/android-sms-gateway/server/pkg/mysql"
+	"github.com/samber/lo"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -24,37 +25,7 @@ func NewRepository(db *gorm.DB) *Repository {
 }
 
 func (r *Repository) list(filter SelectFilter, options SelectOptions) ([]messageModel, int64, error) {
-	query := r.db.Model((*messageModel)(nil))
-
-	// Apply date range filter
-	if !filter.StartDate.IsZero() {
-		query = query.Where("messages.created_at >= ?", filter.StartDate)
-	}
-	if !filter.EndDate.IsZero() {
-		query = query.Where("messages.created_at < ?", filter.EndDate)
-	}
-
-	// Apply ID filter
-	if filter.ExtID != "" {
-		query = query.Where("messages.ext_id = ?", filter.ExtID)
-	}
-
-	// Apply user filter
-	if filter.UserID != "" {
-		query = query.
-			Joins("JOIN devices ON messages.device_id = devices.id").
-			Where("devices.user_id = ?", filter.UserID)
-	}
-
-	// Apply state filter
-	if len(filter.State) > 0 {
-		query = query.Where("messages.state IN ?", filter.State)
-	}
-
-	// Apply device filter
-	if filter.DeviceID != "" {
-		query = query.Where("messages.device_id = ?", filter.DeviceID)
-	}
+	query := applyFilter(r.db.Model((*messageModel)(nil)), filter)
 
 	// Get total count
 	var total int64
@@ -90,7 +61,10 @@ func (r *Repository) list(filter SelectFilter, options SelectOptions) ([]message
 		query = query.Joins("Device")
 	}
 	if options.WithStates {
-		query = query.Preload("States")
+		query = query.Preload("States").Preload("Edits", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+		if options.WithRecipients {
+			query = query.Preload("Recipients.States")
+		}
 	}
 
 	// Apply content filter
@@ -106,15 +80,130 @@ func (r *Repository) list(filter SelectFilter, options SelectOptions) ([]message
 	return messages, total, nil
 }
 
-func (r *Repository) listPending(deviceID string, order Order) ([]messageModel, error) {
+func applyFilter(query *gorm.DB, filter SelectFilter) *gorm.DB {
+	// Apply date range filter
+	if !filter.StartDate.IsZero() {
+		query = query.Where("messages.created_at >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		query = query.Where("messages.created_at < ?", filter.EndDate)
+	}
+
+	// Apply ID filter
+	if filter.ExtID != "" {
+		query = query.Where("messages.ext_id = ?", filter.ExtID)
+	}
+	if len(filter.ExtIDs) > 0 {
+		query = query.Where("messages.ext_id IN ?", filter.ExtIDs)
+	}
+
+	// Apply user filter
+	if filter.UserID != "" {
+		query = query.
+			Joins("JOIN devices ON messages.device_id = devices.id").
+			Where("devices.user_id = ?", filter.UserID)
+	}
+
+	// Apply state filter
+	if len(filter.State) > 0 {
+		query = query.Where("messages.state IN ?", filter.State)
+	}
+
+	// Apply device filter
+	if filter.DeviceID != "" {
+		query = query.Where("messages.device_id = ?", filter.DeviceID)
+	}
+
+	// Apply campaign filter
+	if filter.CampaignID != "" {
+		query = query.Where("messages.campaign_id = ?", filter.CampaignID)
+	}
+
+	// Apply pacing filter
+	if len(filter.ExcludeTypes) > 0 {
+		query = query.Where("messages.type NOT IN ?", filter.ExcludeTypes)
+	}
+	if !filter.SlotArrivedBy.IsZero() {
+		query = query.Where("(messages.is_paced = 0 OR messages.schedule_at <= ?)", filter.SlotArrivedBy)
+	}
+
+	return query
+}
+
+func (r *Repository) listPending(deviceID string, order Order, exclude ...MessageType) ([]messageModel, error) {
+	filter := new(SelectFilter).
+		WithDeviceID(deviceID).
+		WithState(ProcessingStatePending).
+		WithState(ProcessingStateCancelling).
+		WithSlotArrivedBy(time.Now())
+	for _, messageType := range exclude {
+		filter.WithoutType(messageType)
+	}
+
 	messages, _, err := r.list(
-		*new(SelectFilter).WithDeviceID(deviceID).WithState(ProcessingStatePending).WithState(ProcessingStateCancelling),
+		*filter,
 		*new(SelectOptions).IncludeContent().IncludeRecipients().WithLimit(maxPendingBatch).WithOrderBy(order),
 	)
 
 	return messages, err
 }
 
+// listQueue returns a page of the pending and cancelling messages of the device
+// in the order of listPending, including the paced messages whose slot hasn't arrived.
+func (r *Repository) listQueue(deviceID string, order Order, limit, offset int) ([]messageModel, error) {
+	messages, _, err := r.list(
+		*new(SelectFilter).
+			WithDeviceID(deviceID).
+			WithState(ProcessingStatePending).
+			WithState(ProcessingStateCancelling),
+		*new(SelectOptions).IncludeRecipients().WithLimit(limit).WithOffset(offset).WithOrderBy(order),
+	)
+
+	return messages, err
+}
+
+type queueStats struct {
+	Pending    int64      `gorm:"column:pending"`
+	Cancelling int64      `gorm:"column:cancelling"`
+	Held       int64      `gorm:"column:held"`
+	OldestAt   *time.Time `gorm:"column:oldest_at"`
+
+	// Due and OldestDueAt describe the pending messages the device can send
+	// now, the ones the queue limits apply to.
+	Due         int64      `gorm:"column:due"`
+	OldestDueAt *time.Time `gorm:"column:oldest_due_at"`
+}
+
+// queueStats counts the pending and cancelling messages of the device, held are
+// the paced messages whose slot hasn't arrived by now.
+func (r *Repository) queueStats(ctx context.Context, deviceID string, now time.Time) (queueStats, error) {
+	// the pending messages the device can send now
+	const due = "state = ? AND (schedule_at IS NULL OR schedule_at <= ?)"
+
+	stats := queueStats{}
+	err := r.db.WithContext(ctx).
+		Model((*messageModel)(nil)).
+		Select(
+			"COALESCE(SUM(state = ?), 0) AS pending, "+
+				"COALESCE(SUM(state = ?), 0) AS cancelling, "+
+				"COALESCE(SUM(is_paced = 1 AND schedule_at > ?), 0) AS held, "+
+				"MIN(created_at) AS oldest_at, "+
+				"COALESCE(SUM("+due+"), 0) AS due, "+
+				"MIN(CASE WHEN "+due+" THEN created_at END) AS oldest_due_at",
+			ProcessingStatePending, ProcessingStateCancelling, now,
+			ProcessingStatePending, now,
+			ProcessingStatePending, now,
+		).
+		Where("device_id = ?", deviceID).
+		Where("state IN ?", []ProcessingState{ProcessingStatePending, ProcessingStateCancelling}).
+		Scan(&stats).Error
+	if err != nil {
+		return queueStats{}, fmt.Errorf("failed to get queue stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 func (r *Repository) get(filter SelectFilter, options SelectOptions) (messageModel, error) {
 	messages, _, err := r.list(filter, options)
 	if err != nil {
@@ -145,10 +234,15 @@ func (r *Repository) Insert(message *messageModel) error {
 	return fmt.Errorf("failed to insert message: %w", err)
 }
 
-func (r *Repository) UpdateState(message *messageModel) error {
+// UpdateState stores the new state of the message if it is still in prevState.
+func (r *Repository) UpdateState(message *messageModel, prevState ProcessingState) error {
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(message).Select("State").Updates(message).Error; err != nil {
-			return err
+		res := tx.Model(message).Where("state = ?", prevState).Select("State").Updates(message)
+		if res.Error != nil {
+			return res.Error
+		}
+		if prevState != message.State && res.RowsAffected == 0 {
+			return ErrStateConflict
 		}
 
 		for _, v := range message.States {
@@ -167,6 +261,15 @@ func (r *Repository) UpdateState(message *messageModel) error {
 				Updates(map[string]any{"state": v.State, "error": v.Error}).Error; err != nil {
 				return err
 			}
+
+			for _, s := range v.States {
+				s.RecipientID = v.ID
+				if err := tx.Model(&s).Clauses(clause.OnConflict{
+					DoNothing: true,
+				}).Create(&s).Error; err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
@@ -179,10 +282,12 @@ func (r *Repository) UpdateState(message *messageModel) error {
 	return nil
 }
 
+// HashProcessed replaces content and recipient phone numbers of processed messages with their hashes.
+// Recipient state history references recipients by ID, so it is covered by hashing the recipients.
 func (r *Repository) HashProcessed(ctx context.Context, ids []uint64) (int64, error) {
 	rawSQL := "UPDATE `messages` `m`, `message_recipients` `r`\n" +
 		"SET `m`.`is_hashed` = true, `m`.`content` = SHA2(COALESCE(JSON_VALUE(`content`, '$.text'), JSON_VALUE(`content`, '$.data')), 256), `r`.`phone_number` = LEFT(SHA2(phone_number, 256), 16)\n" +
-		"WHERE `m`.`id` = `r`.`message_id` AND `m`.`is_hashed` = false AND `m`.`is_encrypted` = false AND `m`.`state` NOT IN ('Pending', 'Cancelling')"
+		"WHERE `m`.`id` = `r`.`message_id` AND `m`.`is_hashed` = false AND `m`.`is_encrypted` = false AND `m`.`state` NOT IN ('Pending', 'Quarantined', 'Cancelling')"
 	params := []any{}
 	if len(ids) > 0 {
 		rawSQL += " AND `m`.`id` IN (?)"
@@ -198,15 +303,71 @@ func (r *Repository) HashProcessed(ctx context.Context, ids []uint64) (int64, er
 	return res.RowsAffected, nil
 }
 
+// UpdatePending stores an edited message if it is still pending and records the edit.
+// When recipients are set, they replace the existing ones starting in the Pending state
+// at the time of the edit. The state history of the message is left intact.
+func (r *Repository) UpdatePending(ctx context.Context, message *messageModel, edit *messageEditModel) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(message).
+			Where("state = ?", ProcessingStatePending).
+			Select("Content", "SimNumber", "ValidUntil", "ScheduleAt", "Priority", "ContentVerdict").
+			Updates(message)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrMessageNotPending
+		}
+
+		edit.MessageID = message.ID
+		if err := tx.Create(edit).Error; err != nil {
+			return err
+		}
+
+		if message.Recipients == nil {
+			return nil
+		}
+
+		if err := tx.Where("message_id = ?", message.ID).Delete(new(messageRecipientModel)).Error; err != nil {
+			return err
+		}
+
+		for i := range message.Recipients {
+			message.Recipients[i].MessageID = message.ID
+			message.Recipients[i].States = []messageRecipientStateModel{
+				{
+					ID:          0,
+					RecipientID: 0,
+					State:       ProcessingStatePending,
+					UpdatedAt:   edit.EditedAt,
+				},
+			}
+		}
+
+		return tx.Create(&message.Recipients).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrMessageNotPending) {
+			return err
+		}
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+
+	return nil
+}
+
 func (r *Repository) CancelMessage(userID string, id string) error {
-	res := r.db.Model((*messageModel)(nil)).
-		Where("ext_id = ? AND state = ?", id, ProcessingStatePending).
-		Where("device_id IN (?)", r.db.Table("devices").Select("id").Where("user_id = ?", userID)).
-		Update("state", ProcessingStateCancelling)
-	if res.Error != nil {
-		return fmt.Errorf("failed to cancel message: %w", res.Error)
+	rows, err := r.updatePendingBatch(
+		context.Background(),
+		*new(SelectFilter).WithExtID(id).WithUserID(userID),
+		0,
+		1,
+		map[string]any{"state": ProcessingStateCancelling},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to cancel message: %w", err)
 	}
-	if res.RowsAffected == 0 {
+	if len(rows) == 0 {
 		return ErrMessageNotPending
 	}
 	return nil
@@ -215,7 +376,11 @@ func (r *Repository) CancelMessage(userID string, id string) error {
 func (r *Repository) Cleanup(ctx context.Context, until time.Time) (int64, error) {
 	res := r.db.
 		WithContext(ctx).
-		Where("state NOT IN ?", []ProcessingState{ProcessingStatePending, ProcessingStateCancelling}).
+		Where("state NOT IN ?", []ProcessingState{
+			ProcessingStatePending,
+			ProcessingStateQuarantined,
+			ProcessingStateCancelling,
+		}).
 		Where("created_at < ?", until).
 		Delete(new(messageModel))
 	return res.RowsAffected, res.Error
@@ -273,3 +438,455 @@ func (r *Repository) GetStatesInTimeWindow(
 	}
 	return states, nil
 }
+
+// selectExtIDs returns the IDs of all messages of the device.
+func (r *Repository) selectExtIDs(ctx context.Context, deviceID string) ([]string, error) {
+	var ids []string
+	if err := r.db.WithContext(ctx).
+		Model((*messageModel)(nil)).
+		Where("device_id = ?", deviceID).
+		Pluck("ext_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to select message ids: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ExpirePending moves up to limit pending messages with ValidUntil before until to the Failed state.
+// The reason is stored as the error of every recipient.
+func (r *Repository) ExpirePending(
+	ctx context.Context,
+	until time.Time,
+	limit int,
+	reason string,
+) ([]ExpiredMessage, error) {
+	type expiredRow struct {
+		ID       uint64
+		ExtID    string
+		DeviceID string
+		UserID   string
+	}
+
+	rows := []expiredRow{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model((*messageModel)(nil)).
+			Select("messages.id", "messages.ext_id", "messages.device_id", "devices.user_id").
+			Joins("JOIN devices ON messages.device_id = devices.id").
+			Where("messages.state = ?", ProcessingStatePending).
+			Where("messages.valid_until < ?", until).
+			Order("messages.id").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to select expired messages: %w", err)
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := lo.Map(rows, func(row expiredRow, _ int) uint64 { return row.ID })
+
+		if err := tx.Model((*messageModel)(nil)).
+			Where("id IN ?", ids).
+			Update("state", ProcessingStateFailed).Error; err != nil {
+			return fmt.Errorf("failed to update messages state: %w", err)
+		}
+
+		if err := tx.Model((*messageRecipientModel)(nil)).
+			Where("message_id IN ?", ids).
+			Updates(map[string]any{"state": ProcessingStateFailed, "error": reason}).Error; err != nil {
+			return fmt.Errorf("failed to update recipients state: %w", err)
+		}
+
+		var recipientIDs []uint64
+		if err := tx.Model((*messageRecipientModel)(nil)).
+			Where("message_id IN ?", ids).
+			Pluck("id", &recipientIDs).Error; err != nil {
+			return fmt.Errorf("failed to select recipients: %w", err)
+		}
+
+		now := time.Now()
+		states := lo.Map(ids, func(id uint64, _ int) messageStateModel {
+			return messageStateModel{
+				ID:        0,
+				MessageID: id,
+				State:     ProcessingStateFailed,
+				UpdatedAt: now,
+			}
+		})
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&states).Error; err != nil {
+			return fmt.Errorf("failed to insert message states: %w", err)
+		}
+
+		if len(recipientIDs) == 0 {
+			return nil
+		}
+
+		recipientStates := lo.Map(recipientIDs, func(id uint64, _ int) messageRecipientStateModel {
+			return messageRecipientStateModel{
+				ID:          0,
+				RecipientID: id,
+				State:       ProcessingStateFailed,
+				UpdatedAt:   now,
+			}
+		})
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&recipientStates).Error; err != nil {
+			return fmt.Errorf("failed to insert recipient states: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire messages: %w", err)
+	}
+
+	return lo.Map(rows, func(row expiredRow, _ int) ExpiredMessage {
+		return ExpiredMessage{
+			ID:       row.ExtID,
+			DeviceID: row.DeviceID,
+			UserID:   row.UserID,
+		}
+	}), nil
+}
+
+// countPending returns the number of pending messages matching the filter.
+func (r *Repository) countPending(ctx context.Context, filter SelectFilter) (int64, error) {
+	var total int64
+	if err := applyFilter(r.db.WithContext(ctx).Model((*messageModel)(nil)), filter).
+		Where("messages.state = ?", ProcessingStatePending).
+		Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	return total, nil
+}
+
+type pendingRow struct {
+	ID       uint64
+	ExtID    string
+	DeviceID string
+}
+
+// updatePendingBatch applies updates to up to limit pending messages matching the filter
+// with internal ID greater than afterID. The updated messages are returned in ID order.
+// A state change is recorded in the messages state history.
+func (r *Repository) updatePendingBatch(
+	ctx context.Context,
+	filter SelectFilter,
+	afterID uint64,
+	limit int,
+	updates map[string]any,
+) ([]pendingRow, error) {
+	rows := []pendingRow{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := applyFilter(tx.Model((*messageModel)(nil)), filter).
+			Select("messages.id", "messages.ext_id", "messages.device_id").
+			Where("messages.state = ?", ProcessingStatePending).
+			Where("messages.id > ?", afterID).
+			Order("messages.id").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to select pending messages: %w", err)
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := lo.Map(rows, func(row pendingRow, _ int) uint64 { return row.ID })
+		if err := tx.Model((*messageModel)(nil)).
+			Where("id IN ?", ids).
+			Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update pending messages: %w", err)
+		}
+
+		state, ok := updates["state"].(ProcessingState)
+		if !ok {
+			return nil
+		}
+
+		now := time.Now()
+		states := lo.Map(ids, func(id uint64, _ int) messageStateModel {
+			return messageStateModel{
+				ID:        0,
+				MessageID: id,
+				State:     state,
+				UpdatedAt: now,
+			}
+		})
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&states).Error; err != nil {
+			return fmt.Errorf("failed to insert message states: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update messages batch: %w", err)
+	}
+
+	return rows, nil
+}
+
+// CountStates returns the number of messages matching the filter grouped by state.
+func (r *Repository) CountStates(ctx context.Context, filter SelectFilter) (map[ProcessingState]int64, error) {
+	type stateCount struct {
+		State ProcessingState
+		Count int64
+	}
+
+	var rows []stateCount
+	if err := applyFilter(r.db.WithContext(ctx).Model((*messageModel)(nil)), filter).
+		Select("messages.state AS state", "COUNT(*) AS count").
+		Group("messages.state").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count messages by state: %w", err)
+	}
+
+	return lo.SliceToMap(rows, func(row stateCount) (ProcessingState, int64) {
+		return row.State, row.Count
+	}), nil
+}
+
+// insertBatch inserts several messages with a single statement.
+func (r *Repository) insertBatch(ctx context.Context, messages []*messageModel) error {
+	err := r.db.WithContext(ctx).Omit("Device").Create(messages).Error
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) || mysql.IsDuplicateKeyViolation(err) {
+		return ErrMessageAlreadyExists
+	}
+
+	return fmt.Errorf("failed to insert messages: %w", err)
+}
+
+// insertPaced stores the messages together with their drip slots. The rows of the
+// devices are locked for update, so concurrent requests on any replica assign the
+// slots one after another and see the slots of each other.
+func (r *Repository) insertPaced(
+	ctx context.Context,
+	messages []*messageModel,
+	assign func(repo *Repository) error,
+) error {
+	deviceIDs := lo.Uniq(lo.Map(messages, func(item *messageModel, _ int) string { return item.DeviceID }))
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked []string
+		if err := tx.Table("devices").
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Where("id IN ?", deviceIDs).
+			Order("id").
+			Pluck("id", &locked).Error; err != nil {
+			return fmt.Errorf("failed to lock devices: %w", err)
+		}
+
+		repo := NewRepository(tx)
+		if err := assign(repo); err != nil {
+			return err
+		}
+
+		return repo.insertBatch(ctx, messages)
+	})
+}
+
+// lastPacedSlot returns the latest slot assigned to a pending paced message of the device.
+// When simNumber is not nil only the messages for that SIM are considered; zero stands for the default SIM.
+func (r *Repository) lastPacedSlot(ctx context.Context, deviceID string, simNumber *uint8) (*time.Time, error) {
+	query := r.db.WithContext(ctx).
+		Model((*messageModel)(nil)).
+		Where("device_id = ? AND state = ? AND is_paced = 1", deviceID, ProcessingStatePending)
+	if simNumber != nil {
+		if *simNumber == 0 {
+			query = query.Where("sim_number IS NULL")
+		} else {
+			query = query.Where("sim_number = ?", *simNumber)
+		}
+	}
+
+	var last *time.Time
+	if err := query.Select("MAX(schedule_at)").Scan(&last).Error; err != nil {
+		return nil, fmt.Errorf("failed to get last paced slot: %w", err)
+	}
+
+	return last, nil
+}
+
+// SelectSlotsArrived returns the devices with pending paced messages whose slots are within (from, to].
+func (r *Repository) SelectSlotsArrived(ctx context.Context, from, to time.Time) ([]DeviceRef, error) {
+	var rows []DeviceRef
+	if err := r.db.WithContext(ctx).
+		Model((*messageModel)(nil)).
+		Distinct("messages.device_id", "devices.user_id").
+		Joins("JOIN devices ON messages.device_id = devices.id").
+		Where("messages.state = ? AND messages.is_paced = 1", ProcessingStatePending).
+		Where("messages.schedule_at > ? AND messages.schedule_at <= ?", from, to).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to select devices with arrived slots: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *Repository) getLink(ctx context.Context, code string) (messageLinkModel, error) {
+	var link messageLinkModel
+	if err := r.db.WithContext(ctx).Where("code = ?", code).Take(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return link, ErrLinkNotFound
+		}
+		return link, fmt.Errorf("failed to get link: %w", err)
+	}
+
+	return link, nil
+}
+
+// insertClick records a click on the link. The recipient is stored if the message has only one:
+// tracking requires a single recipient, older messages may have several.
+func (r *Repository) insertClick(ctx context.Context, link messageLinkModel, userAgent string, at time.Time) error {
+	var recipientIDs []uint64
+	if err := r.db.WithContext(ctx).
+		Model((*messageRecipientModel)(nil)).
+		Where("message_id = ?", link.MessageID).
+		Limit(2). //nolint:mnd // more than one recipient can't be attributed
+		Pluck("id", &recipientIDs).Error; err != nil {
+		return fmt.Errorf("failed to select recipients: %w", err)
+	}
+
+	click := messageLinkClickModel{
+		ID:          0,
+		LinkID:      link.ID,
+		RecipientID: nil,
+		UserAgent:   userAgent,
+		ClickedAt:   at,
+		Recipient:   nil,
+	}
+	if len(recipientIDs) == 1 {
+		click.RecipientID = &recipientIDs[0]
+	}
+
+	if err := r.db.WithContext(ctx).Create(&click).Error; err != nil {
+		return fmt.Errorf("failed to insert click: %w", err)
+	}
+
+	return nil
+}
+
+// linkClicks returns the click statistics of the message links.
+func (r *Repository) linkClicks(ctx context.Context, messageID uint64) ([]LinkClicks, error) {
+	var links []messageLinkModel
+	if err := r.db.WithContext(ctx).
+		Where("message_id = ?", messageID).
+		Order("id").
+		Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to select links: %w", err)
+	}
+	if len(links) == 0 {
+		return []LinkClicks{}, nil
+	}
+
+	ids := lo.Map(links, func(item messageLinkModel, _ int) uint64 { return item.ID })
+
+	type totalRow struct {
+		LinkID uint64
+		Clicks int64
+		First  time.Time
+		Last   time.Time
+	}
+	var totals []totalRow
+	if err := r.db.WithContext(ctx).
+		Model((*messageLinkClickModel)(nil)).
+		Select("link_id, COUNT(*) AS clicks, MIN(clicked_at) AS first, MAX(clicked_at) AS last").
+		Where("link_id IN ?", ids).
+		Group("link_id").
+		Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to count clicks: %w", err)
+	}
+
+	type recipientRow struct {
+		LinkID      uint64
+		PhoneNumber string
+		Clicks      int64
+	}
+	var recipients []recipientRow
+	if err := r.db.WithContext(ctx).
+		Table("message_link_clicks AS c").
+		Select("c.link_id, r.phone_number, COUNT(*) AS clicks").
+		Joins("JOIN message_recipients AS r ON r.id = c.recipient_id").
+		Where("c.link_id IN ?", ids).
+		Group("c.link_id, r.phone_number").
+		Scan(&recipients).Error; err != nil {
+		return nil, fmt.Errorf("failed to count clicks by recipient: %w", err)
+	}
+
+	totalsByLink := lo.KeyBy(totals, func(item totalRow) uint64 { return item.LinkID })
+
+	return lo.Map(links, func(link messageLinkModel, _ int) LinkClicks {
+		stats := LinkClicks{
+			Code:       link.Code,
+			URL:        link.URL,
+			Clicks:     0,
+			Recipients: map[string]int64{},
+			FirstClick: nil,
+			LastClick:  nil,
+		}
+		if total, ok := totalsByLink[link.ID]; ok {
+			stats.Clicks = total.Clicks
+			stats.FirstClick = &total.First
+			stats.LastClick = &total.Last
+		}
+		for _, row := range recipients {
+			if row.LinkID == link.ID {
+				stats.Recipients[row.PhoneNumber] = row.Clicks
+			}
+		}
+		return stats
+	}), nil
+}
+
+// countClicks returns the number of clicks on the user's links in [from, to) grouped by period.
+func (r *Repository) countClicks(
+	ctx context.Context,
+	userID string,
+	from, to time.Time,
+	period ClicksPeriod,
+) ([]ClicksBucket, error) {
+	format := "%Y-%m-%d 00:00:00"
+	if period == ClicksPeriodHour {
+		format = "%Y-%m-%d %H:00:00"
+	}
+
+	type bucketRow struct {
+		Bucket string
+		Clicks int64
+		Links  int64
+	}
+	var rows []bucketRow
+	if err := r.db.WithContext(ctx).
+		Table("message_link_clicks AS c").
+		Select("DATE_FORMAT(c.clicked_at, ?) AS bucket, COUNT(*) AS clicks, COUNT(DISTINCT c.link_id) AS links", format).
+		Joins("JOIN message_links AS l ON l.id = c.link_id").
+		Joins("JOIN messages AS m ON m.id = l.message_id").
+		Joins("JOIN devices AS d ON d.id = m.device_id").
+		Where("d.user_id = ?", userID).
+		Where("c.clicked_at >= ? AND c.clicked_at < ?", from, to).
+		Group("bucket").
+		Order("bucket").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count clicks: %w", err)
+	}
+
+	buckets := make([]ClicksBucket, 0, len(rows))
+	for _, row := range rows {
+		start, err := time.ParseInLocation(time.DateTime, row.Bucket, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse clicks bucket: %w", err)
+		}
+		buckets = append(buckets, ClicksBucket{Start: start, Clicks: row.Clicks, Links: row.Links})
+	}
+
+	return buckets, nil
+}
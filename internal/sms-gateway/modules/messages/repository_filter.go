@@ -28,11 +28,18 @@ const (
 
 type SelectFilter struct {
 	ExtID     string
+	ExtIDs    []string
 	UserID    string
 	DeviceID  string
 	StartDate time.Time
 	EndDate   time.Time
 	State     []ProcessingState
+
+	CampaignID string
+	// SlotArrivedBy excludes paced messages whose delivery slot is after the time.
+	SlotArrivedBy time.Time
+	// ExcludeTypes excludes the messages of the types.
+	ExcludeTypes []MessageType
 }
 
 func (f *SelectFilter) WithExtID(extID string) *SelectFilter {
@@ -40,6 +47,11 @@ func (f *SelectFilter) WithExtID(extID string) *SelectFilter {
 	return f
 }
 
+func (f *SelectFilter) WithExtIDs(extIDs ...string) *SelectFilter {
+	f.ExtIDs = append(f.ExtIDs, extIDs...)
+	return f
+}
+
 func (f *SelectFilter) WithUserID(userID string) *SelectFilter {
 	f.UserID = userID
 	return f
@@ -56,6 +68,21 @@ func (f *SelectFilter) WithDateRange(start, end time.Time) *SelectFilter {
 	return f
 }
 
+func (f *SelectFilter) WithCampaignID(campaignID string) *SelectFilter {
+	f.CampaignID = campaignID
+	return f
+}
+
+func (f *SelectFilter) WithSlotArrivedBy(t time.Time) *SelectFilter {
+	f.SlotArrivedBy = t
+	return f
+}
+
+func (f *SelectFilter) WithoutType(messageType MessageType) *SelectFilter {
+	f.ExcludeTypes = append(f.ExcludeTypes, messageType)
+	return f
+}
+
 func (f *SelectFilter) WithState(state ProcessingState) *SelectFilter {
 	f.State = append(f.State, state)
 	return f
@@ -0,0 +1,98 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// reviewDeclinedError is stored as the recipient error of declined messages.
+const reviewDeclinedError = "declined by content review"
+
+// ReviewMessage approves or declines a quarantined message. Approved messages become
+// pending and are sent to the device, declined ones fail.
+//
+// Users review messages quarantined by their own policy. Messages quarantined by the
+// server policy are reviewed by the server reviewers, who may review messages of any user.
+func (s *Service) ReviewMessage(ctx context.Context, userID, id string, approve bool) (*MessageState, error) {
+	reviewer := slices.Contains(s.config.ContentReviewers, userID)
+
+	ownerID := userID
+	message, err := s.messages.get(
+		*new(SelectFilter).WithExtID(id).WithUserID(userID),
+		*new(SelectOptions).IncludeRecipients(),
+	)
+	if errors.Is(err, ErrMessageNotFound) && reviewer {
+		message, err = s.messages.get(
+			*new(SelectFilter).WithExtID(id).WithState(ProcessingStateQuarantined),
+			*new(SelectOptions).IncludeRecipients().IncludeDevice(),
+		)
+		ownerID = message.Device.UserID
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if message.State != ProcessingStateQuarantined {
+		return nil, ErrMessageNotQuarantined
+	}
+
+	verdict, err := message.GetContentVerdict()
+	if err != nil {
+		return nil, err
+	}
+	if verdict != nil && verdict.Source == ContentPolicySourceServer && !reviewer {
+		return nil, ErrReviewForbidden
+	}
+
+	state := ProcessingStateFailed
+	var reason *string
+	if approve {
+		state = ProcessingStatePending
+	} else {
+		reason = lo.ToPtr(reviewDeclinedError)
+	}
+
+	now := time.Now()
+	message.State = state
+	message.States = []messageStateModel{{ID: 0, MessageID: message.ID, State: state, UpdatedAt: now}}
+	for i := range message.Recipients {
+		message.Recipients[i].State = state
+		message.Recipients[i].Error = reason
+		message.Recipients[i].States = []messageRecipientStateModel{
+			{ID: 0, RecipientID: message.Recipients[i].ID, State: state, UpdatedAt: now},
+		}
+	}
+
+	if updErr := s.messages.UpdateState(&message, ProcessingStateQuarantined); updErr != nil {
+		if errors.Is(updErr, ErrStateConflict) {
+			return nil, ErrMessageNotQuarantined
+		}
+		return nil, updErr
+	}
+
+	if cacheErr := s.cache.Delete(ctx, ownerID, id); cacheErr != nil {
+		s.logger.Warn("failed to invalidate message cache", zap.String("id", id), zap.Error(cacheErr))
+	}
+	s.metrics.IncTotal(string(state))
+
+	if approve {
+		go func(userID, deviceID string) {
+			if ntfErr := s.eventsSvc.Notify(userID, &deviceID, events.NewMessageEnqueuedEvent()); ntfErr != nil {
+				s.logger.Error(
+					"failed to notify device",
+					zap.Error(ntfErr),
+					zap.String("user_id", userID),
+					zap.String("device_id", deviceID),
+				)
+			}
+		}(ownerID, message.DeviceID)
+	}
+
+	return s.GetState(ownerID, id)
+}
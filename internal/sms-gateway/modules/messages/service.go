@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,28 +13,48 @@ import (
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
 	"github.com/capcom6/go-helpers/anys"
 	"github.com/capcom6/go-helpers/slices"
-	"github.com/nyaruka/phonenumbers"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
 )
 
 type EnqueueOptions struct {
 	SkipPhoneValidation bool
+	// PhoneRegion overrides the default region used to parse phone numbers.
+	PhoneRegion string
+	// Normalize overrides the user's default text normalization rules when set.
+	Normalize *NormalizeOptions
+	Drip      DripOptions
+	// TrackLinks replaces the links in the text with short links counting clicks.
+	TrackLinks bool
+}
+
+// BatchItem is a message to enqueue together with the device selected for it.
+type BatchItem struct {
+	Device  devices.Device
+	Message MessageInput
 }
 
 type Service struct {
 	config Config
 
-	limiter   *Limiter
-	messages  *Repository
-	eventsSvc *events.Service
+	limiter     *Limiter
+	messages    *Repository
+	eventsSvc   *events.Service
+	settingsSvc *settings.Service
 
 	metrics       *metrics
 	cache         *stateCache
+	dups          *dupCache
 	hashingWorker *hashingWorker
 
+	jobs       *jobCache
+	bulkWorker *bulkWorker
+
+	statesListener *statesListener
+
 	logger *zap.Logger
 	idgen  func() string
 }
@@ -44,25 +65,39 @@ func NewService(
 	limiter *Limiter,
 	messages *Repository,
 	eventsSvc *events.Service,
+	settingsSvc *settings.Service,
 
 	metrics *metrics,
 	cache *stateCache,
+	dups *dupCache,
 	hashingTask *hashingWorker,
 
+	jobs *jobCache,
+	bulkWorker *bulkWorker,
+
+	statesListener *statesListener,
+
 	logger *zap.Logger,
 	idgen db.IDGen,
 ) *Service {
 	return &Service{
 		config: config,
 
-		limiter:   limiter,
-		messages:  messages,
-		eventsSvc: eventsSvc,
+		limiter:     limiter,
+		messages:    messages,
+		eventsSvc:   eventsSvc,
+		settingsSvc: settingsSvc,
 
 		metrics:       metrics,
 		cache:         cache,
+		dups:          dups,
 		hashingWorker: hashingTask,
 
+		jobs:       jobs,
+		bulkWorker: bulkWorker,
+
+		statesListener: statesListener,
+
 		logger: logger,
 		idgen:  idgen,
 	}
@@ -75,14 +110,31 @@ func (s *Service) RunBackgroundTasks(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Go(func() {
 		s.limiter.Run(ctx)
 	})
+	wg.Go(func() {
+		s.bulkWorker.Run(ctx)
+	})
+	wg.Go(func() {
+		s.statesListener.Run(ctx)
+	})
 }
 
-func (s *Service) SelectPending(deviceID string, order Order) ([]Message, error) {
+func (s *Service) SelectPending(device devices.Device, order Order) ([]Message, error) {
+	// messages queued before the pause are held back until the device is resumed
+	if device.IsPaused() {
+		return []Message{}, nil
+	}
+
 	if order == "" {
 		order = MessagesOrderLIFO
 	}
 
-	messages, err := s.messages.listPending(deviceID, order)
+	// the app without MMS support would get a message without text and data
+	var exclude []MessageType
+	if !device.Supports(devices.FeatureMms) {
+		exclude = append(exclude, MessageTypeMms)
+	}
+
+	messages, err := s.messages.listPending(device.ID, order, exclude...)
 	if err != nil {
 		return nil, err
 	}
@@ -90,19 +142,40 @@ func (s *Service) SelectPending(deviceID string, order Order) ([]Message, error)
 	return slices.MapOrError(messages, messageToDomain) //nolint:wrapcheck // already wrapped
 }
 
-func (s *Service) UpdateState(device *devices.Device, message MessageStateInput) error {
+// UpdateState applies a state update reported by the device.
+// Transitions not allowed by the state machine are not stored and are reported in the result.
+func (s *Service) UpdateState(device *devices.Device, message MessageStateInput) (*StateUpdate, error) {
 	existing, err := s.messages.get(
 		*new(SelectFilter).WithExtID(message.ID).WithDeviceID(device.ID),
-		*new(SelectOptions).IncludeContent(),
+		*new(SelectOptions).IncludeContent().IncludeRecipients().IncludeStates(),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if message.State == ProcessingStatePending {
 		message.State = ProcessingStateProcessed
 	}
 
+	prevState := existing.State
+	history := lo.Associate(
+		existing.States,
+		func(item messageStateModel) (ProcessingState, time.Time) { return item.State, item.UpdatedAt },
+	)
+
+	result := checkMessageTransition(prevState, history, message.State, message.States)
+	s.metrics.IncStateTransition(transitionLevelMessage, result)
+	if result != StateUpdateApplied {
+		s.logger.Warn(
+			"message state update skipped",
+			zap.String("id", existing.ExtID),
+			zap.String("from", string(prevState)),
+			zap.String("to", string(message.State)),
+			zap.String("result", string(result)),
+		)
+		return &StateUpdate{Result: result, State: prevState}, nil
+	}
+
 	existing.State = message.State
 	existing.States = lo.MapToSlice(
 		message.States,
@@ -115,29 +188,23 @@ func (s *Service) UpdateState(device *devices.Device, message MessageStateInput)
 			}
 		},
 	)
-	existing.Recipients = s.recipientsStateToModel(message.Recipients, existing.IsHashed)
-
-	if updErr := s.messages.UpdateState(&existing); updErr != nil {
-		return updErr
-	}
+	existing.Recipients = s.mergeRecipientsState(
+		existing.Recipients,
+		s.recipientsStateToModel(message.Recipients, existing.IsHashed),
+		message.States,
+	)
 
-	state, err := existing.toStateDomain()
-	if err != nil {
-		return err
+	if updErr := s.messages.UpdateState(&existing, prevState); updErr != nil {
+		return nil, updErr
 	}
 
-	if cacheErr := s.cache.Set(
-		context.Background(),
-		device.UserID,
-		existing.ExtID,
-		state,
-	); cacheErr != nil {
-		s.logger.Warn("failed to cache message", zap.String("id", existing.ExtID), zap.Error(cacheErr))
+	if cacheErr := s.cache.Delete(context.Background(), device.UserID, existing.ExtID); cacheErr != nil {
+		s.logger.Warn("failed to invalidate message cache", zap.String("id", existing.ExtID), zap.Error(cacheErr))
 	}
 	s.hashingWorker.Enqueue(existing.ID)
 	s.metrics.IncTotal(string(existing.State))
 
-	return nil
+	return &StateUpdate{Result: StateUpdateApplied, State: existing.State}, nil
 }
 
 func (s *Service) SelectStates(
@@ -204,6 +271,25 @@ func (s *Service) GetState(userID string, id string) (*MessageState, error) {
 	return state, nil
 }
 
+// ForgetDevice drops the cached states of the device's messages for the users,
+// e.g. after the device moved to another user.
+func (s *Service) ForgetDevice(ctx context.Context, deviceID string, userIDs ...string) error {
+	ids, err := s.messages.selectExtIDs(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		for _, userID := range userIDs {
+			if cacheErr := s.cache.Delete(ctx, userID, id); cacheErr != nil {
+				return cacheErr
+			}
+		}
+	}
+
+	return nil
+}
+
 // CancelMessage transitions a pending message to Cancelling state.
 // Returns an error if the message is not in Pending state.
 func (s *Service) CancelMessage(userID string, id string) (*MessageState, error) {
@@ -238,6 +324,184 @@ func (s *Service) CancelMessage(userID string, id string) (*MessageState, error)
 	return s.GetState(userID, id)
 }
 
+// UpdateMessage edits a message that is still pending. The edit is rejected with
+// ErrMessageNotPending once the device has reported any progress. The changed fields
+// are recorded in the edits of the message.
+func (s *Service) UpdateMessage(
+	ctx context.Context,
+	userID string,
+	id string,
+	update MessageUpdate,
+	opts EnqueueOptions,
+) (*MessageState, error) {
+	message, err := s.messages.get(
+		*new(SelectFilter).WithExtID(id).WithUserID(userID),
+		*new(SelectOptions).IncludeContent().IncludeRecipients(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.State != ProcessingStatePending {
+		return nil, ErrMessageNotPending
+	}
+
+	var fields []string
+	phoneNumbers := lo.Map(message.Recipients, func(item messageRecipientModel, _ int) string { return item.PhoneNumber })
+
+	if update.TextContent != nil {
+		if message.Type != MessageTypeText {
+			return nil, ValidationError("text can only be changed for text messages")
+		}
+		if setErr := message.SetTextContent(*update.TextContent); setErr != nil {
+			return nil, fmt.Errorf("failed to set text content: %w", setErr)
+		}
+		fields = append(fields, "textMessage")
+	}
+
+	if update.PhoneNumbers != nil {
+		policy, policyErr := s.phonePolicy(userID, opts.PhoneRegion)
+		if policyErr != nil {
+			return nil, policyErr
+		}
+		if phoneErr := preparePhoneNumbers(
+			update.PhoneNumbers,
+			policy,
+			message.IsEncrypted || opts.SkipPhoneValidation,
+		); phoneErr != nil {
+			return nil, phoneErr
+		}
+		message.Recipients = lo.Map(update.PhoneNumbers, func(item string, _ int) messageRecipientModel {
+			return newMessageRecipient(item, ProcessingStatePending, nil)
+		})
+		phoneNumbers = update.PhoneNumbers
+		fields = append(fields, "phoneNumbers")
+	} else {
+		message.Recipients = nil
+	}
+
+	if update.SimNumber != nil {
+		message.SimNumber = update.SimNumber
+		fields = append(fields, "simNumber")
+	}
+	if update.ValidUntil != nil {
+		message.ValidUntil = update.ValidUntil
+		fields = append(fields, "validUntil")
+	}
+	if update.ScheduleAt != nil {
+		message.ScheduleAt = update.ScheduleAt
+		fields = append(fields, "scheduleAt")
+	}
+	if update.Priority != nil {
+		message.Priority = int8(*update.Priority)
+		fields = append(fields, "priority")
+	}
+
+	if message.ScheduleAt != nil && message.ValidUntil != nil && message.ScheduleAt.After(*message.ValidUntil) {
+		return nil, ValidationError("scheduleAt must be less than or equal to validUntil")
+	}
+
+	if update.TextContent != nil || update.PhoneNumbers != nil {
+		if contentErr := s.checkUpdatedContent(userID, &message, phoneNumbers); contentErr != nil {
+			return nil, contentErr
+		}
+	}
+
+	//nolint:exhaustruct // IDs are set on insert
+	edit := &messageEditModel{Fields: fields, EditedAt: time.Now()}
+	if updErr := s.messages.UpdatePending(ctx, &message, edit); updErr != nil {
+		return nil, updErr
+	}
+
+	if cacheErr := s.cache.Delete(ctx, userID, id); cacheErr != nil {
+		s.logger.Warn("failed to invalidate message cache", zap.String("id", id), zap.Error(cacheErr))
+	}
+
+	go func(userID, deviceID, messageID string) {
+		if ntfErr := s.eventsSvc.Notify(userID, &deviceID, events.NewMessageUpdatedEvent(messageID)); ntfErr != nil {
+			s.logger.Error(
+				"failed to notify device about update",
+				zap.Error(ntfErr),
+				zap.String("user_id", userID),
+				zap.String("device_id", deviceID),
+			)
+		}
+	}(userID, message.DeviceID, id)
+
+	return s.GetState(userID, id)
+}
+
+// CancelMessages starts a background job cancelling all pending messages matching the filter.
+func (s *Service) CancelMessages(ctx context.Context, userID string, filter SelectFilter) (*BulkJob, error) {
+	return s.startBulkJob(ctx, userID, BulkActionCancel, filter,
+		map[string]any{"state": ProcessingStateCancelling},
+		cancelledEvents,
+	)
+}
+
+// RescheduleMessages starts a background job setting ScheduleAt of all pending messages matching the filter.
+// A nil scheduleAt makes the messages available for sending immediately.
+func (s *Service) RescheduleMessages(
+	ctx context.Context,
+	userID string,
+	filter SelectFilter,
+	scheduleAt *time.Time,
+) (*BulkJob, error) {
+	return s.startBulkJob(ctx, userID, BulkActionReschedule, filter,
+		map[string]any{"schedule_at": scheduleAt},
+		enqueuedEvents,
+	)
+}
+
+// GetBulkJob returns the progress of a bulk job.
+func (s *Service) GetBulkJob(ctx context.Context, userID, id string) (*BulkJob, error) {
+	return s.jobs.Get(ctx, userID, id)
+}
+
+func (s *Service) startBulkJob(
+	ctx context.Context,
+	userID string,
+	action BulkAction,
+	filter SelectFilter,
+	updates map[string]any,
+	notifications func(ids []string) []events.Event,
+) (*BulkJob, error) {
+	now := time.Now()
+	job := BulkJob{
+		ID:        s.idgen(),
+		Action:    action,
+		Status:    BulkJobStatusQueued,
+		Total:     0,
+		Processed: 0,
+		Error:     "",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.jobs.Set(ctx, userID, job); err != nil {
+		return nil, err
+	}
+
+	if err := s.bulkWorker.Enqueue(bulkTask{
+		userID:  userID,
+		job:     job,
+		filter:  filter,
+		updates: updates,
+		events:  notifications,
+	}); err != nil {
+		// the job will never run, don't leave it queued
+		job.Status = BulkJobStatusFailed
+		job.Error = err.Error()
+		job.UpdatedAt = time.Now()
+		if setErr := s.jobs.Set(ctx, userID, job); setErr != nil {
+			s.logger.Warn("failed to save job status", zap.String("job_id", job.ID), zap.Error(setErr))
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
 func (s *Service) Enqueue(
 	ctx context.Context,
 	device devices.Device,
@@ -253,17 +517,58 @@ func (s *Service) Enqueue(
 		return nil, err
 	}
 
-	msg, err := s.prepareMessage(device, message, opts)
+	policy, err := s.phonePolicy(device.UserID, opts.PhoneRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Normalize, err = s.normalizeOptions(device.UserID, opts.Normalize); err != nil {
+		return nil, err
+	}
+
+	content, err := s.contentPolicies(device.UserID)
 	if err != nil {
 		return nil, err
 	}
 
+	msg, report, err := s.prepareMessage(device, message, policy, content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dupPolicy, err := s.duplicatePolicy(device.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// recipients are claimed right before storing so that the claim is released if storing fails
+	var claimed []string
+	if dupPolicy != nil {
+		originalID, dupClaimed, dupErr := s.suppressDuplicates(ctx, device.UserID, msg, *dupPolicy)
+		if dupErr != nil {
+			return nil, dupErr
+		}
+		if originalID != "" {
+			return s.GetState(device.UserID, originalID)
+		}
+		claimed = dupClaimed
+	}
+
 	state, err := msg.toStateDomain()
 	if err != nil {
+		s.dups.Release(ctx, device.UserID, claimed)
 		return nil, err
 	}
+	state.Normalization = report
 
-	if insErr := s.messages.Insert(msg); insErr != nil {
+	if insErr := s.insert(
+		ctx,
+		device.UserID,
+		map[string][]string{device.ID: device.Tags},
+		[]*messageModel{msg},
+		opts.Drip,
+	); insErr != nil {
+		s.dups.Release(ctx, device.UserID, claimed)
 		return state, insErr
 	}
 
@@ -277,6 +582,11 @@ func (s *Service) Enqueue(
 	}
 	s.metrics.IncTotal(string(msg.State))
 
+	// quarantined messages are not sent until approved
+	if msg.State == ProcessingStateQuarantined {
+		return state, nil
+	}
+
 	go func(userID, deviceID string) {
 		if ntfErr := s.eventsSvc.Notify(userID, &deviceID, events.NewMessageEnqueuedEvent()); ntfErr != nil {
 			s.logger.Error(
@@ -291,31 +601,181 @@ func (s *Service) Enqueue(
 	return state, nil
 }
 
-func (s *Service) prepareMessage(
-	device devices.Device,
-	message MessageInput,
+// insert stores the messages, assigning the drip slots first when pacing is enabled.
+// deviceTags maps the IDs of the devices to their groups.
+func (s *Service) insert(
+	ctx context.Context,
+	userID string,
+	deviceTags map[string][]string,
+	msgs []*messageModel,
+	drip DripOptions,
+) error {
+	if !drip.Enabled() {
+		return s.messages.insertBatch(ctx, msgs)
+	}
+
+	return s.messages.insertPaced(ctx, msgs, func(repo *Repository) error {
+		return s.assignSlots(ctx, repo, userID, deviceTags, msgs, drip)
+	})
+}
+
+// EnqueueBatch validates the messages and stores them all at once.
+// Drip slots are assigned across the whole batch.
+func (s *Service) EnqueueBatch(
+	ctx context.Context,
+	userID string,
+	items []BatchItem,
 	opts EnqueueOptions,
-) (*messageModel, error) {
-	var phone string
-	var err error
-	for i, v := range message.PhoneNumbers {
-		if message.IsEncrypted || opts.SkipPhoneValidation {
-			phone = v
-		} else {
-			if phone, err = cleanPhoneNumber(v); err != nil {
-				return nil, fmt.Errorf("failed to use phone in row %d: %w", i+1, err)
-			}
+) ([]MessageState, error) {
+	deviceTags := map[string][]string{}
+	for _, item := range items {
+		if _, ok := deviceTags[item.Device.ID]; ok {
+			continue
 		}
+		deviceTags[item.Device.ID] = item.Device.Tags
 
-		message.PhoneNumbers[i] = phone
+		if err := s.limiter.Refresh(ctx, item.Device.ID); err != nil {
+			s.logger.Error("failed to refresh queue stats", zap.String("device_id", item.Device.ID), zap.Error(err))
+		}
+		if err := s.limiter.Check(ctx, item.Device.ID); err != nil {
+			return nil, err
+		}
 	}
 
-	seen := make(map[string]struct{}, len(message.PhoneNumbers))
-	for _, phone := range message.PhoneNumbers {
-		if _, ok := seen[phone]; ok {
-			return nil, ValidationError("phone numbers must be unique")
+	policy, err := s.phonePolicy(userID, opts.PhoneRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Normalize, err = s.normalizeOptions(userID, opts.Normalize); err != nil {
+		return nil, err
+	}
+
+	content, err := s.contentPolicies(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]*messageModel, 0, len(items))
+	reports := make([]*NormalizationReport, 0, len(items))
+	for i, item := range items {
+		msg, report, err := s.prepareMessage(item.Device, item.Message, policy, content, opts)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
 		}
-		seen[phone] = struct{}{}
+		msgs = append(msgs, msg)
+		reports = append(reports, report)
+	}
+
+	dupPolicy, err := s.duplicatePolicy(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// recipients are claimed right before storing so that the claims are released if storing fails
+	originals := make([]string, len(msgs))
+	var claimed []string
+	if dupPolicy != nil {
+		if originals, claimed, err = s.suppressBatchDuplicates(ctx, userID, msgs, *dupPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	states := make([]*MessageState, len(msgs))
+	stored := make([]*messageModel, 0, len(msgs))
+	for i, msg := range msgs {
+		if originals[i] != "" {
+			continue
+		}
+
+		state, stateErr := msg.toStateDomain()
+		if stateErr != nil {
+			s.dups.Release(ctx, userID, claimed)
+			return nil, stateErr
+		}
+		state.Normalization = reports[i]
+
+		states[i] = state
+		stored = append(stored, msg)
+	}
+
+	if len(stored) > 0 {
+		if insErr := s.insert(ctx, userID, deviceTags, stored, opts.Drip); insErr != nil {
+			s.dups.Release(ctx, userID, claimed)
+			return nil, insErr
+		}
+	}
+
+	notify := map[string]struct{}{}
+	batchStates := make(map[string]*MessageState, len(stored))
+	for i, msg := range msgs {
+		if states[i] == nil {
+			continue
+		}
+		batchStates[msg.ExtID] = states[i]
+
+		if cacheErr := s.cache.Set(context.Background(), userID, msg.ExtID, states[i]); cacheErr != nil {
+			s.logger.Warn("failed to cache message", zap.String("id", msg.ExtID), zap.Error(cacheErr))
+		}
+		s.metrics.IncTotal(string(msg.State))
+
+		// quarantined messages are not sent until approved
+		if msg.State != ProcessingStateQuarantined {
+			notify[msg.DeviceID] = struct{}{}
+		}
+	}
+
+	// suppressed messages report the state of their originals
+	for i, originalID := range originals {
+		if originalID == "" {
+			continue
+		}
+
+		if state, ok := batchStates[originalID]; ok {
+			states[i] = state
+			continue
+		}
+
+		state, stateErr := s.GetState(userID, originalID)
+		if stateErr != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, stateErr)
+		}
+		states[i] = state
+	}
+
+	go func(userID string, deviceIDs []string) {
+		for _, deviceID := range deviceIDs {
+			if ntfErr := s.eventsSvc.Notify(userID, &deviceID, events.NewMessageEnqueuedEvent()); ntfErr != nil {
+				s.logger.Error(
+					"failed to notify device",
+					zap.Error(ntfErr),
+					zap.String("user_id", userID),
+					zap.String("device_id", deviceID),
+				)
+			}
+		}
+	}(userID, lo.Keys(notify))
+
+	return lo.FromSlicePtr(states), nil
+}
+
+func (s *Service) prepareMessage(
+	device devices.Device,
+	message MessageInput,
+	policy PhonePolicy,
+	content []ContentPolicy,
+	opts EnqueueOptions,
+) (*messageModel, *NormalizationReport, error) {
+	if message.MmsContent != nil && !device.Supports(devices.FeatureMms) {
+		return nil, nil, ValidationError(fmt.Sprintf("device %s doesn't support MMS messages", device.ID))
+	}
+
+	if err := preparePhoneNumbers(
+		message.PhoneNumbers,
+		policy,
+		message.IsEncrypted || opts.SkipPhoneValidation,
+	); err != nil {
+		return nil, nil, err
 	}
 
 	validUntil := message.ValidUntil
@@ -327,7 +787,7 @@ func (s *Service) prepareMessage(
 	}
 
 	if message.ScheduleAt != nil && validUntil != nil && message.ScheduleAt.After(*validUntil) {
-		return nil, ValidationError("scheduleAt must be less than or equal to validUntil")
+		return nil, nil, ValidationError("scheduleAt must be less than or equal to validUntil")
 	}
 
 	msg := newMessageModel(
@@ -342,24 +802,231 @@ func (s *Service) prepareMessage(
 		message.IsEncrypted,
 	)
 
+	var report *NormalizationReport
+	subject := ContentSubject{Text: "", PhoneNumbers: message.PhoneNumbers, Encrypted: false}
 	switch {
 	case message.TextContent != nil:
-		if setErr := msg.SetTextContent(*message.TextContent); setErr != nil {
-			return nil, fmt.Errorf("failed to set text content: %w", setErr)
+		textContent := *message.TextContent
+		if opts.Normalize != nil && opts.Normalize.Enabled() && !message.IsEncrypted {
+			textContent.Text, report = normalizeText(textContent.Text, *opts.Normalize)
+		}
+		subject.Encrypted = message.IsEncrypted
+		if !message.IsEncrypted {
+			subject.Text = textContent.Text
+		}
+		if opts.TrackLinks {
+			if trackErr := s.trackLinks(msg, &textContent, message.IsEncrypted); trackErr != nil {
+				return nil, nil, trackErr
+			}
+		}
+		if setErr := msg.SetTextContent(textContent); setErr != nil {
+			return nil, nil, fmt.Errorf("failed to set text content: %w", setErr)
 		}
 	case message.DataContent != nil:
 		if setErr := msg.SetDataContent(*message.DataContent); setErr != nil {
-			return nil, fmt.Errorf("failed to set data content: %w", setErr)
+			return nil, nil, fmt.Errorf("failed to set data content: %w", setErr)
+		}
+	case message.MmsContent != nil:
+		if len(message.MmsContent.Attachments) == 0 {
+			return nil, nil, ValidationError("mms message must have attachments")
+		}
+		subject.Encrypted = message.IsEncrypted
+		if !message.IsEncrypted {
+			subject.Text = message.MmsContent.Text
+		}
+		if setErr := msg.SetMmsContent(*message.MmsContent); setErr != nil {
+			return nil, nil, fmt.Errorf("failed to set mms content: %w", setErr)
 		}
 	default:
-		return nil, ErrNoContent
+		return nil, nil, ErrNoContent
+	}
+
+	verdict := evaluateContent(content, subject)
+	if verdict != nil && verdict.Action == ContentActionReject {
+		return nil, nil, &ContentRejectedError{Reasons: verdict.Reasons}
+	}
+	if setErr := msg.SetContentVerdict(verdict); setErr != nil {
+		return nil, nil, fmt.Errorf("failed to set content verdict: %w", setErr)
 	}
 
 	if msg.ExtID == "" {
 		msg.ExtID = s.idgen()
 	}
 
-	return msg, nil
+	return msg, report, nil
+}
+
+// ValidatePhoneNumbers normalizes the phone numbers in place and checks that they
+// are unique and accepted by the user's phone number policy.
+func (s *Service) ValidatePhoneNumbers(userID string, phoneNumbers []string) error {
+	policy, err := s.phonePolicy(userID, "")
+	if err != nil {
+		return err
+	}
+
+	return preparePhoneNumbers(phoneNumbers, policy, false)
+}
+
+// NormalizePhoneNumbers returns the phone numbers in the form they are enqueued
+// in with the user's phone number policy and the region, e.g. to match them
+// against the contacts. Numbers that can't be parsed are returned unchanged.
+func (s *Service) NormalizePhoneNumbers(userID, region string, phoneNumbers []string) ([]string, error) {
+	policy, err := s.phonePolicy(userID, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(phoneNumbers, func(phone string, _ int) string {
+		normalized, _ := cleanPhoneNumber(phone, policy)
+		return normalized
+	}), nil
+}
+
+// CountStates returns the number of the user's messages matching the filter grouped by state.
+func (s *Service) CountStates(ctx context.Context, userID string, filter SelectFilter) (map[ProcessingState]int64, error) {
+	filter.UserID = userID
+
+	return s.messages.CountStates(ctx, filter)
+}
+
+// phonePolicy returns the server policy with the user settings and the per-request region applied.
+func (s *Service) phonePolicy(userID, region string) (PhonePolicy, error) {
+	overrides, err := s.settingsSvc.GetPhoneNumbers(userID)
+	if err != nil {
+		if errors.Is(err, settings.ErrInvalidField) {
+			return PhonePolicy{}, ValidationError(fmt.Sprintf("invalid phone number settings: %s", err))
+		}
+		return PhonePolicy{}, fmt.Errorf("failed to get phone number settings: %w", err)
+	}
+
+	policy := s.config.Phone.withOverrides(*overrides)
+	if region != "" {
+		policy.DefaultRegion = strings.ToUpper(region)
+	}
+
+	if validErr := policy.Validate(); validErr != nil {
+		return PhonePolicy{}, validErr
+	}
+
+	return policy, nil
+}
+
+// normalizeOptions returns the requested text normalization rules or the user's default ones.
+func (s *Service) normalizeOptions(userID string, requested *NormalizeOptions) (*NormalizeOptions, error) {
+	if requested != nil {
+		return requested, nil
+	}
+
+	rules, err := s.settingsSvc.GetTextNormalization(userID)
+	if err != nil {
+		if errors.Is(err, settings.ErrInvalidField) {
+			return nil, ValidationError(fmt.Sprintf("invalid text normalization settings: %s", err))
+		}
+		return nil, fmt.Errorf("failed to get text normalization settings: %w", err)
+	}
+
+	opts, err := ParseNormalizeOptions(rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid text normalization settings: %w", err)
+	}
+
+	return &opts, nil
+}
+
+// checkUpdatedContent evaluates the content policies against an edited message.
+// The device may already have the pending message, so it can't be quarantined and
+// edits violating a policy are rejected unless the action is flag.
+func (s *Service) checkUpdatedContent(userID string, message *messageModel, phoneNumbers []string) error {
+	content, err := s.contentPolicies(userID)
+	if err != nil {
+		return err
+	}
+
+	text, err := message.subjectText()
+	if err != nil {
+		return err
+	}
+
+	subject := ContentSubject{
+		Text:         text,
+		PhoneNumbers: phoneNumbers,
+		Encrypted:    message.IsEncrypted && message.Type != MessageTypeData,
+	}
+
+	verdict := evaluateContent(content, subject)
+	if verdict != nil && verdict.Action != ContentActionFlag {
+		return &ContentRejectedError{Reasons: verdict.Reasons}
+	}
+
+	if setErr := message.SetContentVerdict(verdict); setErr != nil {
+		return fmt.Errorf("failed to set content verdict: %w", setErr)
+	}
+
+	return nil
+}
+
+// contentPolicies returns the enabled content policies: the server-wide one and the user's own one.
+func (s *Service) contentPolicies(userID string) ([]ContentPolicy, error) {
+	policies := make([]ContentPolicy, 0, 2) //nolint:mnd // server and user policies
+	if s.config.Content.Enabled() {
+		policies = append(policies, s.config.Content)
+	}
+
+	rules, err := s.settingsSvc.GetContentPolicy(userID)
+	if err != nil {
+		if errors.Is(err, settings.ErrInvalidField) {
+			return nil, ValidationError(fmt.Sprintf("invalid content policy settings: %s", err))
+		}
+		return nil, fmt.Errorf("failed to get content policy settings: %w", err)
+	}
+	if rules == nil {
+		return policies, nil
+	}
+
+	policy, err := NewContentPolicy(ContentPolicySourceUser, ContentRules{
+		Keywords:       rules.Keywords,
+		Patterns:       rules.Patterns,
+		AllowedDomains: rules.AllowedDomains,
+		DeniedDomains:  rules.DeniedDomains,
+		MaxLinks:       rules.MaxLinks,
+		MaxRecipients:  rules.MaxRecipients,
+		Action:         ContentAction(rules.Action),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid content policy settings: %w", err)
+	}
+	if policy.Enabled() {
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// preparePhoneNumbers normalizes phone numbers in place and checks that they are unique.
+func preparePhoneNumbers(phoneNumbers []string, policy PhonePolicy, skipValidation bool) error {
+	var phone string
+	var err error
+	for i, v := range phoneNumbers {
+		if skipValidation {
+			phone = v
+		} else {
+			if phone, err = cleanPhoneNumber(v, policy); err != nil {
+				return fmt.Errorf("failed to use phone in row %d: %w", i+1, err)
+			}
+		}
+
+		phoneNumbers[i] = phone
+	}
+
+	seen := make(map[string]struct{}, len(phoneNumbers))
+	for _, phone := range phoneNumbers {
+		if _, ok := seen[phone]; ok {
+			return ValidationError("phone numbers must be unique")
+		}
+		seen[phone] = struct{}{}
+	}
+
+	return nil
 }
 
 /////////////////////////////////////////////////////////////////////////////
@@ -392,20 +1059,44 @@ func (s *Service) recipientsStateToModel(input []smsgateway.RecipientState, hash
 	return output
 }
 
-func cleanPhoneNumber(input string) (string, error) {
-	phone, err := phonenumbers.Parse(input, "RU")
-	if err != nil {
-		return input, ValidationError(fmt.Sprintf("failed to parse phone number: %s", err.Error()))
-	}
+// mergeRecipientsState returns the recipients whose state can be changed by the update.
+// Unknown recipients and disallowed transitions are skipped. The new recipient state
+// is added to its history with the message-level timestamp of the same state, if any.
+func (s *Service) mergeRecipientsState(
+	existing []messageRecipientModel,
+	updates []messageRecipientModel,
+	states map[string]time.Time,
+) []messageRecipientModel {
+	current := lo.KeyBy(existing, func(item messageRecipientModel) string { return item.PhoneNumber })
+	now := time.Now()
+
+	return lo.FilterMap(
+		updates,
+		func(item messageRecipientModel, _ int) (messageRecipientModel, bool) {
+			recipient, ok := current[item.PhoneNumber]
+			if !ok {
+				return item, false
+			}
 
-	if !phonenumbers.IsValidNumber(phone) {
-		return input, ValidationError("invalid phone number")
-	}
+			result := checkTransition(recipient.State, item.State)
+			s.metrics.IncStateTransition(transitionLevelRecipient, result)
+			if result != StateUpdateApplied {
+				return item, false
+			}
 
-	phoneNumberType := phonenumbers.GetNumberType(phone)
-	if phoneNumberType != phonenumbers.MOBILE && phoneNumberType != phonenumbers.FIXED_LINE_OR_MOBILE {
-		return input, ValidationError("not mobile phone number")
-	}
+			item.ID = recipient.ID
+			if item.State != recipient.State {
+				item.States = []messageRecipientStateModel{
+					{
+						ID:          0,
+						RecipientID: recipient.ID,
+						State:       item.State,
+						UpdatedAt:   lo.ValueOr(states, string(item.State), now),
+					},
+				}
+			}
 
-	return phonenumbers.Format(phone, phonenumbers.E164), nil
+			return item, true
+		},
+	)
 }
@@ -0,0 +1,54 @@
+//nolint:testpackage // prepareMessage is unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareMessageMmsFeature(t *testing.T) {
+	//nolint:exhaustruct // prepareMessage doesn't use the dependencies for MMS messages
+	svc := &Service{}
+
+	//nolint:exhaustruct // only the MMS content is needed
+	input := MessageInput{
+		MessageContent: MessageContent{
+			MmsContent: &MmsMessageContent{
+				Text:        "photo",
+				Attachments: []MmsAttachment{{ID: "attachment", Name: "photo.jpg", ContentType: "image/jpeg", Size: 1}},
+			},
+		},
+		ID:           "message",
+		PhoneNumbers: []string{"+79000000001"},
+	}
+	//nolint:exhaustruct // phone numbers are not validated
+	opts := EnqueueOptions{SkipPhoneValidation: true}
+
+	var device devices.Device
+	device.ID = "device"
+
+	_, _, err := svc.prepareMessage(device, input, PhonePolicy{}, nil, opts)
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	device.Features = []devices.Feature{devices.FeatureMms}
+	msg, _, err := svc.prepareMessage(device, input, PhonePolicy{}, nil, opts)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeMms, msg.Type)
+}
+
+func TestSelectPendingPausedDevice(t *testing.T) {
+	//nolint:exhaustruct // a paused device doesn't reach the repository
+	svc := &Service{}
+
+	var device devices.Device
+	device.ID = "device"
+	device.PausedAt = new(time.Time)
+
+	msgs, err := svc.SelectPending(device, MessagesOrderFIFO)
+	require.NoError(t, err)
+	require.Empty(t, msgs)
+}
@@ -0,0 +1,122 @@
+package messages
+
+import (
+	"slices"
+	"time"
+)
+
+// StateUpdateResult describes how an incoming state update was handled.
+type StateUpdateResult string
+
+const (
+	// StateUpdateApplied means the update was valid and has been stored.
+	StateUpdateApplied StateUpdateResult = "applied"
+	// StateUpdateIgnored means the update is older than the stored state and was skipped.
+	StateUpdateIgnored StateUpdateResult = "ignored"
+	// StateUpdateRejected means the update conflicts with the stored final state.
+	StateUpdateRejected StateUpdateResult = "rejected"
+)
+
+// StateUpdate is the outcome of a state update together with the resulting message state.
+type StateUpdate struct {
+	Result StateUpdateResult
+	State  ProcessingState
+}
+
+//nolint:gochecknoglobals // lookup tables
+var (
+	// stateTransitions lists the states reachable from each state.
+	// Staying in the same state is always allowed and is not listed here.
+	stateTransitions = map[ProcessingState][]ProcessingState{
+		ProcessingStatePending: {
+			ProcessingStateCancelling,
+			ProcessingStateCancelled,
+			ProcessingStateProcessed,
+			ProcessingStateSent,
+			ProcessingStateDelivered,
+			ProcessingStateFailed,
+		},
+		// quarantined messages are not sent to the device until approved
+		ProcessingStateQuarantined: {
+			ProcessingStatePending,
+			ProcessingStateFailed,
+		},
+		// the device may have picked the message up before it learned about the cancellation
+		ProcessingStateCancelling: {
+			ProcessingStateCancelled,
+			ProcessingStateProcessed,
+			ProcessingStateSent,
+			ProcessingStateDelivered,
+			ProcessingStateFailed,
+		},
+		ProcessingStateProcessed: {
+			ProcessingStateSent,
+			ProcessingStateDelivered,
+			ProcessingStateFailed,
+		},
+		ProcessingStateSent: {
+			ProcessingStateDelivered,
+			ProcessingStateFailed,
+		},
+		ProcessingStateDelivered: {},
+		ProcessingStateFailed:    {},
+		ProcessingStateCancelled: {},
+	}
+
+	// stateRanks orders states along the lifecycle. A disallowed transition to a
+	// lower rank is a stale report, to the same or higher rank is a conflict.
+	stateRanks = map[ProcessingState]int{
+		ProcessingStateQuarantined: 0,
+		ProcessingStatePending:     0,
+		ProcessingStateCancelling:  1,
+		ProcessingStateProcessed:   2,
+		ProcessingStateSent:        3,
+		ProcessingStateDelivered:   4,
+		ProcessingStateFailed:      4,
+		ProcessingStateCancelled:   4,
+	}
+)
+
+// checkTransition classifies the transition from one state to another.
+func checkTransition(from, to ProcessingState) StateUpdateResult {
+	if from == to || slices.Contains(stateTransitions[from], to) {
+		return StateUpdateApplied
+	}
+
+	if stateRanks[to] < stateRanks[from] {
+		return StateUpdateIgnored
+	}
+
+	return StateUpdateRejected
+}
+
+// checkMessageTransition classifies the transition of a message using its state history.
+// An allowed transition is still ignored when the new state has been reached
+// before the current one according to the reported timestamps.
+func checkMessageTransition(
+	from ProcessingState,
+	history map[ProcessingState]time.Time,
+	to ProcessingState,
+	updates map[string]time.Time,
+) StateUpdateResult {
+	result := checkTransition(from, to)
+	if result != StateUpdateApplied || from == to {
+		return result
+	}
+
+	fromAt, ok := history[from]
+	if !ok {
+		return result
+	}
+
+	toAt, ok := updates[string(to)]
+	if !ok {
+		return result
+	}
+
+	if toAt.Before(fromAt) {
+		return StateUpdateIgnored
+	}
+
+	return result
+}
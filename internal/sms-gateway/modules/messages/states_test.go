@@ -0,0 +1,81 @@
+//nolint:testpackage // transition helpers are unexported; in-package test required.
+package messages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		name string
+		from ProcessingState
+		to   ProcessingState
+		want StateUpdateResult
+	}{
+		{"pending to processed", ProcessingStatePending, ProcessingStateProcessed, StateUpdateApplied},
+		{"processed to sent", ProcessingStateProcessed, ProcessingStateSent, StateUpdateApplied},
+		{"sent to delivered", ProcessingStateSent, ProcessingStateDelivered, StateUpdateApplied},
+		{"sent to failed", ProcessingStateSent, ProcessingStateFailed, StateUpdateApplied},
+		{"cancelling to cancelled", ProcessingStateCancelling, ProcessingStateCancelled, StateUpdateApplied},
+		{"cancelling to processed", ProcessingStateCancelling, ProcessingStateProcessed, StateUpdateApplied},
+		{"same state", ProcessingStateDelivered, ProcessingStateDelivered, StateUpdateApplied},
+		{"delayed sent after delivered", ProcessingStateDelivered, ProcessingStateSent, StateUpdateIgnored},
+		{"failed back to processed", ProcessingStateFailed, ProcessingStateProcessed, StateUpdateIgnored},
+		{"delivered to failed", ProcessingStateDelivered, ProcessingStateFailed, StateUpdateRejected},
+		{"cancelled to delivered", ProcessingStateCancelled, ProcessingStateDelivered, StateUpdateRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, checkTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestCheckMessageTransition(t *testing.T) {
+	now := time.Now()
+	history := map[ProcessingState]time.Time{
+		ProcessingStateProcessed: now,
+	}
+
+	tests := []struct {
+		name    string
+		to      ProcessingState
+		updates map[string]time.Time
+		want    StateUpdateResult
+	}{
+		{
+			name:    "newer timestamp",
+			to:      ProcessingStateSent,
+			updates: map[string]time.Time{string(ProcessingStateSent): now.Add(time.Second)},
+			want:    StateUpdateApplied,
+		},
+		{
+			name:    "older timestamp",
+			to:      ProcessingStateSent,
+			updates: map[string]time.Time{string(ProcessingStateSent): now.Add(-time.Second)},
+			want:    StateUpdateIgnored,
+		},
+		{
+			name:    "no timestamp",
+			to:      ProcessingStateSent,
+			updates: nil,
+			want:    StateUpdateApplied,
+		},
+		{
+			name:    "regression",
+			to:      ProcessingStatePending,
+			updates: nil,
+			want:    StateUpdateIgnored,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, checkMessageTransition(ProcessingStateProcessed, history, tt.to, tt.updates))
+		})
+	}
+}
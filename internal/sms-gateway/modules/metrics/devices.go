@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Device telemetry metric constants.
+const (
+	metricsNamespace       = "sms"
+	metricsSubsystemDevice = "device"
+
+	MetricBatteryLevel     = "battery_level_percent"
+	MetricSignalLevel      = "signal_level"
+	MetricSignalStrength   = "signal_strength_dbm"
+	MetricFreeStorage      = "free_storage_bytes"
+	MetricQueueLength      = "queue_length"
+	MetricTelemetryReports = "telemetry_reports_total"
+
+	MetricLatestBatteryLevel   = "latest_battery_level_percent"
+	MetricLatestSignalStrength = "latest_signal_strength_dbm"
+	MetricLatestFreeStorage    = "latest_free_storage_bytes"
+	MetricLatestQueueLength    = "latest_queue_length"
+
+	LabelCharging = "charging"
+	LabelDeviceID = "device_id"
+	LabelSim      = "sim"
+
+	ChargingUnknown = "unknown"
+)
+
+// DeviceMetrics aggregates the telemetry reported by the devices.
+//
+// The histograms are fleet-wide distributions of the reports. The latest
+// values of the devices are exported as gauges labeled by the device ID for
+// alerting when enabled in the telemetry config: there is one series per
+// device and SIM that reported telemetry since the start of the replica.
+type DeviceMetrics struct {
+	batteryLevel   prometheus.Histogram
+	signalLevel    prometheus.Histogram
+	signalStrength prometheus.Histogram
+	freeStorage    prometheus.Histogram
+	queueLength    prometheus.Histogram
+	reports        *prometheus.CounterVec
+
+	latestBatteryLevel   *prometheus.GaugeVec
+	latestSignalStrength *prometheus.GaugeVec
+	latestFreeStorage    *prometheus.GaugeVec
+	latestQueueLength    *prometheus.GaugeVec
+}
+
+// NewDeviceMetrics creates and registers the device telemetry metrics.
+func NewDeviceMetrics() *DeviceMetrics {
+	newHistogram := func(name, help string, buckets []float64) prometheus.Histogram {
+		return promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystemDevice,
+			Name:      name,
+			Help:      help,
+			Buckets:   buckets,
+		})
+	}
+
+	newGauge := func(name, help string, labels ...string) *prometheus.GaugeVec {
+		return promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystemDevice,
+			Name:      name,
+			Help:      help,
+		}, append([]string{LabelDeviceID}, labels...))
+	}
+
+	//nolint:mnd // bucket layouts
+	return &DeviceMetrics{
+		batteryLevel: newHistogram(
+			MetricBatteryLevel, "Battery level reported by the devices",
+			prometheus.LinearBuckets(10, 10, 10),
+		),
+		signalLevel: newHistogram(
+			MetricSignalLevel, "Signal level of the SIMs from 0 to 4",
+			prometheus.LinearBuckets(0, 1, 5),
+		),
+		signalStrength: newHistogram(
+			MetricSignalStrength, "Signal strength of the SIMs in dBm",
+			prometheus.LinearBuckets(-120, 10, 8),
+		),
+		freeStorage: newHistogram(
+			MetricFreeStorage, "Free storage reported by the devices",
+			prometheus.ExponentialBuckets(64<<20, 2, 10),
+		),
+		queueLength: newHistogram(
+			MetricQueueLength, "Messages waiting in the local queues of the devices",
+			prometheus.ExponentialBuckets(1, 4, 8),
+		),
+		reports: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystemDevice,
+			Name:      MetricTelemetryReports,
+			Help:      "Telemetry reports received from the devices",
+		}, []string{LabelCharging}),
+
+		latestBatteryLevel: newGauge(
+			MetricLatestBatteryLevel, "Latest battery level reported by the device",
+		),
+		latestSignalStrength: newGauge(
+			MetricLatestSignalStrength, "Latest signal strength of the SIM in dBm", LabelSim,
+		),
+		latestFreeStorage: newGauge(
+			MetricLatestFreeStorage, "Latest free storage reported by the device",
+		),
+		latestQueueLength: newGauge(
+			MetricLatestQueueLength, "Latest length of the local queue of the device",
+		),
+	}
+}
+
+func (m *DeviceMetrics) ObserveBatteryLevel(level int) {
+	m.batteryLevel.Observe(float64(level))
+}
+
+func (m *DeviceMetrics) ObserveSignalLevel(level int) {
+	m.signalLevel.Observe(float64(level))
+}
+
+func (m *DeviceMetrics) ObserveSignalStrength(dbm int) {
+	m.signalStrength.Observe(float64(dbm))
+}
+
+func (m *DeviceMetrics) ObserveFreeStorage(bytes int64) {
+	m.freeStorage.Observe(float64(bytes))
+}
+
+func (m *DeviceMetrics) ObserveQueueLength(length int) {
+	m.queueLength.Observe(float64(length))
+}
+
+// IncReports counts a report by the charging state of the device.
+func (m *DeviceMetrics) IncReports(charging *bool) {
+	chargingLabel := ChargingUnknown
+	if charging != nil {
+		chargingLabel = strconv.FormatBool(*charging)
+	}
+
+	m.reports.WithLabelValues(chargingLabel).Inc()
+}
+
+func (m *DeviceMetrics) SetBatteryLevel(deviceID string, level int) {
+	m.latestBatteryLevel.WithLabelValues(deviceID).Set(float64(level))
+}
+
+func (m *DeviceMetrics) SetSignalStrength(deviceID string, simNumber, dbm int) {
+	m.latestSignalStrength.WithLabelValues(deviceID, strconv.Itoa(simNumber)).Set(float64(dbm))
+}
+
+func (m *DeviceMetrics) SetFreeStorage(deviceID string, bytes int64) {
+	m.latestFreeStorage.WithLabelValues(deviceID).Set(float64(bytes))
+}
+
+func (m *DeviceMetrics) SetQueueLength(deviceID string, length int) {
+	m.latestQueueLength.WithLabelValues(deviceID).Set(float64(length))
+}
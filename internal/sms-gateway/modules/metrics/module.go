@@ -15,5 +15,6 @@ func Module() fx.Option {
 		fx.Provide(
 			http.AsRootHandler(newHTTPHandler),
 		),
+		fx.Provide(NewDeviceMetrics),
 	)
 }
@@ -2,6 +2,7 @@ package push
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -88,6 +89,20 @@ func (s *Service) Run(ctx context.Context) {
 	}
 }
 
+// IsBlacklisted reports whether the events for the token are dropped after
+// repeated delivery failures.
+func (s *Service) IsBlacklisted(ctx context.Context, token string) (bool, error) {
+	_, err := s.blacklist.Get(ctx, token)
+	if errors.Is(err, cache.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get blacklist: %w", err)
+	}
+
+	return true, nil
+}
+
 // Enqueue adds the data to the cache and immediately sends all messages if the debounce is 0.
 func (s *Service) Enqueue(token string, event Event) error {
 	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
@@ -0,0 +1,66 @@
+package settings
+
+import "fmt"
+
+// ContentPolicy is the user's own content policy applied to enqueued messages.
+type ContentPolicy struct {
+	Keywords       []string
+	Patterns       []string
+	AllowedDomains []string
+	DeniedDomains  []string
+	MaxLinks       int
+	MaxRecipients  int
+	Action         string // flag, quarantine or reject
+}
+
+// GetContentPolicy returns the content policy of the user or nil if it is not configured.
+// These settings are kept on the server and are not sent to devices.
+func (s *Service) GetContentPolicy(userID string) (*ContentPolicy, error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	section, ok := settings.Settings["content_policy"].(map[string]any)
+	if !ok {
+		return nil, nil //nolint:nilnil // not configured
+	}
+
+	result := new(ContentPolicy)
+
+	fields := map[string]*[]string{
+		"keywords":        &result.Keywords,
+		"patterns":        &result.Patterns,
+		"allowed_domains": &result.AllowedDomains,
+		"denied_domains":  &result.DeniedDomains,
+	}
+	for field, target := range fields {
+		if *target, err = toStrings(section[field]); err != nil {
+			return nil, fmt.Errorf("%w: '%s' %w", ErrInvalidField, field, err)
+		}
+	}
+
+	limits := map[string]*int{
+		"max_links":      &result.MaxLinks,
+		"max_recipients": &result.MaxRecipients,
+	}
+	for field, target := range limits {
+		if section[field] == nil {
+			continue
+		}
+		// JSON numbers are decoded as float64
+		value, isNumber := section[field].(float64)
+		if !isNumber {
+			return nil, fmt.Errorf("%w: '%s' must be a number", ErrInvalidField, field)
+		}
+		*target = int(value)
+	}
+
+	if action, exists := section["action"]; exists && action != nil {
+		if result.Action, ok = action.(string); !ok {
+			return nil, fmt.Errorf("%w: 'action' must be a string", ErrInvalidField)
+		}
+	}
+
+	return result, nil
+}
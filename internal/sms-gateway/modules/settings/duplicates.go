@@ -0,0 +1,42 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+)
+
+// Duplicates is the user's duplicate-send suppression policy.
+type Duplicates struct {
+	Window time.Duration // period during which a repeated message is a duplicate
+	Action string        // reject or merge
+}
+
+// GetDuplicates returns the duplicate-send suppression policy of the user or nil if it is disabled.
+// These settings are kept on the server and are not sent to devices.
+func (s *Service) GetDuplicates(userID string) (*Duplicates, error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	section, ok := settings.Settings["duplicates"].(map[string]any)
+	if !ok {
+		return nil, nil //nolint:nilnil // not configured
+	}
+
+	// JSON numbers are decoded as float64
+	window, _ := section["window_seconds"].(float64)
+	if window <= 0 {
+		return nil, nil //nolint:nilnil // disabled
+	}
+
+	action, ok := section["action"].(string)
+	if section["action"] != nil && !ok {
+		return nil, fmt.Errorf("%w: 'action' must be a string", ErrInvalidField)
+	}
+
+	return &Duplicates{
+		Window: time.Duration(window) * time.Second,
+		Action: action,
+	}, nil
+}
@@ -25,9 +25,36 @@ func NewDeviceSettings(userID string, settings map[string]any) *DeviceSettings {
 	}
 }
 
+// GroupSettings overrides the settings of the devices in a group.
+type GroupSettings struct {
+	models.TimedModel
+
+	UserID   string         `gorm:"primaryKey;not null;type:varchar(32)"`
+	Group    string         `gorm:"primaryKey;not null;type:varchar(32);column:device_group"`
+	Settings map[string]any `gorm:"not null;type:json;serializer:json"`
+
+	User users.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func NewGroupSettings(userID, group string, settings map[string]any) *GroupSettings {
+	//nolint:exhaustruct // partial constructor
+	return &GroupSettings{
+		UserID:   userID,
+		Group:    group,
+		Settings: settings,
+	}
+}
+
+func (*GroupSettings) TableName() string {
+	return "device_group_settings"
+}
+
 func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(new(DeviceSettings)); err != nil {
 		return fmt.Errorf("device_settings migration failed: %w", err)
 	}
+	if err := db.AutoMigrate(new(GroupSettings)); err != nil {
+		return fmt.Errorf("device_group_settings migration failed: %w", err)
+	}
 	return nil
 }
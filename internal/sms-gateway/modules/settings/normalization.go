@@ -0,0 +1,24 @@
+package settings
+
+import "fmt"
+
+// GetTextNormalization returns the default text normalization rules of the user.
+// These settings are kept on the server and are not sent to devices.
+func (s *Service) GetTextNormalization(userID string) ([]string, error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	section, ok := settings.Settings["text_normalization"].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+
+	rules, err := toStrings(section["rules"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: 'rules' %w", ErrInvalidField, err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,73 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errNotStringList = errors.New("must be a list of strings")
+
+// PhoneNumbers are the user's overrides of the server phone number policy.
+// Empty fields mean the server defaults apply.
+type PhoneNumbers struct {
+	DefaultRegion    string
+	AllowedTypes     []string
+	AllowedCountries []string
+	DeniedCountries  []string
+}
+
+// GetPhoneNumbers returns the phone number policy overrides of the user.
+// These settings are kept on the server and are not sent to devices.
+func (s *Service) GetPhoneNumbers(userID string) (*PhoneNumbers, error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	section, ok := settings.Settings["phone_numbers"].(map[string]any)
+	if !ok {
+		return new(PhoneNumbers), nil
+	}
+
+	result := new(PhoneNumbers)
+	if region, exists := section["default_region"]; exists && region != nil {
+		if result.DefaultRegion, ok = region.(string); !ok {
+			return nil, fmt.Errorf("%w: 'default_region' must be a string", ErrInvalidField)
+		}
+	}
+
+	fields := map[string]*[]string{
+		"allowed_types":     &result.AllowedTypes,
+		"allowed_countries": &result.AllowedCountries,
+		"denied_countries":  &result.DeniedCountries,
+	}
+	for field, target := range fields {
+		if *target, err = toStrings(section[field]); err != nil {
+			return nil, fmt.Errorf("%w: '%s' %w", ErrInvalidField, field, err)
+		}
+	}
+
+	return result, nil
+}
+
+func toStrings(value any) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	items, ok := value.([]any)
+	if !ok {
+		return nil, errNotStringList
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		str, isStr := item.(string)
+		if !isStr {
+			return nil, errNotStringList
+		}
+		result = append(result, str)
+	}
+
+	return result, nil
+}
@@ -79,6 +79,92 @@ func (r *repository) ReplaceSettings(settings *DeviceSettings) (*DeviceSettings,
 	return settings, nil
 }
 
+// GetGroupSettings retrieves the settings of a user's device group.
+func (r *repository) GetGroupSettings(userID, group string) (*GroupSettings, error) {
+	settings := new(GroupSettings)
+	err := r.db.Where("user_id = ? AND device_group = ?", userID, group).Limit(1).Find(settings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group settings: %w", err)
+	}
+	if settings.Settings == nil {
+		settings.Settings = map[string]any{}
+	}
+
+	return settings, nil
+}
+
+// SelectGroupSettings retrieves the settings of the user's device groups ordered by group.
+func (r *repository) SelectGroupSettings(userID string, groups []string) ([]GroupSettings, error) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	settings := []GroupSettings{}
+	err := r.db.
+		Where("user_id = ? AND device_group IN ?", userID, groups).
+		Order("device_group").
+		Find(&settings).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to select group settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpdateGroupSettings updates the settings of a user's device group.
+func (r *repository) UpdateGroupSettings(settings *GroupSettings) (*GroupSettings, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		source := new(GroupSettings)
+		if err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Where("user_id = ? AND device_group = ?", settings.UserID, settings.Group).
+			Limit(1).
+			Find(source).
+			Error; err != nil {
+			return err
+		}
+
+		if source.Settings == nil {
+			source.Settings = map[string]any{}
+		}
+
+		var err error
+		settings.Settings, err = appendMap(source.Settings, settings.Settings, rulesGroup)
+		if err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(settings).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update group settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// ReplaceGroupSettings replaces the settings of a user's device group.
+func (r *repository) ReplaceGroupSettings(settings *GroupSettings) (*GroupSettings, error) {
+	if err := r.db.Save(settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to replace group settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// DeleteGroupSettings removes the settings of a user's device group.
+func (r *repository) DeleteGroupSettings(userID, group string) error {
+	err := r.db.
+		Where("user_id = ? AND device_group = ?", userID, group).
+		Delete(new(GroupSettings)).
+		Error
+	if err != nil {
+		return fmt.Errorf("failed to delete group settings: %w", err)
+	}
+
+	return nil
+}
+
 func newRepository(db *gorm.DB) *repository {
 	return &repository{
 		db: db,
@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -79,6 +80,96 @@ func (s *Service) ReplaceSettings(userID string, settings map[string]any) (map[s
 	return filterMap(updated.Settings, rulesPublic)
 }
 
+// GetDeviceSettings returns the settings of a device: the user's settings
+// overridden by the settings of the device groups, in the order of group names.
+func (s *Service) GetDeviceSettings(userID string, groups []string) (map[string]any, error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.settings.SelectGroupSettings(userID, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	result := settings.Settings
+	for _, override := range overrides {
+		if result, err = appendMap(result, override.Settings, rulesGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) GetGroupSettings(userID, group string) (map[string]any, error) {
+	if err := devices.ValidateTag(group); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settings.GetGroupSettings(userID, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterMap(settings.Settings, rulesPublic)
+}
+
+func (s *Service) UpdateGroupSettings(userID, group string, settings map[string]any) (map[string]any, error) {
+	if err := devices.ValidateTag(group); err != nil {
+		return nil, err
+	}
+
+	filtered, err := filterMap(settings, rulesGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.settings.UpdateGroupSettings(NewGroupSettings(userID, group, filtered))
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyDevices(userID)
+
+	return filterMap(updated.Settings, rulesPublic)
+}
+
+func (s *Service) ReplaceGroupSettings(userID, group string, settings map[string]any) (map[string]any, error) {
+	if err := devices.ValidateTag(group); err != nil {
+		return nil, err
+	}
+
+	filtered, err := filterMap(settings, rulesGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.settings.ReplaceGroupSettings(NewGroupSettings(userID, group, filtered))
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyDevices(userID)
+
+	return filterMap(updated.Settings, rulesPublic)
+}
+
+func (s *Service) DeleteGroupSettings(userID, group string) error {
+	if err := devices.ValidateTag(group); err != nil {
+		return err
+	}
+
+	if err := s.settings.DeleteGroupSettings(userID, group); err != nil {
+		return err
+	}
+
+	s.notifyDevices(userID)
+
+	return nil
+}
+
 // notifyDevices asynchronously notifies all the user's devices.
 func (s *Service) notifyDevices(userID string) {
 	go func(userID string) {
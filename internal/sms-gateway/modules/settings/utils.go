@@ -3,6 +3,8 @@ package settings
 import (
 	"errors"
 	"fmt"
+
+	"github.com/samber/lo"
 )
 
 var (
@@ -43,6 +45,28 @@ var (
 		"receiver": map[string]any{
 			"content_provider_enabled": "",
 		},
+		"phone_numbers": map[string]any{
+			"default_region":    "",
+			"allowed_types":     "",
+			"allowed_countries": "",
+			"denied_countries":  "",
+		},
+		"text_normalization": map[string]any{
+			"rules": "",
+		},
+		"duplicates": map[string]any{
+			"window_seconds": "",
+			"action":         "",
+		},
+		"content_policy": map[string]any{
+			"keywords":        "",
+			"patterns":        "",
+			"allowed_domains": "",
+			"denied_domains":  "",
+			"max_links":       "",
+			"max_recipients":  "",
+			"action":          "",
+		},
 	}
 
 	rulesPublic = map[string]any{
@@ -77,6 +101,19 @@ var (
 	}
 )
 
+// rulesGroup lists the settings a device group may override: the ones applied by the devices themselves.
+//
+//nolint:gochecknoglobals // private constants
+var rulesGroup = lo.PickByKeys(rules, []string{
+	"encryption",
+	"messages",
+	"ping",
+	"logs",
+	"webhooks",
+	"gateway",
+	"receiver",
+})
+
 //nolint:nestif,govet // keep as is
 func filterMap(m map[string]any, r map[string]any) (map[string]any, error) {
 	var err error
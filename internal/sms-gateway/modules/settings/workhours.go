@@ -0,0 +1,99 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+)
+
+const workHoursLayout = "15:04"
+
+// WorkHours is the daily window during which devices are allowed to send messages.
+// Start and End are offsets from midnight; a window with Start after End spans midnight.
+type WorkHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Contains reports whether the time falls within the window in the given location.
+func (w WorkHours) Contains(t time.Time, loc *time.Location) bool {
+	return w.contains(sinceMidnight(t.In(loc)))
+}
+
+// Next returns t if it falls within the window, otherwise the next start of the window.
+func (w WorkHours) Next(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	offset := sinceMidnight(local)
+	if w.contains(offset) {
+		return t
+	}
+
+	day := local
+	if offset >= w.Start {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc).Add(w.Start)
+}
+
+func (w WorkHours) contains(offset time.Duration) bool {
+	switch {
+	case w.Start == w.End:
+		return true
+	case w.Start < w.End:
+		return offset >= w.Start && offset < w.End
+	default:
+		return offset >= w.Start || offset < w.End
+	}
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+}
+
+// GetWorkHours returns the work hours of a device in the groups or nil if they
+// are disabled. The user's settings are overridden by the settings of the
+// groups, the same way the device applies them.
+func (s *Service) GetWorkHours(userID string, groups []string) (*WorkHours, error) {
+	settings, err := s.GetDeviceSettings(userID, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWorkHours(settings)
+}
+
+func parseWorkHours(settings map[string]any) (*WorkHours, error) {
+	messages, ok := settings["messages"].(map[string]any)
+	if !ok {
+		return nil, nil //nolint:nilnil // not configured
+	}
+
+	if enabled, _ := messages["work_hours_enabled"].(bool); !enabled {
+		return nil, nil //nolint:nilnil // disabled
+	}
+
+	start, err := parseWorkHour(messages["work_hours_start"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid work_hours_start: %w", err)
+	}
+	end, err := parseWorkHour(messages["work_hours_end"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid work_hours_end: %w", err)
+	}
+
+	return &WorkHours{Start: start, End: end}, nil
+}
+
+func parseWorkHour(value any) (time.Duration, error) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("%w: expected HH:mm string", ErrInvalidField)
+	}
+
+	t, err := time.Parse(workHoursLayout, str)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidField, err)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -3,6 +3,8 @@ package sse
 import (
 	"context"
 
+	appCache "github.com/android-sms-gateway/server/internal/sms-gateway/cache"
+	"github.com/go-core-fx/cachefx/cache"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -17,6 +19,9 @@ func Module() fx.Option {
 			newMetrics,
 			fx.Private,
 		),
+		fx.Provide(func(factory appCache.Factory) (cache.Cache, error) {
+			return factory.New("sse")
+		}, fx.Private),
 		fx.Provide(
 			NewService,
 		),
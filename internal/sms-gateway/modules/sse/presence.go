@@ -0,0 +1,62 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-core-fx/cachefx/cache"
+	"go.uber.org/zap"
+)
+
+const (
+	// presenceTTL bounds how long a device stays connected in the shared cache
+	// if its replica goes away without removing it.
+	presenceTTL     = time.Minute
+	presenceRefresh = presenceTTL / 2
+	presenceTimeout = time.Second
+)
+
+// IsConnected reports whether the device holds an SSE connection to any replica.
+func (s *Service) IsConnected(ctx context.Context, deviceID string) (bool, error) {
+	s.mu.RLock()
+	_, local := s.connections[deviceID]
+	s.mu.RUnlock()
+
+	if local {
+		return true, nil
+	}
+
+	_, err := s.presence.Get(ctx, deviceID)
+	if errors.Is(err, cache.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	return true, nil
+}
+
+// markPresent tells the other replicas that the device is connected.
+func (s *Service) markPresent(deviceID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+
+	if err := s.presence.Set(ctx, deviceID, []byte{}, cache.WithTTL(presenceTTL)); err != nil {
+		s.logger.Warn("failed to set presence", zap.String("device_id", deviceID), zap.Error(err))
+	}
+}
+
+// clearPresent is called when the last local connection of the device is
+// closed. A connection held by another replica marks the device again on its
+// next refresh.
+func (s *Service) clearPresent(deviceID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+
+	if err := s.presence.Delete(ctx, deviceID); err != nil && !errors.Is(err, cache.ErrKeyNotFound) {
+		s.logger.Warn("failed to clear presence", zap.String("device_id", deviceID), zap.Error(err))
+	}
+}
@@ -0,0 +1,38 @@
+//nolint:testpackage // the presence helpers are unexported; in-package test required.
+package sse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/cachefx/cache"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestIsConnected(t *testing.T) {
+	s := NewService(DefaultConfig(), cache.NewMemory(time.Hour), zap.NewNop(), newMetrics())
+
+	connected, err := s.IsConnected(t.Context(), "device")
+	require.NoError(t, err)
+	require.False(t, connected)
+
+	// connected to another replica
+	s.markPresent("device")
+	connected, err = s.IsConnected(t.Context(), "device")
+	require.NoError(t, err)
+	require.True(t, connected)
+
+	s.clearPresent("device")
+	connected, err = s.IsConnected(t.Context(), "device")
+	require.NoError(t, err)
+	require.False(t, connected)
+
+	// connected to this replica
+	conn := s.registerConnection("device")
+	connected, err = s.IsConnected(t.Context(), "device")
+	require.NoError(t, err)
+	require.True(t, connected)
+
+	require.True(t, s.removeConnection("device", conn.id))
+}
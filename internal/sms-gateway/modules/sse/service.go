@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-core-fx/cachefx/cache"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/google/uuid"
@@ -24,6 +25,9 @@ type Service struct {
 	mu          sync.RWMutex
 	connections map[string][]*sseConnection
 
+	// presence is shared by the replicas, see IsConnected
+	presence cache.Cache
+
 	logger  *zap.Logger
 	metrics *metrics
 }
@@ -39,13 +43,15 @@ type eventWrapper struct {
 	data []byte
 }
 
-func NewService(config Config, logger *zap.Logger, metrics *metrics) *Service {
+func NewService(config Config, presence cache.Cache, logger *zap.Logger, metrics *metrics) *Service {
 	return &Service{
 		config: config,
 
 		mu:          sync.RWMutex{},
 		connections: make(map[string][]*sseConnection),
 
+		presence: presence,
+
 		logger:  logger,
 		metrics: metrics,
 	}
@@ -132,7 +138,15 @@ func (s *Service) Handler(deviceID string, c *fiber.Ctx) error {
 
 func (s *Service) handleStream(deviceID string, w *bufio.Writer) {
 	conn := s.registerConnection(deviceID)
-	defer s.removeConnection(deviceID, conn.id)
+	defer func() {
+		if s.removeConnection(deviceID, conn.id) {
+			s.clearPresent(deviceID)
+		}
+	}()
+
+	s.markPresent(deviceID)
+	presenceTicker := time.NewTicker(presenceRefresh)
+	defer presenceTicker.Stop()
 
 	var tickerChan <-chan time.Time
 
@@ -175,6 +189,8 @@ func (s *Service) handleStream(deviceID string, w *bufio.Writer) {
 			}
 			// Count keepalives sent
 			s.metrics.IncrementKeepalivesSent()
+		case <-presenceTicker.C:
+			s.markPresent(deviceID)
 		case <-conn.closeSignal:
 			return
 		}
@@ -220,7 +236,8 @@ func (s *Service) registerConnection(deviceID string) *sseConnection {
 	return conn
 }
 
-func (s *Service) removeConnection(deviceID, connID string) {
+// removeConnection reports whether it was the last connection of the device.
+func (s *Service) removeConnection(deviceID, connID string) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -243,6 +260,9 @@ func (s *Service) removeConnection(deviceID, connID string) {
 
 		if len(s.connections[deviceID]) == 0 {
 			delete(s.connections, deviceID)
+			return true
 		}
 	}
+
+	return false
 }
@@ -0,0 +1,13 @@
+package telemetry
+
+import "time"
+
+type Config struct {
+	// MaxSamples is the number of the latest samples kept per device.
+	MaxSamples int
+	// MinInterval is the minimum time between two samples of a device.
+	MinInterval time.Duration
+	// DeviceGauges exports the latest values of every device as gauges labeled
+	// by the device ID.
+	DeviceGauges bool
+}
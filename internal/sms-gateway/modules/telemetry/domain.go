@@ -0,0 +1,37 @@
+package telemetry
+
+import "time"
+
+// Signal is the signal quality of a SIM.
+type Signal struct {
+	SimNumber int
+	// Level is the signal level from 0 to 4 as shown in the status bar.
+	Level *int
+	// Dbm is the signal strength in dBm.
+	Dbm *int
+}
+
+// Report is the state of a device sent by the app. Unknown values are nil.
+type Report struct {
+	BatteryLevel *int
+	Charging     *bool
+	Signals      []Signal
+	FreeStorage  *int64
+	AppVersion   *string
+	QueueLength  *int
+}
+
+// Sample is a stored report.
+type Sample struct {
+	Report
+
+	DeviceID  string
+	CreatedAt time.Time
+}
+
+// HistoryFilter selects the samples of a device, newest first.
+type HistoryFilter struct {
+	From  time.Time
+	To    time.Time
+	Limit int
+}
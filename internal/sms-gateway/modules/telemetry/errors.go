@@ -0,0 +1,7 @@
+package telemetry
+
+import "errors"
+
+var (
+	ErrTooFrequent = errors.New("telemetry is reported too frequently")
+)
@@ -0,0 +1,96 @@
+package telemetry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/samber/lo"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type sampleModel struct {
+	ID       uint64 `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	DeviceID string `gorm:"not null;type:char(21);index:idx_device_telemetry_device_id_created_at,priority:1"`
+
+	BatteryLevel *uint8                           `gorm:"type:TINYINT UNSIGNED"`
+	Charging     *bool                            `gorm:"type:tinyint(1)"`
+	Signals      datatypes.JSONSlice[signalModel] `gorm:"serializer:json;type:json"`
+	FreeStorage  *int64                           `gorm:"type:BIGINT UNSIGNED"`
+	AppVersion   *string                          `gorm:"type:varchar(32)"`
+	QueueLength  *uint32                          `gorm:"type:INT UNSIGNED"`
+
+	CreatedAt time.Time `gorm:"not null;type:datetime(3);autoCreateTime:false;index:idx_device_telemetry_device_id_created_at,priority:2"`
+
+	Device devices.DeviceModel `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+}
+
+func newSampleModel(deviceID string, report Report, createdAt time.Time) *sampleModel {
+	//nolint:exhaustruct // the device is not needed for insert
+	return &sampleModel{
+		DeviceID: deviceID,
+
+		BatteryLevel: toUintPtr[uint8](report.BatteryLevel),
+		Charging:     report.Charging,
+		Signals: lo.Map(
+			report.Signals,
+			func(item Signal, _ int) signalModel { return signalModel(item) },
+		),
+		FreeStorage: report.FreeStorage,
+		AppVersion:  report.AppVersion,
+		QueueLength: toUintPtr[uint32](report.QueueLength),
+
+		CreatedAt: createdAt,
+	}
+}
+
+func (*sampleModel) TableName() string {
+	return "device_telemetry"
+}
+
+func (m *sampleModel) toDomain() Sample {
+	return Sample{
+		Report: Report{
+			BatteryLevel: fromUintPtr(m.BatteryLevel),
+			Charging:     m.Charging,
+			Signals: lo.Map(
+				m.Signals,
+				func(item signalModel, _ int) Signal { return Signal(item) },
+			),
+			FreeStorage: m.FreeStorage,
+			AppVersion:  m.AppVersion,
+			QueueLength: fromUintPtr(m.QueueLength),
+		},
+
+		DeviceID:  m.DeviceID,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+type signalModel struct {
+	SimNumber int  `json:"simNumber"`
+	Level     *int `json:"level,omitempty"`
+	Dbm       *int `json:"dbm,omitempty"`
+}
+
+func toUintPtr[T uint8 | uint32](v *int) *T {
+	if v == nil {
+		return nil
+	}
+	return lo.ToPtr(T(*v)) //nolint:gosec // validated by the handler
+}
+
+func fromUintPtr[T uint8 | uint32](v *T) *int {
+	if v == nil {
+		return nil
+	}
+	return lo.ToPtr(int(*v))
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(new(sampleModel)); err != nil {
+		return fmt.Errorf("telemetry migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+//nolint:testpackage // the model is unexported; in-package test required.
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleModel(t *testing.T) {
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		report Report
+	}{
+		{
+			name: "full",
+			report: Report{
+				BatteryLevel: lo.ToPtr(87),
+				Charging:     lo.ToPtr(true),
+				Signals: []Signal{
+					{SimNumber: 1, Level: lo.ToPtr(3), Dbm: lo.ToPtr(-85)},
+					{SimNumber: 2, Level: nil, Dbm: lo.ToPtr(-110)},
+				},
+				FreeStorage: lo.ToPtr(int64(8 << 30)),
+				AppVersion:  lo.ToPtr("1.40.0"),
+				QueueLength: lo.ToPtr(5),
+			},
+		},
+		{
+			name: "empty",
+			report: Report{
+				BatteryLevel: nil,
+				Charging:     nil,
+				Signals:      []Signal{},
+				FreeStorage:  nil,
+				AppVersion:   nil,
+				QueueLength:  nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample := newSampleModel("device", tt.report, createdAt).toDomain()
+
+			require.Equal(t, "device", sample.DeviceID)
+			require.Equal(t, createdAt, sample.CreatedAt)
+			require.Equal(t, tt.report, sample.Report)
+		})
+	}
+}
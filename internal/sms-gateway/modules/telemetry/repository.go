@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// trimQuery deletes the samples of a device older than the newest ones kept.
+// The subquery is wrapped in a derived table as MySQL can't select from the
+// table being deleted from.
+const trimQuery = "DELETE FROM device_telemetry WHERE device_id = ? AND id <= (" +
+	"SELECT id FROM (SELECT id FROM device_telemetry WHERE device_id = ? ORDER BY id DESC LIMIT 1 OFFSET ?) t)"
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// insert adds the sample and keeps only the newest maxSamples of the device.
+func (r *Repository) insert(ctx context.Context, sample *sampleModel, maxSamples int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Device").Create(sample).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(trimQuery, sample.DeviceID, sample.DeviceID, maxSamples).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert telemetry: %w", err)
+	}
+
+	return nil
+}
+
+// lastReportedAt returns the time of the newest sample of the device or the
+// zero time if there is none.
+func (r *Repository) lastReportedAt(ctx context.Context, deviceID string) (time.Time, error) {
+	sample := new(sampleModel)
+	err := r.db.WithContext(ctx).
+		Select("created_at").
+		Where("device_id = ?", deviceID).
+		Order("id DESC").
+		Take(sample).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last telemetry: %w", err)
+	}
+
+	return sample.CreatedAt, nil
+}
+
+// latest returns the newest sample of each device.
+func (r *Repository) latest(ctx context.Context, deviceIDs []string) ([]sampleModel, error) {
+	samples := []sampleModel{}
+	if len(deviceIDs) == 0 {
+		return samples, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where(
+			"id IN (SELECT MAX(id) FROM device_telemetry WHERE device_id IN ? GROUP BY device_id)",
+			deviceIDs,
+		).
+		Find(&samples).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to select latest telemetry: %w", err)
+	}
+
+	return samples, nil
+}
+
+func (r *Repository) history(ctx context.Context, deviceID string, filter HistoryFilter) ([]sampleModel, error) {
+	query := r.db.WithContext(ctx).Where("device_id = ?", deviceID)
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	samples := []sampleModel{}
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&samples).Error; err != nil {
+		return nil, fmt.Errorf("failed to select telemetry: %w", err)
+	}
+
+	return samples, nil
+}
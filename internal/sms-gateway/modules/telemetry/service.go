@@ -0,0 +1,144 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/metrics"
+	"github.com/samber/lo"
+)
+
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+)
+
+type Service struct {
+	config Config
+
+	telemetry  *Repository
+	devicesSvc *devices.Service
+	metrics    *metrics.DeviceMetrics
+}
+
+func NewService(
+	config Config,
+	telemetry *Repository,
+	devicesSvc *devices.Service,
+	metrics *metrics.DeviceMetrics,
+) *Service {
+	return &Service{
+		config: config,
+
+		telemetry:  telemetry,
+		devicesSvc: devicesSvc,
+		metrics:    metrics,
+	}
+}
+
+// Report stores the report of the device and updates its metrics. Reports sent
+// sooner than the configured interval after the previous one are refused with
+// ErrTooFrequent.
+func (s *Service) Report(ctx context.Context, device devices.Device, report Report) error {
+	now := time.Now()
+
+	if s.config.MinInterval > 0 {
+		last, err := s.telemetry.lastReportedAt(ctx, device.ID)
+		if err != nil {
+			return err
+		}
+		if now.Sub(last) < s.config.MinInterval {
+			return ErrTooFrequent
+		}
+	}
+
+	if err := s.telemetry.insert(ctx, newSampleModel(device.ID, report, now), s.config.MaxSamples); err != nil {
+		return err
+	}
+
+	s.observe(device.ID, report)
+
+	return nil
+}
+
+// Latest returns the newest sample of each of the devices that reported telemetry.
+func (s *Service) Latest(ctx context.Context, deviceIDs []string) (map[string]Sample, error) {
+	samples, err := s.telemetry.latest(ctx, deviceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.SliceToMap(samples, func(item sampleModel) (string, Sample) {
+		return item.DeviceID, item.toDomain()
+	}), nil
+}
+
+// History returns the samples of the user's device, newest first.
+func (s *Service) History(ctx context.Context, userID, deviceID string, filter HistoryFilter) ([]Sample, error) {
+	exists, err := s.devicesSvc.Exists(ctx, userID, devices.WithID(deviceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check device: %w", err)
+	}
+	if !exists {
+		return nil, devices.ErrNotFound
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultHistoryLimit
+	}
+	filter.Limit = min(filter.Limit, maxHistoryLimit)
+
+	samples, err := s.telemetry.history(ctx, deviceID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(samples, func(item sampleModel, _ int) Sample { return item.toDomain() }), nil
+}
+
+func (s *Service) observe(deviceID string, report Report) {
+	if report.BatteryLevel != nil {
+		s.metrics.ObserveBatteryLevel(*report.BatteryLevel)
+	}
+	if report.FreeStorage != nil {
+		s.metrics.ObserveFreeStorage(*report.FreeStorage)
+	}
+	if report.QueueLength != nil {
+		s.metrics.ObserveQueueLength(*report.QueueLength)
+	}
+	for _, signal := range report.Signals {
+		if signal.Level != nil {
+			s.metrics.ObserveSignalLevel(*signal.Level)
+		}
+		if signal.Dbm != nil {
+			s.metrics.ObserveSignalStrength(*signal.Dbm)
+		}
+	}
+
+	s.metrics.IncReports(report.Charging)
+
+	if s.config.DeviceGauges {
+		s.setGauges(deviceID, report)
+	}
+}
+
+// setGauges exports the latest values of the device. Values missing from the
+// report keep their previous value.
+func (s *Service) setGauges(deviceID string, report Report) {
+	if report.BatteryLevel != nil {
+		s.metrics.SetBatteryLevel(deviceID, *report.BatteryLevel)
+	}
+	if report.FreeStorage != nil {
+		s.metrics.SetFreeStorage(deviceID, *report.FreeStorage)
+	}
+	if report.QueueLength != nil {
+		s.metrics.SetQueueLength(deviceID, *report.QueueLength)
+	}
+	for _, signal := range report.Signals {
+		if signal.Dbm != nil {
+			s.metrics.SetSignalStrength(deviceID, signal.SimNumber, *signal.Dbm)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package transfers
+
+import "github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+
+// WebhooksMode tells what happens to the webhooks scoped to the transferred device.
+type WebhooksMode string
+
+const (
+	// WebhooksMove re-homes the webhooks to the new owner.
+	WebhooksMove WebhooksMode = "move"
+	// WebhooksDrop deletes the webhooks.
+	WebhooksDrop WebhooksMode = "drop"
+)
+
+// Transfer is the result of a device transfer.
+type Transfer struct {
+	// Device with the new owner
+	Device devices.Device
+	// Previous owner
+	FromUserID string
+	// What happened to the device webhooks
+	WebhooksMode WebhooksMode
+	// Number of the webhooks moved or dropped
+	Webhooks int64
+}
@@ -0,0 +1,14 @@
+package transfers
+
+import "errors"
+
+var (
+	ErrUserNotFound    = errors.New("target user not found")
+	ErrWebhookConflict = errors.New("target user already has a webhook with the same ID")
+)
+
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,129 @@
+package transfers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// transfer moves the device of fromUserID to toUserID together with its
+// webhooks and incidents in one transaction. Messages reference the device
+// only, so they follow it. The devices of toUserID are counted under the lock
+// of the user's row, so concurrent transfers and registrations can't exceed
+// maxDevices, 0 for unlimited. It returns the number of the webhooks moved or
+// dropped.
+func (r *Repository) transfer(
+	ctx context.Context,
+	deviceID, fromUserID, toUserID string,
+	mode WebhooksMode,
+	maxDevices int,
+) (int64, error) {
+	var webhooks int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if maxDevices > 0 {
+			if err := checkDevicesLimit(tx, toUserID, maxDevices); err != nil {
+				return err
+			}
+		}
+
+		res := tx.Model((*devices.DeviceModel)(nil)).
+			Where("id = ? AND user_id = ?", deviceID, fromUserID).
+			Update("user_id", toUserID)
+		if res.Error != nil {
+			return fmt.Errorf("failed to update device: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			// removed or transferred concurrently
+			return devices.ErrNotFound
+		}
+
+		var err error
+		if webhooks, err = transferWebhooks(tx, deviceID, toUserID, mode); err != nil {
+			return err
+		}
+
+		if incErr := tx.Table("device_incidents").
+			Where("device_id = ?", deviceID).
+			Update("user_id", toUserID).Error; incErr != nil {
+			return fmt.Errorf("failed to update incidents: %w", incErr)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to transfer device: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// checkDevicesLimit locks the user's row and fails if the user already has
+// maxDevices devices.
+func checkDevicesLimit(tx *gorm.DB, userID string, maxDevices int) error {
+	var locked []string
+	if err := tx.Table("users").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userID).
+		Pluck("id", &locked).Error; err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	if len(locked) == 0 {
+		return ErrUserNotFound
+	}
+
+	var count int64
+	if err := tx.Model((*devices.DeviceModel)(nil)).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count devices: %w", err)
+	}
+	if count >= int64(maxDevices) {
+		return ValidationError(fmt.Sprintf("user already has the maximum of %d devices", maxDevices))
+	}
+
+	return nil
+}
+
+// transferWebhooks moves or drops the webhooks scoped to the device. The
+// webhooks model doesn't allow updating the owner, hence the plain table.
+func transferWebhooks(tx *gorm.DB, deviceID, toUserID string, mode WebhooksMode) (int64, error) {
+	if mode == WebhooksDrop {
+		res := tx.Exec("DELETE FROM webhooks WHERE device_id = ?", deviceID)
+		if res.Error != nil {
+			return 0, fmt.Errorf("failed to delete webhooks: %w", res.Error)
+		}
+
+		return res.RowsAffected, nil
+	}
+
+	var conflicts int64
+	if err := tx.Table("webhooks AS w").
+		Joins("JOIN webhooks AS o ON o.ext_id = w.ext_id AND o.user_id = ?", toUserID).
+		Where("w.device_id = ?", deviceID).
+		Count(&conflicts).Error; err != nil {
+		return 0, fmt.Errorf("failed to check webhooks: %w", err)
+	}
+	if conflicts > 0 {
+		return 0, ErrWebhookConflict
+	}
+
+	res := tx.Table("webhooks").Where("device_id = ?", deviceID).Update("user_id", toUserID)
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to update webhooks: %w", res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
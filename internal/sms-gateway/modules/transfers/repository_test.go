@@ -0,0 +1,119 @@
+//nolint:testpackage // the repository methods are unexported; in-package test required.
+package transfers
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB creates the columns of the tables touched by a transfer. The
+// MySQL schema uses enums, so the models can't be migrated on SQLite.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	//nolint:exhaustruct // defaults
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// every connection opens its own in-memory database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	for _, query := range []string{
+		"CREATE TABLE users (id TEXT PRIMARY KEY)",
+		"INSERT INTO users (id) VALUES ('from'), ('to')",
+		"CREATE TABLE devices (id TEXT PRIMARY KEY, user_id TEXT NOT NULL)",
+		"CREATE TABLE webhooks (id INTEGER PRIMARY KEY, ext_id TEXT NOT NULL, user_id TEXT NOT NULL, device_id TEXT)",
+		"CREATE TABLE device_incidents (id INTEGER PRIMARY KEY, device_id TEXT NOT NULL, user_id TEXT NOT NULL)",
+		"INSERT INTO devices (id, user_id) VALUES ('device', 'from'), ('other', 'from')",
+		`INSERT INTO webhooks (ext_id, user_id, device_id) VALUES
+			('sent', 'from', 'device'), ('delivered', 'from', 'device'), ('received', 'from', NULL), ('failed', 'from', 'other')`,
+		"INSERT INTO device_incidents (device_id, user_id) VALUES ('device', 'from'), ('other', 'from')",
+	} {
+		require.NoError(t, db.Exec(query).Error)
+	}
+
+	return db
+}
+
+func ownerOf(t *testing.T, db *gorm.DB, table, where string, args ...any) []string {
+	t.Helper()
+
+	var owners []string
+	require.NoError(t, db.Table(table).Where(where, args...).Order("id").Pluck("user_id", &owners).Error)
+
+	return owners
+}
+
+func TestRepositoryTransfer(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         WebhooksMode
+		wantWebhooks int64
+		wantRest     []string
+	}{
+		{name: "move", mode: WebhooksMove, wantWebhooks: 2, wantRest: []string{"to", "to"}},
+		{name: "drop", mode: WebhooksDrop, wantWebhooks: 2, wantRest: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+
+			webhooks, err := NewRepository(db).transfer(t.Context(), "device", "from", "to", tt.mode, 0)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantWebhooks, webhooks)
+
+			require.Equal(t, []string{"to"}, ownerOf(t, db, "devices", "id = ?", "device"))
+			require.Equal(t, tt.wantRest, ownerOf(t, db, "webhooks", "device_id = ?", "device"))
+			require.Equal(t, []string{"to"}, ownerOf(t, db, "device_incidents", "device_id = ?", "device"))
+
+			// the rest of the user's data stays
+			require.Equal(t, []string{"from"}, ownerOf(t, db, "devices", "id = ?", "other"))
+			require.Equal(t, []string{"from", "from"}, ownerOf(t, db, "webhooks", "device_id IS NULL OR device_id <> ?", "device"))
+			require.Equal(t, []string{"from"}, ownerOf(t, db, "device_incidents", "device_id = ?", "other"))
+		})
+	}
+}
+
+func TestRepositoryTransferWebhookConflict(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.Exec("INSERT INTO webhooks (ext_id, user_id, device_id) VALUES ('sent', 'to', NULL)").Error)
+
+	_, err := NewRepository(db).transfer(t.Context(), "device", "from", "to", WebhooksMove, 0)
+	require.ErrorIs(t, err, ErrWebhookConflict)
+
+	// the device update is rolled back together with the rest
+	require.Equal(t, []string{"from"}, ownerOf(t, db, "devices", "id = ?", "device"))
+	require.Equal(t, []string{"from", "from"}, ownerOf(t, db, "webhooks", "device_id = ?", "device"))
+	require.Equal(t, []string{"from"}, ownerOf(t, db, "device_incidents", "device_id = ?", "device"))
+}
+
+func TestRepositoryTransferNotOwned(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := NewRepository(db).transfer(t.Context(), "device", "to", "from", WebhooksMove, 0)
+	require.ErrorIs(t, err, devices.ErrNotFound)
+}
+
+func TestRepositoryTransferDevicesLimit(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.Exec("INSERT INTO devices (id, user_id) VALUES ('owned', 'to')").Error)
+
+	_, err := NewRepository(db).transfer(t.Context(), "device", "from", "to", WebhooksMove, 1)
+
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, []string{"from"}, ownerOf(t, db, "devices", "id = ?", "device"))
+
+	_, err = NewRepository(db).transfer(t.Context(), "device", "from", "to", WebhooksMove, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"to"}, ownerOf(t, db, "devices", "id = ?", "device"))
+}
@@ -0,0 +1,149 @@
+package transfers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/users"
+	"go.uber.org/zap"
+)
+
+// forgetTimeout limits dropping the cached message states of a transferred device.
+const forgetTimeout = time.Minute
+
+// devicesService looks up the device and drops it from the caches of the replicas.
+type devicesService interface {
+	GetByID(ctx context.Context, id string) (*devices.Device, error)
+	Invalidate(device devices.Device)
+}
+
+// messagesCache drops the cached message states of the device.
+type messagesCache interface {
+	ForgetDevice(ctx context.Context, deviceID string, userIDs ...string) error
+}
+
+type usersService interface {
+	GetByUsername(username string) (*users.User, error)
+}
+
+type notifier interface {
+	Notify(userID string, deviceID *string, event events.Event) error
+}
+
+type Config struct {
+	MaxDevices int // Max devices per user, 0 for unlimited
+}
+
+type Service struct {
+	config Config
+
+	transfers *Repository
+
+	devicesSvc  devicesService
+	messagesSvc messagesCache
+	usersSvc    usersService
+	eventsSvc   notifier
+
+	logger *zap.Logger
+}
+
+func NewService(
+	config Config,
+	transfers *Repository,
+	devicesSvc *devices.Service,
+	messagesSvc *messages.Service,
+	usersSvc *users.Service,
+	eventsSvc *events.Service,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		config: config,
+
+		transfers: transfers,
+
+		devicesSvc:  devicesSvc,
+		messagesSvc: messagesSvc,
+		usersSvc:    usersSvc,
+		eventsSvc:   eventsSvc,
+
+		logger: logger,
+	}
+}
+
+// Transfer moves the device to another user. Its messages and incidents move
+// with it, the webhooks scoped to it are moved or dropped according to mode.
+// The target user may not exceed the devices limit. The devices of both users
+// reload their settings and webhooks, the cached message states are dropped in
+// the background.
+func (s *Service) Transfer(ctx context.Context, deviceID, toUserID string, mode WebhooksMode) (*Transfer, error) {
+	if mode != WebhooksMove && mode != WebhooksDrop {
+		return nil, ValidationError(fmt.Sprintf("webhooks mode must be %q or %q", WebhooksMove, WebhooksDrop))
+	}
+
+	device, err := s.devicesSvc.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromUserID := device.UserID
+	if fromUserID == toUserID {
+		return nil, ValidationError("device already belongs to the user")
+	}
+
+	if _, userErr := s.usersSvc.GetByUsername(toUserID); errors.Is(userErr, users.ErrNotFound) {
+		return nil, ErrUserNotFound
+	} else if userErr != nil {
+		return nil, fmt.Errorf("failed to get user: %w", userErr)
+	}
+
+	webhooks, err := s.transfers.transfer(ctx, device.ID, fromUserID, toUserID, mode, s.config.MaxDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	device.UserID = toUserID
+	s.devicesSvc.Invalidate(*device)
+
+	s.forgetMessages(device.ID, fromUserID, toUserID)
+
+	s.notifyDevices(fromUserID)
+	s.notifyDevices(toUserID)
+
+	return &Transfer{
+		Device:       *device,
+		FromUserID:   fromUserID,
+		WebhooksMode: mode,
+		Webhooks:     webhooks,
+	}, nil
+}
+
+// forgetMessages asynchronously drops the cached states of the device's
+// messages, so the previous owner can't read them from the cache. The device
+// may have a long history, so the admin request doesn't wait for it.
+func (s *Service) forgetMessages(deviceID string, userIDs ...string) {
+	go func(deviceID string, userIDs []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), forgetTimeout)
+		defer cancel()
+
+		if err := s.messagesSvc.ForgetDevice(ctx, deviceID, userIDs...); err != nil {
+			s.logger.Error("failed to invalidate messages cache", zap.String("device_id", deviceID), zap.Error(err))
+		}
+	}(deviceID, userIDs)
+}
+
+// notifyDevices asynchronously asks all the user's devices to reload their
+// settings and webhooks.
+func (s *Service) notifyDevices(userID string) {
+	go func(userID string) {
+		for _, event := range []events.Event{events.NewSettingsUpdatedEvent(), events.NewWebhooksUpdatedEvent()} {
+			if err := s.eventsSvc.Notify(userID, nil, event); err != nil {
+				s.logger.Error("failed to notify devices", zap.String("user_id", userID), zap.Error(err))
+			}
+		}
+	}(userID)
+}
@@ -0,0 +1,144 @@
+//nolint:testpackage // the service fields are unexported; in-package test required.
+package transfers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/users"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeDevices struct {
+	device      devices.Device
+	invalidated []devices.Device
+}
+
+func (f *fakeDevices) GetByID(_ context.Context, _ string) (*devices.Device, error) {
+	device := f.device
+	return &device, nil
+}
+
+func (f *fakeDevices) Invalidate(device devices.Device) {
+	f.invalidated = append(f.invalidated, device)
+}
+
+type fakeMessages struct {
+	forgotten chan []string
+}
+
+func (f *fakeMessages) ForgetDevice(_ context.Context, deviceID string, userIDs ...string) error {
+	f.forgotten <- append([]string{deviceID}, userIDs...)
+	return nil
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) GetByUsername(username string) (*users.User, error) {
+	//nolint:exhaustruct // only the ID is used
+	return &users.User{ID: username}, nil
+}
+
+type fakeEvents struct {
+	mu       sync.Mutex
+	notified []string
+}
+
+func (f *fakeEvents) Notify(userID string, _ *string, _ events.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.notified = append(f.notified, userID)
+	return nil
+}
+
+type testService struct {
+	*Service
+
+	devices  *fakeDevices
+	messages *fakeMessages
+	events   *fakeEvents
+}
+
+func newTestService(t *testing.T) testService {
+	t.Helper()
+
+	var device devices.Device
+	device.ID = "device"
+	device.UserID = "from"
+	device.AuthToken = "token"
+
+	ts := testService{
+		Service:  nil,
+		devices:  &fakeDevices{device: device, invalidated: nil},
+		messages: &fakeMessages{forgotten: make(chan []string, 1)},
+		events:   &fakeEvents{mu: sync.Mutex{}, notified: nil},
+	}
+	ts.Service = &Service{
+		config:      Config{MaxDevices: 0},
+		transfers:   NewRepository(newTestDB(t)),
+		devicesSvc:  ts.devices,
+		messagesSvc: ts.messages,
+		usersSvc:    fakeUsers{},
+		eventsSvc:   ts.events,
+		logger:      zap.NewNop(),
+	}
+
+	return ts
+}
+
+func TestTransferRejectsUnknownWebhooksMode(t *testing.T) {
+	//nolint:exhaustruct // the mode is validated before any dependency is used
+	s := &Service{}
+
+	_, err := s.Transfer(t.Context(), "device", "user", WebhooksMode("keep"))
+
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestTransferInvalidatesPreviousOwner(t *testing.T) {
+	s := newTestService(t)
+
+	transfer, err := s.Transfer(t.Context(), "device", "to", WebhooksMove)
+	require.NoError(t, err)
+	require.Equal(t, "from", transfer.FromUserID)
+	require.Equal(t, "to", transfer.Device.UserID)
+	require.Equal(t, int64(2), transfer.Webhooks)
+
+	require.Len(t, s.devices.invalidated, 1)
+	require.Equal(t, "to", s.devices.invalidated[0].UserID)
+
+	select {
+	case forgotten := <-s.messages.forgotten:
+		require.Equal(t, []string{"device", "from", "to"}, forgotten)
+	case <-time.After(time.Second):
+		require.Fail(t, "messages cache is not invalidated")
+	}
+
+	require.Eventually(t, func() bool {
+		s.events.mu.Lock()
+		defer s.events.mu.Unlock()
+
+		// settings and webhooks of both users
+		return len(s.events.notified) == 4
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTransferWebhookConflictKeepsState(t *testing.T) {
+	s := newTestService(t)
+	require.NoError(t, s.transfers.db.Exec(
+		"INSERT INTO webhooks (ext_id, user_id, device_id) VALUES ('sent', 'to', NULL)",
+	).Error)
+
+	_, err := s.Transfer(t.Context(), "device", "to", WebhooksMove)
+	require.ErrorIs(t, err, ErrWebhookConflict)
+
+	require.Empty(t, s.devices.invalidated)
+	require.Empty(t, s.messages.forgotten)
+}
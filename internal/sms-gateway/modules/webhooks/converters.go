@@ -4,11 +4,14 @@ import (
 	"github.com/android-sms-gateway/client-go/smsgateway"
 )
 
-func webhookToDTO(model *Webhook) smsgateway.Webhook {
-	return smsgateway.Webhook{
-		ID:       model.ExtID,
-		DeviceID: model.DeviceID,
-		URL:      model.URL,
-		Event:    model.Event,
+func webhookToDTO(model *Webhook) WebhookDTO {
+	return WebhookDTO{
+		Webhook: smsgateway.Webhook{
+			ID:       model.ExtID,
+			DeviceID: model.DeviceID,
+			URL:      model.URL,
+			Event:    model.Event,
+		},
+		DeviceGroup: model.DeviceGroup,
 	}
 }
@@ -0,0 +1,11 @@
+package webhooks
+
+import "github.com/android-sms-gateway/client-go/smsgateway"
+
+// WebhookDTO extends smsgateway.Webhook with the device group scope.
+type WebhookDTO struct {
+	smsgateway.Webhook
+
+	// Device group the webhook is limited to, exclusive with `deviceId`
+	DeviceGroup *string `json:"deviceGroup,omitempty"`
+}
@@ -7,6 +7,7 @@ import (
 
 var (
 	ErrInvalidEvent = errors.New("invalid event")
+	ErrInvalidScope = errors.New("device and device group are mutually exclusive")
 )
 
 type ValidationError struct {
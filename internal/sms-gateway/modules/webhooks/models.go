@@ -17,7 +17,8 @@ type Webhook struct {
 	ExtID  string `json:"id" gorm:"not null;type:varchar(36);uniqueIndex:unq_webhooks_user_extid,priority:2"`
 	UserID string `json:"-"  gorm:"<-:create;not null;type:varchar(32);uniqueIndex:unq_webhooks_user_extid,priority:1"`
 
-	DeviceID *string `json:"device_id,omitempty" gorm:"type:char(21);index:idx_webhooks_device"`
+	DeviceID    *string `json:"device_id,omitempty"    gorm:"type:char(21);index:idx_webhooks_device"`
+	DeviceGroup *string `json:"device_group,omitempty" gorm:"type:varchar(32);index:idx_webhooks_device_group"`
 
 	URL   string                  `json:"url"   validate:"required,http_url" gorm:"not null;type:varchar(256)"`
 	Event smsgateway.WebhookEvent `json:"event"                              gorm:"not null;type:varchar(32)"`
@@ -26,14 +27,22 @@ type Webhook struct {
 	Device *devices.DeviceModel `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
 }
 
-func newWebhook(extID string, url string, event smsgateway.WebhookEvent, userID string, deviceID *string) *Webhook {
+func newWebhook(
+	extID string,
+	url string,
+	event smsgateway.WebhookEvent,
+	userID string,
+	deviceID *string,
+	deviceGroup *string,
+) *Webhook {
 	//nolint:exhaustruct // partial constructor
 	return &Webhook{
-		ExtID:    extID,
-		URL:      url,
-		Event:    event,
-		UserID:   userID,
-		DeviceID: deviceID,
+		ExtID:       extID,
+		URL:         url,
+		Event:       event,
+		UserID:      userID,
+		DeviceID:    deviceID,
+		DeviceGroup: deviceGroup,
 	}
 }
 
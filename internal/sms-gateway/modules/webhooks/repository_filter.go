@@ -16,11 +16,20 @@ func WithUserID(userID string) SelectFilter {
 	}
 }
 
+// WithDeviceGroups creates a SelectFilter that matches the webhooks of any of
+// the groups and the webhooks not limited to a group.
+func WithDeviceGroups(groups []string) SelectFilter {
+	return func(f *selectFilter) {
+		f.deviceGroups = &groups
+	}
+}
+
 type selectFilter struct {
 	userID        string
 	extID         *string
 	deviceID      *string
 	deviceIDExact bool
+	deviceGroups  *[]string
 }
 
 func newFilter(filters ...SelectFilter) *selectFilter {
@@ -57,5 +66,12 @@ func (f *selectFilter) apply(query *gorm.DB) *gorm.DB {
 			query = query.Where("device_id = ? OR device_id IS NULL", *f.deviceID)
 		}
 	}
+	if f.deviceGroups != nil {
+		if len(*f.deviceGroups) == 0 {
+			query = query.Where("device_group IS NULL")
+		} else {
+			query = query.Where("device_group IN ? OR device_group IS NULL", *f.deviceGroups)
+		}
+	}
 	return query
 }
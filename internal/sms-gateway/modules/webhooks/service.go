@@ -51,7 +51,7 @@ func NewService(params ServiceParams) *Service {
 }
 
 // _select retrieves a list of webhooks that match the provided filters.
-func (s *Service) _select(filters ...SelectFilter) ([]smsgateway.Webhook, error) {
+func (s *Service) _select(filters ...SelectFilter) ([]WebhookDTO, error) {
 	items, err := s.webhooks.Select(filters...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to select webhooks: %w", err)
@@ -62,15 +62,16 @@ func (s *Service) _select(filters ...SelectFilter) ([]smsgateway.Webhook, error)
 
 // Select returns a list of webhooks for a specific user that match the provided filters.
 // It ensures that the filter includes the user's ID.
-func (s *Service) Select(userID string, filters ...SelectFilter) ([]smsgateway.Webhook, error) {
+func (s *Service) Select(userID string, filters ...SelectFilter) ([]WebhookDTO, error) {
 	filters = append(filters, WithUserID(userID))
 
 	return s._select(filters...)
 }
 
-// Replace creates or updates a webhook for a given user. After replacing the webhook,
-// it asynchronously notifies all the user's devices. Returns an error if the operation fails.
-func (s *Service) Replace(ctx context.Context, userID string, webhook *smsgateway.Webhook) error {
+// Replace creates or updates a webhook for a given user. A webhook may be limited to
+// a device or to a device group. After replacing the webhook, it asynchronously
+// notifies the affected devices. Returns an error if the operation fails.
+func (s *Service) Replace(ctx context.Context, userID string, webhook *WebhookDTO) error {
 	if !smsgateway.IsValidWebhookEvent(webhook.Event) {
 		return newValidationError("event", webhook.Event, ErrInvalidEvent)
 	}
@@ -90,12 +91,22 @@ func (s *Service) Replace(ctx context.Context, userID string, webhook *smsgatewa
 		}
 	}
 
+	if webhook.DeviceGroup != nil {
+		if webhook.DeviceID != nil {
+			return newValidationError("device_group", *webhook.DeviceGroup, ErrInvalidScope)
+		}
+		if err := devices.ValidateTag(*webhook.DeviceGroup); err != nil {
+			return newValidationError("device_group", *webhook.DeviceGroup, err)
+		}
+	}
+
 	model := newWebhook(
 		webhook.ID,
 		webhook.URL,
 		webhook.Event,
 		userID,
 		webhook.DeviceID,
+		webhook.DeviceGroup,
 	)
 
 	if err := s.webhooks.Replace(model); err != nil {
@@ -22,6 +22,7 @@ const (
 
 	operationSet   = "set"
 	operationDrain = "drain"
+	operationGet   = "get"
 
 	statusSuccess = "success"
 	statusError   = "error"
@@ -2,6 +2,7 @@ package online
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 type Service interface {
 	Run(ctx context.Context) error
 	SetOnline(ctx context.Context, deviceID string)
+	// LastSeen returns the online time not persisted to the device yet or nil.
+	LastSeen(ctx context.Context, deviceID string) (*time.Time, error)
 }
 
 type service struct {
@@ -76,6 +79,25 @@ func (s *service) SetOnline(ctx context.Context, deviceID string) {
 	s.metrics.IncrementStatusSet(true)
 }
 
+func (s *service) LastSeen(ctx context.Context, deviceID string) (*time.Time, error) {
+	data, err := s.cache.Get(ctx, deviceID)
+	if errors.Is(err, cache.ErrKeyNotFound) {
+		return nil, nil //nolint:nilnil //no unflushed online time
+	}
+	if err != nil {
+		s.metrics.IncrementCacheOperation(operationGet, statusError)
+		return nil, fmt.Errorf("failed to get online status: %w", err)
+	}
+	s.metrics.IncrementCacheOperation(operationGet, statusSuccess)
+
+	t, err := time.Parse(time.RFC3339, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse last seen: %w", err)
+	}
+
+	return &t, nil
+}
+
 func (s *service) persist(ctx context.Context) error {
 	var drainErr, persistErr error
 
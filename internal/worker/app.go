@@ -2,7 +2,11 @@ package worker
 
 import (
 	"context"
+	"strings"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/blobs"
+	appdb "github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/pubsub"
 	"github.com/android-sms-gateway/server/internal/worker/config"
 	"github.com/android-sms-gateway/server/internal/worker/executor"
 	"github.com/android-sms-gateway/server/internal/worker/locker"
@@ -22,6 +26,9 @@ func Run() {
 		logger.WithFxDefaultLogger(),
 		config.Module(),
 		db.Module,
+		appdb.Module(),
+		pubsub.Module(),
+		blobs.Module(),
 		fiberfx.Module(),
 		module(),
 	).Run()
@@ -35,6 +42,13 @@ func module() fx.Option {
 		executor.Module(),
 		health.Module(),
 		server.Module(),
+		fx.Invoke(func(config pubsub.Config, logger *zap.Logger) {
+			// the worker publishes events and cache invalidations for the API replicas,
+			// the in-process pubsub delivers them nowhere
+			if config.URL == "" || strings.HasPrefix(config.URL, "memory://") {
+				logger.Warn("pubsub is in memory, devices and API replicas won't receive the worker's updates; use redis://")
+			}
+		}),
 		fx.Invoke(func(logger *zap.Logger, lc fx.Lifecycle) {
 			lc.Append(fx.Hook{
 				OnStart: func(_ context.Context) error {
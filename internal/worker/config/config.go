@@ -10,16 +10,24 @@ type Config struct {
 	Tasks    Tasks           `yaml:"tasks"`
 	Database config.Database `yaml:"database"`
 	HTTP     config.HTTP     `yaml:"http"`
+	PubSub   config.PubSub   `yaml:"pubsub"`
+	Blobs    config.Blobs    `yaml:"blobs"`
+	SMTP     SMTP            `yaml:"smtp"`
 }
 
 type Tasks struct {
-	MessagesHashing MessagesHashing `yaml:"messages_hashing"`
-	MessagesCleanup MessagesCleanup `yaml:"messages_cleanup"`
-	DevicesCleanup  DevicesCleanup  `yaml:"devices_cleanup"`
-	TokensCleanup   TokensCleanup   `yaml:"tokens_cleanup"`
+	MessagesHashing    MessagesHashing    `yaml:"messages_hashing"`
+	MessagesCleanup    MessagesCleanup    `yaml:"messages_cleanup"`
+	MessagesExpiration MessagesExpiration `yaml:"messages_expiration"`
+	MessagesDrip       MessagesDrip       `yaml:"messages_drip"`
+	DevicesCleanup     DevicesCleanup     `yaml:"devices_cleanup"`
+	DevicesOffline     DevicesOffline     `yaml:"devices_offline"`
+	TokensCleanup      TokensCleanup      `yaml:"tokens_cleanup"`
+	CampaignsCleanup   CampaignsCleanup   `yaml:"campaigns_cleanup"`
 }
 type MessagesHashing struct {
-	Interval Duration `yaml:"interval" envconfig:"TASKS__MESSAGES_HASHING__INTERVAL"`
+	Interval          Duration `yaml:"interval"            envconfig:"TASKS__MESSAGES_HASHING__INTERVAL"`
+	AttachmentsMaxAge Duration `yaml:"attachments_max_age" envconfig:"TASKS__MESSAGES_HASHING__ATTACHMENTS_MAX_AGE"`
 }
 
 type MessagesCleanup struct {
@@ -27,35 +35,82 @@ type MessagesCleanup struct {
 	MaxAge   Duration `yaml:"max_age"  envconfig:"TASKS__MESSAGES_CLEANUP__MAX_AGE"`
 }
 
+type MessagesExpiration struct {
+	Interval Duration `yaml:"interval" envconfig:"TASKS__MESSAGES_EXPIRATION__INTERVAL"`
+}
+
+type MessagesDrip struct {
+	Interval Duration `yaml:"interval" envconfig:"TASKS__MESSAGES_DRIP__INTERVAL"`
+}
+
 type DevicesCleanup struct {
 	Interval Duration `yaml:"interval" envconfig:"TASKS__DEVICES_CLEANUP__INTERVAL"`
 	MaxAge   Duration `yaml:"max_age"  envconfig:"TASKS__DEVICES_CLEANUP__MAX_AGE"`
 }
 
+type DevicesOffline struct {
+	Interval        Duration `yaml:"interval"         envconfig:"TASKS__DEVICES_OFFLINE__INTERVAL"`
+	FlapWindow      Duration `yaml:"flap_window"      envconfig:"TASKS__DEVICES_OFFLINE__FLAP_WINDOW"`
+	RetryWindow     Duration `yaml:"retry_window"     envconfig:"TASKS__DEVICES_OFFLINE__RETRY_WINDOW"`
+	MaxAge          Duration `yaml:"max_age"          envconfig:"TASKS__DEVICES_OFFLINE__MAX_AGE"`
+	CallbackTimeout Duration `yaml:"callback_timeout" envconfig:"TASKS__DEVICES_OFFLINE__CALLBACK_TIMEOUT"`
+}
+
 type TokensCleanup struct {
 	Interval Duration `yaml:"interval" envconfig:"TASKS__TOKENS_CLEANUP__INTERVAL"`
 	MaxAge   Duration `yaml:"max_age"  envconfig:"TASKS__TOKENS_CLEANUP__MAX_AGE"`
 }
 
+type SMTP struct {
+	Host     string `yaml:"host"     envconfig:"SMTP__HOST"`     // mail server host, empty to disable emails
+	Port     int    `yaml:"port"     envconfig:"SMTP__PORT"`     // mail server port
+	Username string `yaml:"username" envconfig:"SMTP__USERNAME"` // username, empty for no authentication
+	Password string `yaml:"password" envconfig:"SMTP__PASSWORD"` // password
+	From     string `yaml:"from"     envconfig:"SMTP__FROM"`     // sender address
+}
+
+type CampaignsCleanup struct {
+	Interval Duration `yaml:"interval" envconfig:"TASKS__CAMPAIGNS_CLEANUP__INTERVAL"`
+	MaxAge   Duration `yaml:"max_age"  envconfig:"TASKS__CAMPAIGNS_CLEANUP__MAX_AGE"`
+}
+
 func Default() Config {
 	//nolint:exhaustruct,mnd,goconst // default values
 	return Config{
 		Tasks: Tasks{
 			MessagesHashing: MessagesHashing{
-				Interval: Duration(7 * 24 * time.Hour),
+				Interval:          Duration(7 * 24 * time.Hour),
+				AttachmentsMaxAge: Duration(24 * time.Hour),
 			},
 			MessagesCleanup: MessagesCleanup{
 				Interval: Duration(24 * time.Hour),
 				MaxAge:   Duration(30 * 24 * time.Hour),
 			},
+			MessagesExpiration: MessagesExpiration{
+				Interval: Duration(5 * time.Minute),
+			},
+			MessagesDrip: MessagesDrip{
+				Interval: Duration(30 * time.Second),
+			},
 			DevicesCleanup: DevicesCleanup{
 				Interval: Duration(24 * time.Hour),
 				MaxAge:   Duration(365 * 24 * time.Hour),
 			},
+			DevicesOffline: DevicesOffline{
+				Interval:        Duration(1 * time.Minute),
+				FlapWindow:      Duration(15 * time.Minute),
+				RetryWindow:     Duration(1 * time.Hour),
+				MaxAge:          Duration(30 * 24 * time.Hour),
+				CallbackTimeout: Duration(10 * time.Second),
+			},
 			TokensCleanup: TokensCleanup{
 				Interval: Duration(24 * time.Hour),
 				MaxAge:   Duration(1 * time.Hour),
 			},
+			CampaignsCleanup: CampaignsCleanup{
+				Interval: Duration(24 * time.Hour),
+				MaxAge:   Duration(30 * 24 * time.Hour),
+			},
 		},
 		Database: config.Database{
 			Host:         "localhost",
@@ -72,5 +127,19 @@ func Default() Config {
 			Listen:  "127.0.0.1:3000",
 			Proxies: []string{},
 		},
+		PubSub: config.PubSub{
+			URL:        "memory://",
+			BufferSize: 128,
+		},
+		Blobs: config.Blobs{
+			URL: "file://./data/blobs",
+		},
+		SMTP: SMTP{
+			Host:     "",
+			Port:     25,
+			Username: "",
+			Password: "",
+			From:     "sms-gate@localhost",
+		},
 	}
 }
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/blobs"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/pubsub"
 	"github.com/android-sms-gateway/server/internal/worker/server"
+	"github.com/android-sms-gateway/server/internal/worker/tasks/campaigns"
 	"github.com/android-sms-gateway/server/internal/worker/tasks/devices"
 	"github.com/android-sms-gateway/server/internal/worker/tasks/messages"
 	"github.com/android-sms-gateway/server/internal/worker/tasks/tokens"
@@ -45,12 +48,19 @@ func Module() fx.Option {
 		fx.Provide(func(cfg Config) messages.Config {
 			return messages.Config{
 				Hashing: messages.HashingConfig{
-					Interval: time.Duration(cfg.Tasks.MessagesHashing.Interval),
+					Interval:          time.Duration(cfg.Tasks.MessagesHashing.Interval),
+					AttachmentsMaxAge: time.Duration(cfg.Tasks.MessagesHashing.AttachmentsMaxAge),
 				},
 				Cleanup: messages.CleanupConfig{
 					Interval: time.Duration(cfg.Tasks.MessagesCleanup.Interval),
 					MaxAge:   time.Duration(cfg.Tasks.MessagesCleanup.MaxAge),
 				},
+				Expiration: messages.ExpirationConfig{
+					Interval: time.Duration(cfg.Tasks.MessagesExpiration.Interval),
+				},
+				Drip: messages.DripConfig{
+					Interval: time.Duration(cfg.Tasks.MessagesDrip.Interval),
+				},
 			}
 		}),
 		fx.Provide(func(cfg Config) devices.Config {
@@ -59,6 +69,20 @@ func Module() fx.Option {
 					Interval: time.Duration(cfg.Tasks.DevicesCleanup.Interval),
 					MaxAge:   time.Duration(cfg.Tasks.DevicesCleanup.MaxAge),
 				},
+				Offline: devices.OfflineConfig{
+					Interval:        time.Duration(cfg.Tasks.DevicesOffline.Interval),
+					FlapWindow:      time.Duration(cfg.Tasks.DevicesOffline.FlapWindow),
+					RetryWindow:     time.Duration(cfg.Tasks.DevicesOffline.RetryWindow),
+					MaxAge:          time.Duration(cfg.Tasks.DevicesOffline.MaxAge),
+					CallbackTimeout: time.Duration(cfg.Tasks.DevicesOffline.CallbackTimeout),
+					SMTP: devices.SMTPConfig{
+						Host:     cfg.SMTP.Host,
+						Port:     cfg.SMTP.Port,
+						Username: cfg.SMTP.Username,
+						Password: cfg.SMTP.Password,
+						From:     cfg.SMTP.From,
+					},
+				},
 			}
 		}),
 		fx.Provide(func(cfg Config) tokens.Config {
@@ -69,6 +93,25 @@ func Module() fx.Option {
 				},
 			}
 		}),
+		fx.Provide(func(cfg Config) campaigns.Config {
+			return campaigns.Config{
+				Cleanup: campaigns.CleanupConfig{
+					Interval: time.Duration(cfg.Tasks.CampaignsCleanup.Interval),
+					MaxAge:   time.Duration(cfg.Tasks.CampaignsCleanup.MaxAge),
+				},
+			}
+		}),
+		fx.Provide(func(cfg Config) pubsub.Config {
+			return pubsub.Config{
+				URL:        cfg.PubSub.URL,
+				BufferSize: cfg.PubSub.BufferSize,
+			}
+		}),
+		fx.Provide(func(cfg Config) blobs.Config {
+			return blobs.Config{
+				URL: cfg.Blobs.URL,
+			}
+		}),
 		fx.Provide(func(cfg Config) server.Config {
 			return server.Config{
 				Address: cfg.HTTP.Listen,
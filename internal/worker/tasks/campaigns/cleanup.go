@@ -0,0 +1,57 @@
+package campaigns
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
+	"github.com/android-sms-gateway/server/internal/worker/executor"
+	"go.uber.org/zap"
+)
+
+type cleanupTask struct {
+	config    CleanupConfig
+	campaigns *campaigns.Repository
+
+	logger *zap.Logger
+}
+
+func NewCleanupTask(
+	config CleanupConfig,
+	campaigns *campaigns.Repository,
+	logger *zap.Logger,
+) executor.PeriodicTask {
+	return &cleanupTask{
+		config:    config,
+		campaigns: campaigns,
+
+		logger: logger,
+	}
+}
+
+// Interval implements executor.PeriodicTask.
+func (c *cleanupTask) Interval() time.Duration {
+	return c.config.Interval
+}
+
+// Name implements executor.PeriodicTask.
+func (c *cleanupTask) Name() string {
+	return "campaigns:cleanup"
+}
+
+// Run implements executor.PeriodicTask.
+func (c *cleanupTask) Run(ctx context.Context) error {
+	rows, err := c.campaigns.Cleanup(ctx, time.Now().Add(-c.config.MaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to cleanup campaigns: %w", err)
+	}
+
+	if rows > 0 {
+		c.logger.Info("cleaned up campaigns", zap.Int64("rows", rows))
+	}
+
+	return nil
+}
+
+var _ executor.PeriodicTask = (*cleanupTask)(nil)
@@ -0,0 +1,12 @@
+package campaigns
+
+import "time"
+
+type Config struct {
+	Cleanup CleanupConfig
+}
+
+type CleanupConfig struct {
+	Interval time.Duration
+	MaxAge   time.Duration
+}
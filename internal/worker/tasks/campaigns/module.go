@@ -0,0 +1,22 @@
+package campaigns
+
+import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
+	"github.com/android-sms-gateway/server/internal/worker/executor"
+	"github.com/go-core-fx/logger"
+	"go.uber.org/fx"
+)
+
+func Module() fx.Option {
+	return fx.Module(
+		"campaigns",
+		logger.WithNamedLogger("campaigns"),
+		fx.Provide(func(c Config) CleanupConfig {
+			return c.Cleanup
+		}, fx.Private),
+		fx.Provide(campaigns.NewRepository, fx.Private),
+		fx.Provide(
+			executor.AsWorkerTask(NewCleanupTask),
+		),
+	)
+}
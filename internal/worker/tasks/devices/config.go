@@ -4,9 +4,32 @@ import "time"
 
 type Config struct {
 	Cleanup CleanupConfig
+	Offline OfflineConfig
 }
 
 type CleanupConfig struct {
 	Interval time.Duration
 	MaxAge   time.Duration
 }
+
+type OfflineConfig struct {
+	Interval time.Duration
+	// FlapWindow is how long a device must stay online to be recovered.
+	FlapWindow time.Duration
+	// RetryWindow is how long a failed notification is retried.
+	RetryWindow time.Duration
+	// MaxAge is how long resolved incidents are kept.
+	MaxAge time.Duration
+
+	CallbackTimeout time.Duration
+	SMTP            SMTPConfig
+}
+
+// SMTPConfig is the mail server for the email notifications, disabled without a host.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
@@ -1,6 +1,7 @@
 package devices
 
 import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/worker/executor"
 	"github.com/go-core-fx/logger"
@@ -11,12 +12,14 @@ func Module() fx.Option {
 	return fx.Module(
 		"devices",
 		logger.WithNamedLogger("devices"),
-		fx.Provide(func(c Config) CleanupConfig {
-			return c.Cleanup
+		fx.Provide(func(c Config) (CleanupConfig, OfflineConfig) {
+			return c.Cleanup, c.Offline
 		}, fx.Private),
 		fx.Provide(devices.NewRepository, fx.Private),
+		fx.Provide(alerts.NewRepository, fx.Private),
 		fx.Provide(
 			executor.AsWorkerTask(NewCleanupTask),
+			executor.AsWorkerTask(NewOfflineTask),
 		),
 	)
 }
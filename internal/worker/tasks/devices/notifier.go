@@ -0,0 +1,226 @@
+package devices
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
+	"github.com/samber/lo"
+)
+
+const maxCallbackRedirects = 5
+
+var (
+	ErrEmailDisabled      = errors.New("email notifications are disabled")
+	ErrUnexpectedResponse = errors.New("unexpected response")
+	ErrInsecureCallback   = errors.New("callback URL must use https")
+	ErrForbiddenCallback  = errors.New("callback address is not public")
+)
+
+// callbackPayload is the body of the POST request sent to the callback URL.
+type callbackPayload struct {
+	Event      alerts.NotificationKind `json:"event"`
+	IncidentID uint64                  `json:"incidentId"`
+	DeviceID   string                  `json:"deviceId"`
+	DeviceName *string                 `json:"deviceName,omitempty"`
+	LastSeenAt time.Time               `json:"lastSeenAt"`
+	StartedAt  time.Time               `json:"startedAt"`
+	ResolvedAt *time.Time              `json:"resolvedAt,omitempty"`
+	Flaps      int                     `json:"flaps"`
+}
+
+// notifier delivers the notifications through the channels set by the user.
+type notifier struct {
+	client *http.Client
+	smtp   SMTPConfig
+}
+
+func newNotifier(config OfflineConfig) *notifier {
+	//nolint:exhaustruct // default dialer settings
+	dialer := &net.Dialer{Control: checkCallbackAddress}
+
+	//nolint:exhaustruct // default transport settings
+	transport := &http.Transport{
+		// without a proxy the dialer sees the actual target
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		ForceAttemptHTTP2:   true,
+		TLSHandshakeTimeout: config.CallbackTimeout,
+	}
+
+	//nolint:exhaustruct // default client settings
+	return &notifier{
+		client: &http.Client{
+			Transport:     transport,
+			Timeout:       config.CallbackTimeout,
+			CheckRedirect: checkCallbackRedirect,
+		},
+		smtp: config.SMTP,
+	}
+}
+
+// checkCallbackAddress prevents the callbacks set by users from reaching
+// the server's own network. It is called with the resolved address of every
+// connection, including the ones made to follow redirects.
+func checkCallbackAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenCallback, address)
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenCallback, address)
+	}
+	ip = ip.Unmap()
+
+	// loopback, link-local, multicast and unspecified addresses aren't global unicast
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return fmt.Errorf("%w: %s", ErrForbiddenCallback, ip)
+	}
+
+	return nil
+}
+
+func checkCallbackRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxCallbackRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxCallbackRedirects)
+	}
+
+	if req.URL.Scheme != "https" {
+		return ErrInsecureCallback
+	}
+
+	return nil
+}
+
+// Send delivers the notification to every channel of the user. It reports
+// whether any channel succeeded along with the errors of the failed ones.
+func (n *notifier) Send(ctx context.Context, notification alerts.Notification) (bool, error) {
+	delivered := false
+	var errs []error
+
+	if notification.CallbackURL != nil {
+		if err := n.post(ctx, *notification.CallbackURL, notification); err != nil {
+			errs = append(errs, fmt.Errorf("failed to call callback: %w", err))
+		} else {
+			delivered = true
+		}
+	}
+
+	if notification.Email != nil {
+		if err := n.mail(*notification.Email, notification); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send email: %w", err))
+		} else {
+			delivered = true
+		}
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+func (n *notifier) post(ctx context.Context, url string, notification alerts.Notification) error {
+	incident := notification.Incident
+	payload, err := json.Marshal(callbackPayload{
+		Event:      notification.Kind,
+		IncidentID: incident.ID,
+		DeviceID:   incident.DeviceID,
+		DeviceName: notification.DeviceName,
+		LastSeenAt: incident.LastSeenAt,
+		StartedAt:  incident.StartedAt,
+		ResolvedAt: incident.ResolvedAt,
+		Flaps:      incident.Flaps,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if req.URL.Scheme != "https" {
+		return ErrInsecureCallback
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sms-gate/1.x (server; golang)")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: status code %d", ErrUnexpectedResponse, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (n *notifier) mail(to string, notification alerts.Notification) error {
+	if n.smtp.Host == "" {
+		return ErrEmailDisabled
+	}
+
+	var auth smtp.Auth
+	if n.smtp.Username != "" {
+		auth = smtp.PlainAuth("", n.smtp.Username, n.smtp.Password, n.smtp.Host)
+	}
+
+	subject, body := formatEmail(notification)
+
+	msg := new(bytes.Buffer)
+	msg.WriteString("From: " + n.smtp.From + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(n.smtp.Host, strconv.Itoa(n.smtp.Port))
+	if err := smtp.SendMail(addr, auth, n.smtp.From, []string{to}, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+func formatEmail(notification alerts.Notification) (string, string) {
+	incident := notification.Incident
+	name := lo.CoalesceOrEmpty(lo.FromPtr(notification.DeviceName), incident.DeviceID)
+
+	if notification.Kind == alerts.NotificationOffline {
+		return fmt.Sprintf("Device %s is offline", name),
+			fmt.Sprintf(
+				"The device %s (%s) has not checked in since %s.\n",
+				name, incident.DeviceID, incident.LastSeenAt.UTC().Format(time.RFC3339),
+			)
+	}
+
+	return fmt.Sprintf("Device %s is back online", name),
+		fmt.Sprintf(
+			"The device %s (%s) is back online. It was offline since %s, the connection dropped %d more time(s).\n",
+			name, incident.DeviceID, incident.LastSeenAt.UTC().Format(time.RFC3339), incident.Flaps,
+		)
+}
@@ -0,0 +1,289 @@
+//nolint:testpackage // the notifier is unexported; in-package test required.
+package devices
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStandIn is a minimal SMTP server accepting a single message.
+type smtpStandIn struct {
+	listener net.Listener
+	messages chan string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &smtpStandIn{
+		listener: listener,
+		messages: make(chan string, 1),
+	}
+	go s.serve()
+
+	return s
+}
+
+func (s *smtpStandIn) port(t *testing.T) int {
+	t.Helper()
+
+	_, port, err := net.SplitHostPort(s.listener.Addr().String())
+	require.NoError(t, err)
+
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return p
+}
+
+func (s *smtpStandIn) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch command := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			reply("354 end with <CRLF>.<CRLF>")
+
+			message := new(strings.Builder)
+			for {
+				dataLine, dataErr := reader.ReadString('\n')
+				if dataErr != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				message.WriteString(dataLine)
+			}
+			s.messages <- message.String()
+
+			reply("250 queued")
+		case strings.HasPrefix(command, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func newTestNotification(kind alerts.NotificationKind) alerts.Notification {
+	lastSeen := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	return alerts.Notification{
+		Kind: kind,
+		Incident: alerts.Incident{
+			ID:         42,
+			DeviceID:   "device-id",
+			LastSeenAt: lastSeen,
+			StartedAt:  lastSeen.Add(15 * time.Minute),
+			ReturnedAt: nil,
+			ResolvedAt: nil,
+			Flaps:      2,
+		},
+		UserID:      "user",
+		DeviceName:  lo.ToPtr("Pixel"),
+		CallbackURL: nil,
+		Email:       nil,
+	}
+}
+
+// newTestServerNotifier returns a notifier trusting the test server and allowed to reach it on the loopback.
+func newTestServerNotifier(server *httptest.Server) *notifier {
+	//nolint:exhaustruct // only the timeout is used
+	n := newNotifier(OfflineConfig{CallbackTimeout: time.Second})
+	n.client.Transport = server.Client().Transport
+
+	return n
+}
+
+func TestNotifierCallback(t *testing.T) {
+	payloads := make(chan callbackPayload, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload callbackPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payloads <- payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := newTestServerNotifier(server)
+
+	notification := newTestNotification(alerts.NotificationOffline)
+	notification.CallbackURL = lo.ToPtr(server.URL)
+
+	delivered, err := n.Send(context.Background(), notification)
+	require.NoError(t, err)
+	require.True(t, delivered)
+
+	payload := <-payloads
+	require.Equal(t, alerts.NotificationOffline, payload.Event)
+	require.Equal(t, uint64(42), payload.IncidentID)
+	require.Equal(t, "device-id", payload.DeviceID)
+	require.Equal(t, "Pixel", lo.FromPtr(payload.DeviceName))
+	require.True(t, notification.Incident.LastSeenAt.Equal(payload.LastSeenAt))
+}
+
+func TestNotifierCallbackFailure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := newTestServerNotifier(server)
+
+	notification := newTestNotification(alerts.NotificationOffline)
+	notification.CallbackURL = lo.ToPtr(server.URL)
+
+	delivered, err := n.Send(context.Background(), notification)
+	require.ErrorIs(t, err, ErrUnexpectedResponse)
+	require.False(t, delivered)
+}
+
+func TestNotifierCallbackForbidden(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	//nolint:exhaustruct // only the timeout is used
+	n := newNotifier(OfflineConfig{CallbackTimeout: time.Second})
+
+	tests := []struct {
+		name string
+		url  string
+		err  error
+	}{
+		{"plain http", "http://example.com/callback", ErrInsecureCallback},
+		{"loopback", server.URL, ErrForbiddenCallback},
+		{"private", "https://10.0.0.1/callback", ErrForbiddenCallback},
+		{"link-local", "https://169.254.169.254/latest/meta-data", ErrForbiddenCallback},
+		{"ipv6 loopback", "https://[::1]/callback", ErrForbiddenCallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notification := newTestNotification(alerts.NotificationOffline)
+			notification.CallbackURL = lo.ToPtr(tt.url)
+
+			delivered, err := n.Send(context.Background(), notification)
+			require.ErrorIs(t, err, tt.err)
+			require.False(t, delivered)
+		})
+	}
+}
+
+func TestNotifierCallbackRedirect(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	n := newTestServerNotifier(server)
+
+	notification := newTestNotification(alerts.NotificationOffline)
+	notification.CallbackURL = lo.ToPtr(server.URL)
+
+	delivered, err := n.Send(context.Background(), notification)
+	require.ErrorIs(t, err, ErrInsecureCallback)
+	require.False(t, delivered)
+}
+
+func TestCheckCallbackAddress(t *testing.T) {
+	require.NoError(t, checkCallbackAddress("tcp4", "93.184.216.34:443", nil))
+	require.NoError(t, checkCallbackAddress("tcp6", "[2606:2800:220:1::1]:443", nil))
+
+	for _, address := range []string{
+		"127.0.0.1:443",
+		"0.0.0.0:443",
+		"192.168.1.10:443",
+		"172.16.0.1:443",
+		"[fd00::1]:443",
+		"[fe80::1]:443",
+		"[::ffff:127.0.0.1]:443",
+	} {
+		require.ErrorIs(t, checkCallbackAddress("tcp", address, nil), ErrForbiddenCallback, address)
+	}
+}
+
+func TestNotifierEmail(t *testing.T) {
+	smtpServer := newSMTPStandIn(t)
+
+	//nolint:exhaustruct // no callbacks
+	n := newNotifier(OfflineConfig{
+		SMTP: SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     smtpServer.port(t),
+			Username: "",
+			Password: "",
+			From:     "alerts@example.com",
+		},
+	})
+
+	notification := newTestNotification(alerts.NotificationRecovered)
+	notification.Email = lo.ToPtr("owner@example.com")
+
+	delivered, err := n.Send(context.Background(), notification)
+	require.NoError(t, err)
+	require.True(t, delivered)
+
+	select {
+	case message := <-smtpServer.messages:
+		require.Contains(t, message, "To: owner@example.com\r\n")
+		require.Contains(t, message, "Subject: Device Pixel is back online\r\n")
+		require.Contains(t, message, "The device Pixel (device-id) is back online.")
+	case <-time.After(time.Second):
+		require.FailNow(t, "email was not received")
+	}
+}
+
+func TestNotifierEmailDisabled(t *testing.T) {
+	//nolint:exhaustruct // no mail server
+	n := newNotifier(OfflineConfig{})
+
+	notification := newTestNotification(alerts.NotificationOffline)
+	notification.Email = lo.ToPtr("owner@example.com")
+
+	delivered, err := n.Send(context.Background(), notification)
+	require.ErrorIs(t, err, ErrEmailDisabled)
+	require.False(t, delivered)
+}
@@ -0,0 +1,96 @@
+package devices
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/alerts"
+	"github.com/android-sms-gateway/server/internal/worker/executor"
+	"go.uber.org/zap"
+)
+
+type offlineTask struct {
+	config   OfflineConfig
+	alerts   *alerts.Repository
+	notifier *notifier
+
+	logger *zap.Logger
+}
+
+func NewOfflineTask(
+	config OfflineConfig,
+	alerts *alerts.Repository,
+	logger *zap.Logger,
+) executor.PeriodicTask {
+	return &offlineTask{
+		config:   config,
+		alerts:   alerts,
+		notifier: newNotifier(config),
+
+		logger: logger,
+	}
+}
+
+// Interval implements executor.PeriodicTask.
+func (t *offlineTask) Interval() time.Duration {
+	return t.config.Interval
+}
+
+// Name implements executor.PeriodicTask.
+func (t *offlineTask) Name() string {
+	return "devices:offline"
+}
+
+// Run implements executor.PeriodicTask.
+func (t *offlineTask) Run(ctx context.Context) error {
+	now := time.Now()
+
+	opened, resolved, err := t.alerts.Detect(ctx, now, t.config.FlapWindow)
+	if err != nil {
+		return fmt.Errorf("failed to detect offline devices: %w", err)
+	}
+
+	if opened > 0 || resolved > 0 {
+		t.logger.Info("offline incidents updated", zap.Int64("opened", opened), zap.Int64("resolved", resolved))
+	}
+
+	notifications, err := t.alerts.SelectNotifications(ctx, now.Add(-t.config.RetryWindow))
+	if err != nil {
+		return fmt.Errorf("failed to select notifications: %w", err)
+	}
+
+	for _, notification := range notifications {
+		delivered, sendErr := t.notifier.Send(ctx, notification)
+		if sendErr != nil {
+			t.logger.Warn(
+				"failed to send notification",
+				zap.String("user_id", notification.UserID),
+				zap.String("device_id", notification.Incident.DeviceID),
+				zap.String("kind", string(notification.Kind)),
+				zap.Bool("delivered", delivered),
+				zap.Error(sendErr),
+			)
+		}
+		if !delivered {
+			continue
+		}
+
+		if markErr := t.alerts.MarkSent(ctx, notification, time.Now()); markErr != nil {
+			return fmt.Errorf("failed to mark notification as sent: %w", markErr)
+		}
+	}
+
+	rows, err := t.alerts.Cleanup(ctx, now.Add(-t.config.MaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to cleanup incidents: %w", err)
+	}
+
+	if rows > 0 {
+		t.logger.Info("cleaned up incidents", zap.Int64("rows", rows))
+	}
+
+	return nil
+}
+
+var _ executor.PeriodicTask = (*offlineTask)(nil)
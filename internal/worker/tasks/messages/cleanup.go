@@ -5,14 +5,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/android-sms-gateway/server/internal/worker/executor"
 	"go.uber.org/zap"
 )
 
 type cleanupTask struct {
-	config   CleanupConfig
-	messages *messages.Repository
+	config      CleanupConfig
+	messages    *messages.Repository
+	attachments *attachments.Purger
 
 	logger *zap.Logger
 }
@@ -20,11 +22,13 @@ type cleanupTask struct {
 func NewCleanupTask(
 	config CleanupConfig,
 	messages *messages.Repository,
+	attachments *attachments.Purger,
 	logger *zap.Logger,
 ) executor.PeriodicTask {
 	return &cleanupTask{
-		config:   config,
-		messages: messages,
+		config:      config,
+		messages:    messages,
+		attachments: attachments,
 
 		logger: logger,
 	}
@@ -42,7 +46,8 @@ func (c *cleanupTask) Name() string {
 
 // Run implements executor.PeriodicTask.
 func (c *cleanupTask) Run(ctx context.Context) error {
-	rows, err := c.messages.Cleanup(ctx, time.Now().Add(-c.config.MaxAge))
+	until := time.Now().Add(-c.config.MaxAge)
+	rows, err := c.messages.Cleanup(ctx, until)
 	if err != nil {
 		return fmt.Errorf("failed to cleanup messages: %w", err)
 	}
@@ -51,6 +56,16 @@ func (c *cleanupTask) Run(ctx context.Context) error {
 		c.logger.Info("cleaned up messages", zap.Int64("rows", rows))
 	}
 
+	// encrypted messages are never hashed, so their attachments are kept until the messages are deleted
+	purged, err := c.attachments.Purge(ctx, until)
+	if err != nil {
+		return fmt.Errorf("failed to purge attachments: %w", err)
+	}
+
+	if purged > 0 {
+		c.logger.Info("purged attachments", zap.Int64("rows", purged))
+	}
+
 	return nil
 }
 
@@ -3,15 +3,27 @@ package messages
 import "time"
 
 type Config struct {
-	Hashing HashingConfig
-	Cleanup CleanupConfig
+	Hashing    HashingConfig
+	Cleanup    CleanupConfig
+	Expiration ExpirationConfig
+	Drip       DripConfig
 }
 
 type HashingConfig struct {
 	Interval time.Duration
+	// AttachmentsMaxAge is the time an uploaded attachment is kept while no message with content references it.
+	AttachmentsMaxAge time.Duration
 }
 
 type CleanupConfig struct {
 	Interval time.Duration
 	MaxAge   time.Duration
 }
+
+type ExpirationConfig struct {
+	Interval time.Duration
+}
+
+type DripConfig struct {
+	Interval time.Duration
+}
@@ -0,0 +1,84 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/worker/executor"
+	"go.uber.org/zap"
+)
+
+// dripTask wakes up devices when delivery slots of their paced messages arrive.
+type dripTask struct {
+	config    DripConfig
+	messages  *messages.Repository
+	publisher *events.Publisher
+
+	lastRun time.Time
+
+	logger *zap.Logger
+}
+
+func NewDripTask(
+	config DripConfig,
+	messages *messages.Repository,
+	publisher *events.Publisher,
+	logger *zap.Logger,
+) executor.PeriodicTask {
+	return &dripTask{
+		config:    config,
+		messages:  messages,
+		publisher: publisher,
+
+		lastRun: time.Time{},
+
+		logger: logger,
+	}
+}
+
+// Interval implements executor.PeriodicTask.
+func (d *dripTask) Interval() time.Duration {
+	return d.config.Interval
+}
+
+// Name implements executor.PeriodicTask.
+func (d *dripTask) Name() string {
+	return "messages:drip"
+}
+
+// Run implements executor.PeriodicTask.
+func (d *dripTask) Run(ctx context.Context) error {
+	now := time.Now()
+	from := d.lastRun
+	if from.IsZero() {
+		from = now.Add(-d.config.Interval)
+	}
+
+	due, err := d.messages.SelectSlotsArrived(ctx, from, now)
+	if err != nil {
+		return fmt.Errorf("failed to select devices: %w", err)
+	}
+	d.lastRun = now
+
+	for _, device := range due {
+		if notifyErr := d.publisher.Notify(
+			ctx,
+			device.UserID,
+			&device.DeviceID,
+			events.NewMessageEnqueuedEvent(),
+		); notifyErr != nil {
+			d.logger.Warn("failed to notify device", zap.String("device_id", device.DeviceID), zap.Error(notifyErr))
+		}
+	}
+
+	if len(due) > 0 {
+		d.logger.Info("notified devices about arrived slots", zap.Int("count", len(due)))
+	}
+
+	return nil
+}
+
+var _ executor.PeriodicTask = (*dripTask)(nil)
@@ -0,0 +1,115 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/worker/executor"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+const (
+	expirationBatchSize = 100
+	expirationReason    = "expired on server"
+)
+
+// expirer fails the pending messages that are no longer valid.
+type expirer interface {
+	ExpirePending(ctx context.Context, until time.Time, limit int, reason string) ([]messages.ExpiredMessage, error)
+}
+
+type expirationTask struct {
+	config      ExpirationConfig
+	messages    expirer
+	publisher   *events.Publisher
+	invalidator *messages.CacheInvalidator
+
+	logger *zap.Logger
+}
+
+func NewExpirationTask(
+	config ExpirationConfig,
+	messages *messages.Repository,
+	publisher *events.Publisher,
+	invalidator *messages.CacheInvalidator,
+	logger *zap.Logger,
+) executor.PeriodicTask {
+	return &expirationTask{
+		config:      config,
+		messages:    messages,
+		publisher:   publisher,
+		invalidator: invalidator,
+
+		logger: logger,
+	}
+}
+
+// Interval implements executor.PeriodicTask.
+func (e *expirationTask) Interval() time.Duration {
+	return e.config.Interval
+}
+
+// Name implements executor.PeriodicTask.
+func (e *expirationTask) Name() string {
+	return "messages:expiration"
+}
+
+// Run implements executor.PeriodicTask.
+func (e *expirationTask) Run(ctx context.Context) error {
+	now := time.Now()
+	total := 0
+
+	for {
+		expired, err := e.messages.ExpirePending(ctx, now, expirationBatchSize, expirationReason)
+		if err != nil {
+			return fmt.Errorf("failed to expire messages: %w", err)
+		}
+
+		// the API caches message states, the replicas have to drop the stale ones
+		byUser := lo.GroupByMap(expired, func(message messages.ExpiredMessage) (string, string) {
+			return message.UserID, message.ID
+		})
+		for userID, ids := range byUser {
+			if invErr := e.invalidator.Invalidate(ctx, userID, ids...); invErr != nil {
+				e.logger.Warn(
+					"failed to invalidate expired messages cache",
+					zap.String("user_id", userID),
+					zap.Error(invErr),
+				)
+			}
+		}
+
+		for _, message := range expired {
+			if notifyErr := e.publisher.Notify(
+				ctx,
+				message.UserID,
+				&message.DeviceID,
+				events.NewMessageCancelledEvent(message.ID),
+			); notifyErr != nil {
+				e.logger.Warn(
+					"failed to notify device about expired message",
+					zap.String("message_id", message.ID),
+					zap.String("device_id", message.DeviceID),
+					zap.Error(notifyErr),
+				)
+			}
+		}
+
+		total += len(expired)
+		if len(expired) < expirationBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		e.logger.Info("expired messages", zap.Int("count", total))
+	}
+
+	return nil
+}
+
+var _ executor.PeriodicTask = (*expirationTask)(nil)
@@ -0,0 +1,108 @@
+//nolint:testpackage // expiration task is unexported; in-package test required.
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/pkg/pubsub"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeExpirer struct {
+	batches [][]messages.ExpiredMessage
+	calls   int
+	until   time.Time
+}
+
+func (f *fakeExpirer) ExpirePending(
+	_ context.Context,
+	until time.Time,
+	limit int,
+	_ string,
+) ([]messages.ExpiredMessage, error) {
+	if limit != expirationBatchSize {
+		return nil, fmt.Errorf("unexpected limit %d", limit)
+	}
+
+	f.until = until
+	f.calls++
+	if f.calls > len(f.batches) {
+		return nil, nil
+	}
+
+	return f.batches[f.calls-1], nil
+}
+
+func newExpiredBatch(size int, userID string) []messages.ExpiredMessage {
+	batch := make([]messages.ExpiredMessage, 0, size)
+	for i := range size {
+		batch = append(batch, messages.ExpiredMessage{
+			ID:       fmt.Sprintf("%s-%d", userID, i),
+			DeviceID: "device-" + userID,
+			UserID:   userID,
+		})
+	}
+	return batch
+}
+
+func TestExpirationTaskRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ps := pubsub.NewMemory(pubsub.WithBufferSize(1024))
+	defer ps.Close()
+
+	statesSub, err := ps.Subscribe(ctx, "messages:states")
+	require.NoError(t, err)
+	eventsSub, err := ps.Subscribe(ctx, "events")
+	require.NoError(t, err)
+
+	// a full batch is followed by another query, a short one finishes the run
+	expirer := &fakeExpirer{
+		batches: [][]messages.ExpiredMessage{
+			append(newExpiredBatch(expirationBatchSize-1, "first"), newExpiredBatch(1, "second")...),
+			newExpiredBatch(2, "second"),
+		},
+		calls: 0,
+		until: time.Time{},
+	}
+
+	task := &expirationTask{
+		config:      ExpirationConfig{Interval: time.Minute},
+		messages:    expirer,
+		publisher:   events.NewPublisher(ps),
+		invalidator: messages.NewCacheInvalidator(ps),
+		logger:      zap.NewNop(),
+	}
+
+	startedAt := time.Now()
+	require.NoError(t, task.Run(ctx))
+	require.Equal(t, 2, expirer.calls)
+	require.False(t, expirer.until.Before(startedAt))
+
+	invalidated := map[string]int{}
+	for range 3 {
+		msg := <-statesSub.Receive()
+
+		var payload struct {
+			UserID string   `json:"userId"`
+			IDs    []string `json:"ids"`
+		}
+		require.NoError(t, json.Unmarshal(msg.Data, &payload))
+		invalidated[payload.UserID] += len(payload.IDs)
+	}
+	require.Equal(t, map[string]int{"first": expirationBatchSize - 1, "second": 3}, invalidated)
+
+	for range expirationBatchSize + 2 {
+		msg := <-eventsSub.Receive()
+		require.Contains(t, string(msg.Data), string(smsgateway.PushMessageCancelled))
+	}
+}
@@ -5,14 +5,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/android-sms-gateway/server/internal/worker/executor"
 	"go.uber.org/zap"
 )
 
 type initialHashingTask struct {
-	config   HashingConfig
-	messages *messages.Repository
+	config      HashingConfig
+	messages    *messages.Repository
+	attachments *attachments.Purger
 
 	logger *zap.Logger
 }
@@ -20,11 +22,13 @@ type initialHashingTask struct {
 func NewInitialHashingTask(
 	config HashingConfig,
 	messages *messages.Repository,
+	attachments *attachments.Purger,
 	logger *zap.Logger,
 ) executor.PeriodicTask {
 	return &initialHashingTask{
-		config:   config,
-		messages: messages,
+		config:      config,
+		messages:    messages,
+		attachments: attachments,
 
 		logger: logger,
 	}
@@ -51,6 +55,16 @@ func (i *initialHashingTask) Run(ctx context.Context) error {
 		i.logger.Info("hashed messages", zap.Int64("rows", rows))
 	}
 
+	// the attachments of hashed messages and the unused uploads are not needed anymore
+	purged, err := i.attachments.Purge(ctx, time.Now().Add(-i.config.AttachmentsMaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to purge attachments: %w", err)
+	}
+
+	if purged > 0 {
+		i.logger.Info("purged attachments", zap.Int64("rows", purged))
+	}
+
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package messages
 
 import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/attachments"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/events"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/android-sms-gateway/server/internal/worker/executor"
 	"github.com/go-core-fx/logger"
@@ -11,13 +13,17 @@ func Module() fx.Option {
 	return fx.Module(
 		"messages",
 		logger.WithNamedLogger("messages"),
-		fx.Provide(func(c Config) (HashingConfig, CleanupConfig) {
-			return c.Hashing, c.Cleanup
+		fx.Provide(func(c Config) (HashingConfig, CleanupConfig, ExpirationConfig, DripConfig) {
+			return c.Hashing, c.Cleanup, c.Expiration, c.Drip
 		}, fx.Private),
 		fx.Provide(messages.NewRepository, fx.Private),
+		fx.Provide(attachments.NewRepository, attachments.NewPurger, fx.Private),
+		fx.Provide(events.NewPublisher, messages.NewCacheInvalidator, fx.Private),
 		fx.Provide(
 			executor.AsWorkerTask(NewInitialHashingTask),
 			executor.AsWorkerTask(NewCleanupTask),
+			executor.AsWorkerTask(NewExpirationTask),
+			executor.AsWorkerTask(NewDripTask),
 		),
 	)
 }
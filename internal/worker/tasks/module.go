@@ -1,6 +1,7 @@
 package tasks
 
 import (
+	"github.com/android-sms-gateway/server/internal/worker/tasks/campaigns"
 	"github.com/android-sms-gateway/server/internal/worker/tasks/devices"
 	"github.com/android-sms-gateway/server/internal/worker/tasks/messages"
 	"github.com/android-sms-gateway/server/internal/worker/tasks/tokens"
@@ -15,5 +16,6 @@ func Module() fx.Option {
 		messages.Module(),
 		devices.Module(),
 		tokens.Module(),
+		campaigns.Module(),
 	)
 }
@@ -0,0 +1,27 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid config")
+	ErrNotFound      = errors.New("blob not found")
+	ErrInvalidKey    = errors.New("invalid blob key")
+)
+
+// Store keeps binary objects by key.
+//
+// The methods follow the semantics of S3-compatible object storages: keys are
+// slash-separated paths, objects are written at once and replaced as a whole,
+// and deleting a missing object is not an error.
+type Store interface {
+	// Put stores the object read from r. The size is the exact number of bytes to read.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object for reading. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object.
+	Delete(ctx context.Context, key string) error
+}
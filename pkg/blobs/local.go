@@ -0,0 +1,131 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	localDirPerm  = 0o750
+	localFilePerm = 0o640
+)
+
+// LocalStore keeps the objects as files under the root directory.
+// Directories are created on the first write.
+type LocalStore struct {
+	root string
+}
+
+func NewLocal(root string) (*LocalStore, error) {
+	if root == "" {
+		return nil, fmt.Errorf("%w: root directory is required", ErrInvalidConfig)
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root directory: %w", err)
+	}
+
+	return &LocalStore{
+		root: root,
+	}, nil
+}
+
+// Put implements Store. The object is written to a temporary file first, so
+// readers never see a partially written object.
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, size int64, _ string) error {
+	filename, err := s.filename(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filename)
+	if mkErr := os.MkdirAll(dir, localDirPerm); mkErr != nil {
+		return fmt.Errorf("failed to create directory: %w", mkErr)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	written, err := io.Copy(tmp, io.LimitReader(r, size))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if written != size {
+		return fmt.Errorf("failed to write file: %w", io.ErrUnexpectedEOF)
+	}
+
+	if chErr := os.Chmod(tmp.Name(), localFilePerm); chErr != nil {
+		return fmt.Errorf("failed to set file mode: %w", chErr)
+	}
+
+	if mvErr := os.Rename(tmp.Name(), filename); mvErr != nil {
+		return fmt.Errorf("failed to move file: %w", mvErr)
+	}
+
+	return nil
+}
+
+// Get implements Store.
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	filename, err := s.filename(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Delete implements Store.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	filename, err := s.filename(key)
+	if err != nil {
+		return err
+	}
+
+	if rmErr := os.Remove(filename); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove file: %w", rmErr)
+	}
+
+	return nil
+}
+
+// filename maps the key to a file under the root directory.
+func (s *LocalStore) filename(key string) (string, error) {
+	if key == "" || path.IsAbs(key) || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	for part := range strings.SplitSeq(key, "/") {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, ".upload-") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+var _ Store = (*LocalStore)(nil)
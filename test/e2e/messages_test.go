@@ -3,6 +3,7 @@ package e2e
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -469,3 +470,190 @@ func TestMessages_PostDuplicateMessageID(t *testing.T) {
 		}
 	})
 }
+
+// TestMessages_Expiration relies on the worker expiring messages every second.
+func TestMessages_Expiration(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	authorizedClient := publicUserClient.Clone().SetBasicAuth(credentials.Login, credentials.Password)
+
+	res, err := authorizedClient.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "test",
+			"ttl":          1,
+			"deviceId":     credentials.ID,
+			"phoneNumbers": []string{"+79999999999"},
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 202 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	type expiredState struct {
+		ID         string  `json:"id"`
+		State      string  `json:"state"`
+		Recipients []state `json:"recipients"`
+	}
+
+	var created expiredState
+	if err := json.Unmarshal(res.Body(), &created); err != nil {
+		t.Fatal(err)
+	}
+
+	// the state read here is cached by the API and has to be invalidated by the worker
+	var current expiredState
+	deadline := time.Now().Add(15 * time.Second)
+	for {
+		res, err := authorizedClient.R().Get("messages/" + created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		if err := json.Unmarshal(res.Body(), &current); err != nil {
+			t.Fatal(err)
+		}
+		if current.State != "Pending" || time.Now().After(deadline) {
+			break
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	if current.State != "Failed" {
+		t.Fatalf("expected state %q, got %q", "Failed", current.State)
+	}
+
+	for _, recipient := range current.Recipients {
+		if recipient.State != "Failed" {
+			t.Errorf("expected recipient %s state %q, got %q", recipient.PhoneNumber, "Failed", recipient.State)
+		}
+	}
+}
+
+func TestMessages_Update(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	authorizedClient := publicUserClient.Clone().SetBasicAuth(credentials.Login, credentials.Password)
+
+	type editedState struct {
+		ID     string               `json:"id"`
+		State  string               `json:"state"`
+		States map[string]time.Time `json:"states"`
+		Edits  []struct {
+			Fields   []string  `json:"fields"`
+			EditedAt time.Time `json:"editedAt"`
+		} `json:"edits"`
+	}
+
+	getState := func(t *testing.T, id string) editedState {
+		t.Helper()
+
+		res, err := authorizedClient.R().Get("messages/" + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var state editedState
+		if err := json.Unmarshal(res.Body(), &state); err != nil {
+			t.Fatal(err)
+		}
+		return state
+	}
+
+	res, err := authorizedClient.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "test",
+			"deviceId":     credentials.ID,
+			"phoneNumbers": []string{"+79999999999"},
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 202 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var created editedState
+	if err := json.Unmarshal(res.Body(), &created); err != nil {
+		t.Fatal(err)
+	}
+	enqueued := getState(t, created.ID)
+
+	t.Run("pending message is edited", func(t *testing.T) {
+		res, err := authorizedClient.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"textMessage": map[string]any{"text": "updated"},
+				"priority":    10,
+			}).
+			Patch("messages/" + created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		edited := getState(t, created.ID)
+		if edited.State != "Pending" {
+			t.Errorf("expected state %q, got %q", "Pending", edited.State)
+		}
+		// the state history is not rewritten by the edit
+		if !edited.States["Pending"].Equal(enqueued.States["Pending"]) {
+			t.Errorf("expected Pending at %s, got %s", enqueued.States["Pending"], edited.States["Pending"])
+		}
+		if len(edited.Edits) != 1 {
+			t.Fatalf("expected 1 edit, got %d", len(edited.Edits))
+		}
+		if fields := edited.Edits[0].Fields; len(fields) != 2 || fields[0] != "textMessage" || fields[1] != "priority" {
+			t.Errorf("unexpected edited fields %v", fields)
+		}
+	})
+
+	t.Run("processed message is not edited", func(t *testing.T) {
+		res, err := publicMobileClient.R().
+			SetAuthToken(credentials.Token).
+			SetHeader("Content-Type", "application/json").
+			SetBody([]map[string]any{
+				{
+					"id":    created.ID,
+					"state": "Processed",
+					"recipients": []map[string]any{
+						{"phoneNumber": "+79999999999", "state": "Processed"},
+					},
+				},
+			}).
+			Patch("message")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		res, err = authorizedClient.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"priority": 20}).
+			Patch("messages/" + created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 409 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		if edits := getState(t, created.ID).Edits; len(edits) != 1 {
+			t.Errorf("expected 1 edit, got %d", len(edits))
+		}
+	})
+}
@@ -241,3 +241,95 @@ func TestPublicDeviceRegisterWithCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestPublicDeviceTokenRotation(t *testing.T) {
+	device := mobileDeviceRegister(t, publicMobileClient)
+
+	type rotateTokenResponse struct {
+		Token                  string `json:"token"`
+		PreviousTokenExpiresAt string `json:"previousTokenExpiresAt"`
+	}
+
+	rotate := func(t *testing.T, token string) rotateTokenResponse {
+		t.Helper()
+
+		res, err := publicMobileClient.R().
+			SetHeader("Authorization", "Bearer "+token).
+			Post("device/token")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var resp rotateTokenResponse
+		if err := json.Unmarshal(res.Body(), &resp); err != nil {
+			t.Fatal(err)
+		}
+
+		return resp
+	}
+
+	// authorized returns the ID of the device authorized with the token
+	authorized := func(t *testing.T, token string) string {
+		t.Helper()
+
+		res, err := publicMobileClient.R().
+			SetHeader("Authorization", "Bearer "+token).
+			Get("device")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var resp struct {
+			Device *struct {
+				ID string `json:"id"`
+			} `json:"device"`
+		}
+		if err := json.Unmarshal(res.Body(), &resp); err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Device == nil {
+			return ""
+		}
+
+		return resp.Device.ID
+	}
+
+	rotated := rotate(t, device.Token)
+	if rotated.Token == device.Token {
+		t.Fatal("token was not rotated")
+	}
+
+	t.Run("previous token in grace period", func(t *testing.T) {
+		if id := authorized(t, device.Token); id != device.ID {
+			t.Fatal("expected device", device.ID, "got", id)
+		}
+	})
+
+	t.Run("new token", func(t *testing.T) {
+		if id := authorized(t, rotated.Token); id != device.ID {
+			t.Fatal("expected device", device.ID, "got", id)
+		}
+	})
+
+	t.Run("retry with previous token", func(t *testing.T) {
+		retried := rotate(t, device.Token)
+		if retried.Token != rotated.Token {
+			t.Fatal("expected current token", rotated.Token, "got", retried.Token)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		if id := authorized(t, "123456789"); id != "" {
+			t.Fatal("expected no device, got", id)
+		}
+	})
+}